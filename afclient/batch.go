@@ -0,0 +1,195 @@
+package afclient
+
+import (
+	"context"
+	pb "github.com/airbloc/airframe/proto"
+	"github.com/airbloc/logger"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// MaxBatchSize is the maximum number of objects in a batch.
+const MaxBatchSize = 100
+
+// ObjectKey identifies an object in a batch.
+type ObjectKey struct {
+	Type string
+	ID   string
+}
+
+// BatchGetResult is the result of an object in BatchGet.
+// Err is ErrNotExists if the object does not exist.
+type BatchGetResult struct {
+	Object *Object
+	Err    error
+}
+
+// PutItem is a write of an object in BatchPut.
+type PutItem struct {
+	Type string
+	ID   string
+	Data M
+
+	// ExpectedVersion makes the write fail with ErrConflict unless the current version
+	// of the object is the same with it, like `afclient.WithExpectedVersion` option.
+	ExpectedVersion uint64
+
+	// Private creates the object as private, like `afclient.AsPrivate` option.
+	Private bool
+
+	// ExpiresAt makes the object expire at the time, like `afclient.WithExpiry` option.
+	ExpiresAt time.Time
+}
+
+// BatchPutResult is the result of a write in BatchPut.
+type BatchPutResult struct {
+	*PutResult
+	Err error
+}
+
+// BatchGet returns the objects with given keys in one round trip, in the same order with the keys.
+// A batch can have up to MaxBatchSize objects without duplicates.
+func (c *client) BatchGet(ctx context.Context, keys []ObjectKey) ([]*BatchGetResult, error) {
+	res, err := c.batchGet(ctx, keys, false)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*BatchGetResult, len(res))
+	for i, r := range res {
+		if r.GetError() != nil {
+			results[i] = &BatchGetResult{Err: itemErrorOf(r.GetError())}
+			continue
+		}
+		obj := &Object{
+			Owner:         common.HexToAddress(r.GetObject().GetOwner()),
+			ACL:           aclOf(r.GetObject()),
+			Private:       r.GetObject().GetPrivate(),
+			ExpiresAt:     expiryOf(r.GetObject().GetExpiresAt()),
+			Version:       r.GetObject().GetVersion(),
+			CreatedAt:     time.Unix(0, int64(r.GetObject().GetCreatedAt())),
+			LastUpdatedAt: time.Unix(0, int64(r.GetObject().GetLastUpdatedAt())),
+		}
+		if err := json.UnmarshalFromString(r.GetObject().GetData(), &obj.Data); err != nil {
+			return nil, errors.Wrap(err, "error on unmarshalling data")
+		}
+		results[i] = &BatchGetResult{Object: obj}
+	}
+	return results, nil
+}
+
+// BatchPut creates or updates the objects in one round trip, each of which is signed
+// by the client's key like Put. The current versions of the objects without
+// ExpectedVersion are fetched first in a batch. Each write succeeds or fails
+// independently, and the results are in the same order with the items.
+func (c *client) BatchPut(ctx context.Context, items []PutItem) ([]*BatchPutResult, error) {
+	versions := make([]uint64, len(items))
+	var keys []ObjectKey
+	var indices []int
+	for i, item := range items {
+		if versions[i] = item.ExpectedVersion; versions[i] == 0 {
+			keys = append(keys, ObjectKey{Type: item.Type, ID: item.ID})
+			indices = append(indices, i)
+		}
+	}
+	if len(keys) > 0 {
+		res, err := c.batchGet(ctx, keys, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get current versions")
+		}
+		for i, r := range res {
+			if r.GetError() != nil && codes.Code(r.GetError().GetCode()) != codes.NotFound {
+				return nil, errors.Wrap(itemErrorOf(r.GetError()), "failed to get current versions")
+			}
+			versions[indices[i]] = r.GetObject().GetVersion()
+		}
+	}
+
+	req := &pb.BatchPutRequest{Items: make([]*pb.PutRequest, len(items))}
+	for i, item := range items {
+		hash := objectHash(c.domain, item.Type, item.ID, versions[i]+1, item.Data, item.ExpiresAt)
+		sig, err := crypto.Sign(hash[:], c.key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to sign data")
+		}
+		marshalledData, err := json.MarshalToString(item.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal data into JSON")
+		}
+		req.Items[i] = &pb.PutRequest{
+			Type:      item.Type,
+			Id:        item.ID,
+			Data:      marshalledData,
+			Signature: sig,
+			Private:   item.Private,
+			ExpiresAt: unixNanoOf(item.ExpiresAt),
+
+			ExpectedVersion: item.ExpectedVersion,
+		}
+	}
+
+	c.log.Debug("BatchPut({count} objects) by {owner}", logger.Attrs{
+		"count": len(items),
+		"owner": crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+	})
+
+	res, err := c.api.BatchPutObjects(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	results := make([]*BatchPutResult, len(res.GetResults()))
+	for i, r := range res.GetResults() {
+		if r.GetError() != nil {
+			results[i] = &BatchPutResult{Err: itemErrorOf(r.GetError())}
+			continue
+		}
+		results[i] = &BatchPutResult{PutResult: &PutResult{
+			FeeUsed: r.GetResult().GetFeeUsed(),
+			Created: r.GetResult().GetCreated(),
+			Version: r.GetResult().GetVersion(),
+		}}
+	}
+	return results, nil
+}
+
+func (c *client) batchGet(ctx context.Context, keys []ObjectKey, includeDeleted bool) ([]*pb.BatchGetResult, error) {
+	req := &pb.BatchGetRequest{
+		Keys:           make([]*pb.ObjectKey, len(keys)),
+		IncludeDeleted: includeDeleted,
+	}
+	for i, key := range keys {
+		req.Keys[i] = &pb.ObjectKey{Type: key.Type, Id: key.ID}
+	}
+	res, err := c.api.BatchGetObjects(ctx, req)
+	if err != nil {
+		if status.Code(err) == codes.ResourceExhausted {
+			return nil, exhaustedErrorOf(err)
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	return res.GetResults(), nil
+}
+
+// itemErrorOf returns an error of an item in a batch,
+// which is the same as the error for a single object.
+func itemErrorOf(e *pb.ItemError) error {
+	switch codes.Code(e.GetCode()) {
+	case codes.NotFound:
+		return ErrNotExists
+	case codes.Unauthenticated:
+		return ErrNotAuthorized
+	case codes.PermissionDenied:
+		return ErrReadDenied
+	case codes.Aborted:
+		return ErrConflict
+	case codes.ResourceExhausted:
+		if e.GetRetryAfter() > 0 {
+			return &RateLimitError{RetryAfter: time.Duration(e.GetRetryAfter()) * time.Second}
+		}
+		return ErrQuotaExceeded
+	}
+	return errors.New(e.GetMessage())
+}
@@ -25,6 +25,18 @@ var (
 
 	// ErrNotAuthorized is raised when given signature mismatched with the object owner's one.
 	ErrNotAuthorized = errors.New("you're not authorized to update the object.")
+
+	// ErrReadDenied is raised when the object is private, and the client is neither
+	// the owner nor granted to read it.
+	ErrReadDenied = errors.New("you're not authorized to read the object.")
+
+	// ErrConflict is raised when the object has been updated by others,
+	// so that the version of the object is not the expected one.
+	ErrConflict = errors.New("the object has been updated by others.")
+
+	// ErrQuotaExceeded is raised when the server enforces prepaid quotas,
+	// and the payer of the write cannot afford the fee.
+	ErrQuotaExceeded = errors.New("the prepaid quota has been exhausted.")
 )
 
 // M is a shorthand of `map[string]interface{}`.
@@ -36,63 +48,156 @@ type Object struct {
 	Data  M
 	Owner common.Address
 
+	// ACL has the addresses granted by the owner.
+	ACL ACL
+
+	// Private is true if the object is only readable by the owner and the ACL.
+	Private bool
+
+	// ExpiresAt is the time when the object expires, which is zero if it never expires.
+	ExpiresAt time.Time
+
+	// Version is 1 on creation, and increased by 1 on every update.
+	Version uint64
+
 	// timestamps
 	CreatedAt     time.Time
 	LastUpdatedAt time.Time
 }
 
+// ACL is an access control list of an object. Writers can update the object
+// on behalf of the owner, and readers can read it.
+type ACL struct {
+	Writers []common.Address
+	Readers []common.Address
+}
+
+// Revision is a snapshot of the object right after a write, with the signature of the write.
+// Data of revisions are erased when the object is deleted.
+type Revision struct {
+	Version   uint64
+	Data      M
+	Owner     common.Address
+	Signer    common.Address
+	Signature []byte
+	Timestamp time.Time
+
+	// Deleted is true if the revision is a deletion.
+	Deleted bool
+
+	// Transferred is true if the revision is a transfer of the ownership from the signer to the owner.
+	Transferred bool
+
+	// ExpiresAt is the expiry of the object after the revision, which is zero if it never expires.
+	ExpiresAt time.Time
+}
+
 // PutResult returns
 type PutResult struct {
 	FeeUsed uint64
 	Created bool
+
+	// Version is the version of the object after the write.
+	Version uint64
 }
 
 // Client interacts with given Airframe endpoint through gRPC calls,
 // and provides read-write interfaces for resources registered in Airframe.
+//
+// Writes are signed with `auth.DefaultDomain`. If the server uses another signing domain,
+// dial with the same domain using `afclient.WithDomain`.
 type Client interface {
-	Get(ctx context.Context, typ, id string) (*Object, error)
+	Get(ctx context.Context, typ, id string, options ...GetOption) (*Object, error)
+	History(ctx context.Context, typ, id string) ([]*Revision, error)
 	Query(ctx context.Context, typ string, query M, options ...QueryOption) ([]*Object, error)
-	Put(ctx context.Context, typ, id string, data M) (*PutResult, error)
+	Iterate(ctx context.Context, typ string, query M, options ...QueryOption) *Iterator
+	Put(ctx context.Context, typ, id string, data M, options ...PutOption) (*PutResult, error)
+	Patch(ctx context.Context, typ, id string, patchType PatchType, patch interface{}, options ...PutOption) (*PutResult, error)
+	Delete(ctx context.Context, typ, id string) error
+	SetACL(ctx context.Context, typ, id string, acl ACL, options ...PutOption) (*PutResult, error)
+	SetVisibility(ctx context.Context, typ, id string, private bool, options ...PutOption) (*PutResult, error)
+	TransferOwnership(ctx context.Context, typ, id string, newOwner common.Address, options ...TransferOption) (*PutResult, error)
+	SignTransferAcceptance(ctx context.Context, typ, id string) ([]byte, error)
+	BatchGet(ctx context.Context, keys []ObjectKey) ([]*BatchGetResult, error)
+	BatchPut(ctx context.Context, items []PutItem) ([]*BatchPutResult, error)
+	Transact(ctx context.Context, ops []TxOp) ([]*PutResult, error)
+	SetSchema(ctx context.Context, typ, definition string, options ...PutOption) (*Schema, error)
+	GetSchema(ctx context.Context, typ string, version uint64) (*Schema, error)
+	ListSchemas(ctx context.Context) ([]*Schema, error)
+	Usage(ctx context.Context, owner common.Address) (*Usage, error)
+	Deposit(ctx context.Context, owner common.Address, amount uint64) (*Usage, error)
 }
 
 type client struct {
-	api pb.APIClient
-	key *ecdsa.PrivateKey
+	api    pb.APIClient
+	key    *ecdsa.PrivateKey
+	domain string
 
 	log logger.Logger
 }
 
 // Dial connects to given Airframe endpoint.
-func Dial(addr string, key *ecdsa.PrivateKey) (Client, error) {
+// Calls are authenticated with read tokens signed by given key, to read private objects,
+// and carry the trace context of the caller. See propagateTraceContext.
+func Dial(addr string, key *ecdsa.PrivateKey, options ...DialOption) (Client, error) {
+	opt := dialOptions{domain: auth.DefaultDomain}
+	for _, applyFunc := range options {
+		applyFunc(&opt)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure())
+	conn, err := grpc.DialContext(ctx, addr,
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(&readTokenCredentials{key: key, domain: opt.domain}),
+		grpc.WithUnaryInterceptor(propagateTraceContext))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to connect gRPC server")
 	}
 	return &client{
-		key: key,
-		api: pb.NewAPIClient(conn),
+		key:    key,
+		domain: opt.domain,
+		api:    pb.NewAPIClient(conn),
 	}, nil
 }
 
 // Get returns object with given resource type and ID.
-// ErrNotExists is returned if no matching object is found with given ID.
-func (c *client) Get(ctx context.Context, typ, id string) (*Object, error) {
-	res, err := c.api.GetObject(ctx, &pb.GetRequest{
-		Type: typ,
-		Id:   id,
-	})
+// A past state of the object can be read using `afclient.AtRevision` or `afclient.AtTime` options.
+// ErrNotExists is returned if no matching object is found with given ID,
+// and ErrReadDenied is returned if the object is private and the client cannot read it.
+func (c *client) Get(ctx context.Context, typ, id string, options ...GetOption) (*Object, error) {
+	var opt getOptions
+	for _, applyFunc := range options {
+		applyFunc(&opt)
+	}
+	req := &pb.GetRequest{
+		Type:     typ,
+		Id:       id,
+		Revision: opt.revision,
+	}
+	if !opt.at.IsZero() {
+		req.At = uint64(opt.at.UnixNano())
+	}
+	res, err := c.api.GetObject(ctx, req)
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
+		switch status.Code(err) {
+		case codes.NotFound:
 			return nil, ErrNotExists
+		case codes.PermissionDenied:
+			return nil, ErrReadDenied
+		case codes.ResourceExhausted:
+			return nil, exhaustedErrorOf(err)
 		}
 		return nil, errors.Wrap(err, "failed to call RPC")
 	}
 
 	obj := &Object{
 		Owner:         common.HexToAddress(res.GetOwner()),
+		ACL:           aclOf(res),
+		Private:       res.GetPrivate(),
+		ExpiresAt:     expiryOf(res.GetExpiresAt()),
+		Version:       res.GetVersion(),
 		CreatedAt:     time.Unix(0, int64(res.GetCreatedAt())),
 		LastUpdatedAt: time.Unix(0, int64(res.GetLastUpdatedAt())),
 	}
@@ -102,6 +207,45 @@ func (c *client) Get(ctx context.Context, typ, id string) (*Object, error) {
 	return obj, nil
 }
 
+// History returns every revision of the object including deletions, sorted by version.
+// ErrNotExists is returned if the object has never existed.
+func (c *client) History(ctx context.Context, typ, id string) ([]*Revision, error) {
+	res, err := c.api.GetObjectHistory(ctx, &pb.HistoryRequest{
+		Type: typ,
+		Id:   id,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return nil, ErrNotExists
+		case codes.PermissionDenied:
+			return nil, ErrReadDenied
+		case codes.ResourceExhausted:
+			return nil, exhaustedErrorOf(err)
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+
+	revisions := make([]*Revision, len(res.GetRevisions()))
+	for i, rev := range res.GetRevisions() {
+		revisions[i] = &Revision{
+			Version:   rev.GetVersion(),
+			Owner:     common.HexToAddress(rev.GetOwner()),
+			Signer:    common.HexToAddress(rev.GetSigner()),
+			Signature: rev.GetSignature(),
+			Timestamp: time.Unix(0, int64(rev.GetTimestamp())),
+			Deleted:   rev.GetDeleted(),
+
+			Transferred: rev.GetTransferred(),
+			ExpiresAt:   expiryOf(rev.GetExpiresAt()),
+		}
+		if err := json.UnmarshalFromString(rev.GetData(), &revisions[i].Data); err != nil {
+			return nil, errors.Wrap(err, "error on unmarshalling data")
+		}
+	}
+	return revisions, nil
+}
+
 // Query returns objects matching with given query.
 // You can write the query using Mongo-style expressions. For example:
 //   {
@@ -110,8 +254,15 @@ func (c *client) Get(ctx context.Context, typ, id string) (*Object, error) {
 //     "name": {"contains": "Kim"},
 //   }
 //
+// Conditions can be composed with "$and", "$or" and "$not" operators. For example:
+//   {
+//     "$or": [{"gender": "Male"}, {"age": {"gt": 30}}],
+//   }
+//
 // You can also skip and limit results for paginations, etc.
-// using `afclient.WithSkip` or `afclient.WithLimit` options.
+// using `afclient.WithSkip` or `afclient.WithLimit` options,
+// and sort them using `afclient.WithSort` option.
+// To walk through all the results page by page, use `Iterate` instead.
 func (c *client) Query(ctx context.Context, typ string, query M, options ...QueryOption) ([]*Object, error) {
 	opt := queryOptions{
 		skip:  0,
@@ -120,20 +271,31 @@ func (c *client) Query(ctx context.Context, typ string, query M, options ...Quer
 	for _, applyFunc := range options {
 		applyFunc(&opt)
 	}
+	objects, _, err := c.queryPage(ctx, typ, query, opt)
+	return objects, err
+}
 
+// queryPage queries a page of objects, and returns it with the cursor of the next page.
+func (c *client) queryPage(ctx context.Context, typ string, query M, opt queryOptions) ([]*Object, string, error) {
 	q, err := json.MarshalToString(query)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshal query")
+		return nil, "", errors.Wrap(err, "failed to marshal query")
 	}
 
 	res, err := c.api.QueryObject(ctx, &pb.QueryRequest{
-		Query: q,
-		Type:  typ,
-		Skip:  uint64(opt.skip),
-		Limit: uint64(opt.limit),
+		Query:  q,
+		Type:   typ,
+		Skip:   uint64(opt.skip),
+		Limit:  uint64(opt.limit),
+		Sort:   opt.sort,
+		Order:  string(opt.order),
+		Cursor: opt.cursor,
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to call RPC")
+		if status.Code(err) == codes.ResourceExhausted {
+			return nil, "", exhaustedErrorOf(err)
+		}
+		return nil, "", errors.Wrap(err, "failed to call RPC")
 	}
 
 	results := res.GetResults()
@@ -141,24 +303,53 @@ func (c *client) Query(ctx context.Context, typ string, query M, options ...Quer
 	for i := 0; i < len(results); i++ {
 		objects[i] = &Object{
 			Owner:         common.HexToAddress(results[i].GetOwner()),
+			ACL:           aclOf(results[i]),
+			Private:       results[i].GetPrivate(),
+			ExpiresAt:     expiryOf(results[i].GetExpiresAt()),
+			Version:       results[i].GetVersion(),
 			CreatedAt:     time.Unix(0, int64(results[i].GetCreatedAt())),
 			LastUpdatedAt: time.Unix(0, int64(results[i].GetLastUpdatedAt())),
 		}
 		if err := json.UnmarshalFromString(results[i].GetData(), &objects[i].Data); err != nil {
-			return nil, errors.Wrap(err, "error on unmarshalling data")
+			return nil, "", errors.Wrap(err, "error on unmarshalling data")
 		}
 	}
-	return objects, nil
+	return objects, res.GetNextCursor(), nil
 }
 
-func (c *client) Put(ctx context.Context, typ, id string, data M) (*PutResult, error) {
-	hash := auth.GetObjectHash(typ, id, data)
+// Put creates or updates the object with given data, signed by the client's key.
+// The current version of the object is fetched first, since the signature
+// should be made for the next version of the object.
+// ErrNotAuthorized is returned if the object is owned by others,
+// or the object has been updated by others right before the write.
+//
+// To update the object only if it has not been changed since it is read,
+// use `afclient.WithExpectedVersion` option. ErrConflict is returned
+// if the version of the object is not the expected one.
+//
+// To make the object expire, use `afclient.WithTTL` or `afclient.WithExpiry` option.
+// Since every write sets the expiry, updates without the option make the object never expire.
+func (c *client) Put(ctx context.Context, typ, id string, data M, options ...PutOption) (*PutResult, error) {
+	var opt putOptions
+	for _, applyFunc := range options {
+		applyFunc(&opt)
+	}
+	version := opt.expectedVersion
+	if version == 0 {
+		var err error
+		if version, err = c.latestVersion(ctx, typ, id); err != nil {
+			return nil, errors.Wrap(err, "failed to get current version")
+		}
+	}
+	version++
+	hash := objectHash(c.domain, typ, id, version, data, opt.expiresAt)
 
 	c.log.Debug("Put({type}, {id}) by {owner}", logger.Attrs{
-		"type":  typ,
-		"id":    id,
-		"hash":  hash,
-		"owner": crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+		"type":    typ,
+		"id":      id,
+		"version": version,
+		"hash":    hash,
+		"owner":   crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
 	})
 
 	sig, err := crypto.Sign(hash[:], c.key)
@@ -176,15 +367,389 @@ func (c *client) Put(ctx context.Context, typ, id string, data M) (*PutResult, e
 		Id:        id,
 		Data:      marshalledData,
 		Signature: sig,
+		Private:   opt.private,
+		ExpiresAt: unixNanoOf(opt.expiresAt),
+
+		ExpectedVersion: opt.expectedVersion,
 	})
 	if err != nil {
-		if status.Code(err) == codes.Unauthenticated {
+		switch status.Code(err) {
+		case codes.Unauthenticated:
 			return nil, ErrNotAuthorized
+		case codes.Aborted:
+			return nil, ErrConflict
+		case codes.ResourceExhausted:
+			return nil, exhaustedErrorOf(err)
 		}
 		return nil, errors.Wrap(err, "failed to call RPC")
 	}
 	return &PutResult{
 		FeeUsed: res.GetFeeUsed(),
 		Created: res.GetCreated(),
+		Version: res.GetVersion(),
 	}, nil
 }
+
+// Patch updates a part of the object with given patch document, signed by the client's key.
+// The patch is either a JSON Merge Patch (RFC 7396) object of fields to be replaced with
+// `afclient.MergePatch`, or a JSON Patch (RFC 6902) array of operations with `afclient.JSONPatch`.
+// The patch is signed for the current version of the object, which is fetched first
+// unless `afclient.WithExpectedVersion` option is given. ErrConflict is returned
+// if the object has been updated by others before the patch is applied.
+func (c *client) Patch(ctx context.Context, typ, id string, patchType PatchType, patch interface{}, options ...PutOption) (*PutResult, error) {
+	var opt putOptions
+	for _, applyFunc := range options {
+		applyFunc(&opt)
+	}
+	baseVersion := opt.expectedVersion
+	if baseVersion == 0 {
+		obj, err := c.Get(ctx, typ, id)
+		if err != nil {
+			return nil, err
+		}
+		baseVersion = obj.Version
+	}
+
+	doc, err := json.MarshalToString(patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal patch into JSON")
+	}
+	hash := auth.GetPatchHash(c.domain, typ, id, baseVersion, string(patchType), []byte(doc))
+
+	c.log.Debug("Patch({type}, {id}) by {owner}", logger.Attrs{
+		"type":        typ,
+		"id":          id,
+		"baseVersion": baseVersion,
+		"hash":        hash,
+		"owner":       crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+	})
+
+	sig, err := crypto.Sign(hash[:], c.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign patch")
+	}
+	res, err := c.api.PatchObject(ctx, &pb.PatchRequest{
+		Type:      typ,
+		Id:        id,
+		PatchType: string(patchType),
+		Patch:     doc,
+		Signature: sig,
+
+		ExpectedVersion: baseVersion,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return nil, ErrNotExists
+		case codes.Unauthenticated:
+			return nil, ErrNotAuthorized
+		case codes.Aborted:
+			return nil, ErrConflict
+		case codes.ResourceExhausted:
+			return nil, exhaustedErrorOf(err)
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	return &PutResult{
+		FeeUsed: res.GetFeeUsed(),
+		Created: res.GetCreated(),
+		Version: res.GetVersion(),
+	}, nil
+}
+
+// Delete deletes the object, signed by the client's key.
+// ErrNotExists is returned if the object does not exist, and ErrNotAuthorized
+// is returned if the object is owned by others.
+func (c *client) Delete(ctx context.Context, typ, id string) error {
+	obj, err := c.Get(ctx, typ, id)
+	if err != nil {
+		return err
+	}
+	hash := auth.GetDeleteHash(c.domain, typ, id, obj.Version+1)
+
+	c.log.Debug("Delete({type}, {id}) by {owner}", logger.Attrs{
+		"type":    typ,
+		"id":      id,
+		"version": obj.Version + 1,
+		"hash":    hash,
+		"owner":   crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+	})
+
+	sig, err := crypto.Sign(hash[:], c.key)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign deletion")
+	}
+	_, err = c.api.DeleteObject(ctx, &pb.DeleteRequest{
+		Type:      typ,
+		Id:        id,
+		Signature: sig,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return ErrNotExists
+		case codes.Unauthenticated:
+			return ErrNotAuthorized
+		case codes.ResourceExhausted:
+			return exhaustedErrorOf(err)
+		}
+		return errors.Wrap(err, "failed to call RPC")
+	}
+	return nil
+}
+
+// SetACL replaces the access control list of the object, signed by the client's key.
+// Writers can update the object with Put or Patch on behalf of the owner, but only the owner
+// can delete, transfer the object or change the list. ErrNotAuthorized is returned
+// if the object is owned by others.
+func (c *client) SetACL(ctx context.Context, typ, id string, acl ACL, options ...PutOption) (*PutResult, error) {
+	var opt putOptions
+	for _, applyFunc := range options {
+		applyFunc(&opt)
+	}
+	version := opt.expectedVersion
+	if version == 0 {
+		obj, err := c.Get(ctx, typ, id)
+		if err != nil {
+			return nil, err
+		}
+		version = obj.Version
+	}
+	hash := auth.GetACLHash(c.domain, typ, id, version+1, acl.Writers, acl.Readers)
+
+	c.log.Debug("SetACL({type}, {id}) by {owner}", logger.Attrs{
+		"type":    typ,
+		"id":      id,
+		"version": version + 1,
+		"hash":    hash,
+		"owner":   crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+	})
+
+	sig, err := crypto.Sign(hash[:], c.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign ACL")
+	}
+	req := &pb.SetACLRequest{
+		Type:      typ,
+		Id:        id,
+		Signature: sig,
+
+		ExpectedVersion: version,
+	}
+	for _, writer := range acl.Writers {
+		req.Writers = append(req.Writers, writer.Hex())
+	}
+	for _, reader := range acl.Readers {
+		req.Readers = append(req.Readers, reader.Hex())
+	}
+	res, err := c.api.SetACL(ctx, req)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return nil, ErrNotExists
+		case codes.Unauthenticated:
+			return nil, ErrNotAuthorized
+		case codes.Aborted:
+			return nil, ErrConflict
+		case codes.ResourceExhausted:
+			return nil, exhaustedErrorOf(err)
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	return &PutResult{
+		FeeUsed: res.GetFeeUsed(),
+		Created: res.GetCreated(),
+		Version: res.GetVersion(),
+	}, nil
+}
+
+// SetVisibility makes the object private or public, signed by the client's key.
+// Private objects are only readable by the owner and the readers and writers in the ACL.
+// ErrNotAuthorized is returned if the object is owned by others.
+func (c *client) SetVisibility(ctx context.Context, typ, id string, private bool, options ...PutOption) (*PutResult, error) {
+	var opt putOptions
+	for _, applyFunc := range options {
+		applyFunc(&opt)
+	}
+	version := opt.expectedVersion
+	if version == 0 {
+		obj, err := c.Get(ctx, typ, id)
+		if err != nil {
+			return nil, err
+		}
+		version = obj.Version
+	}
+	hash := auth.GetVisibilityHash(c.domain, typ, id, version+1, private)
+
+	c.log.Debug("SetVisibility({type}, {id}) by {owner}", logger.Attrs{
+		"type":    typ,
+		"id":      id,
+		"version": version + 1,
+		"private": private,
+		"hash":    hash,
+		"owner":   crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+	})
+
+	sig, err := crypto.Sign(hash[:], c.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign visibility")
+	}
+	res, err := c.api.SetVisibility(ctx, &pb.SetVisibilityRequest{
+		Type:      typ,
+		Id:        id,
+		Private:   private,
+		Signature: sig,
+
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return nil, ErrNotExists
+		case codes.Unauthenticated:
+			return nil, ErrNotAuthorized
+		case codes.Aborted:
+			return nil, ErrConflict
+		case codes.ResourceExhausted:
+			return nil, exhaustedErrorOf(err)
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	return &PutResult{
+		FeeUsed: res.GetFeeUsed(),
+		Created: res.GetCreated(),
+		Version: res.GetVersion(),
+	}, nil
+}
+
+// TransferOwnership moves the ownership of the object to given address, signed by the client's key.
+// The public key of the new owner should be given with `afclient.WithNewOwnerKey` option,
+// or the new owner should accept the transfer with `SignTransferAcceptance` and the signature
+// should be given with `afclient.WithRecipientSignature` option.
+// ErrNotAuthorized is returned if the object is owned by others.
+func (c *client) TransferOwnership(ctx context.Context, typ, id string, newOwner common.Address, options ...TransferOption) (*PutResult, error) {
+	var opt transferOptions
+	for _, applyFunc := range options {
+		applyFunc(&opt)
+	}
+	obj, err := c.Get(ctx, typ, id)
+	if err != nil {
+		return nil, err
+	}
+	hash := auth.GetTransferHash(c.domain, typ, id, obj.Version+1, newOwner)
+
+	c.log.Debug("TransferOwnership({type}, {id}) to {newOwner}", logger.Attrs{
+		"type":     typ,
+		"id":       id,
+		"version":  obj.Version + 1,
+		"hash":     hash,
+		"owner":    crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+		"newOwner": newOwner.Hex(),
+	})
+
+	sig, err := crypto.Sign(hash[:], c.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign transfer")
+	}
+	req := &pb.TransferRequest{
+		Type:               typ,
+		Id:                 id,
+		NewOwner:           newOwner.Hex(),
+		Signature:          sig,
+		RecipientSignature: opt.recipientSignature,
+
+		ExpectedVersion: obj.Version,
+	}
+	if opt.newOwnerKey != nil {
+		req.NewOwnerKey = crypto.CompressPubkey(opt.newOwnerKey)
+	}
+	res, err := c.api.TransferOwnership(ctx, req)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return nil, ErrNotExists
+		case codes.Unauthenticated:
+			return nil, ErrNotAuthorized
+		case codes.Aborted:
+			return nil, ErrConflict
+		case codes.ResourceExhausted:
+			return nil, exhaustedErrorOf(err)
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	return &PutResult{
+		FeeUsed: res.GetFeeUsed(),
+		Created: res.GetCreated(),
+		Version: res.GetVersion(),
+	}, nil
+}
+
+// SignTransferAcceptance returns a signature of the client's key accepting the transfer
+// of the object to the client, which should be given to the current owner. The signature is
+// only valid until the object is updated, since it is made for the next version of the object.
+func (c *client) SignTransferAcceptance(ctx context.Context, typ, id string) ([]byte, error) {
+	obj, err := c.Get(ctx, typ, id)
+	if err != nil {
+		return nil, err
+	}
+	hash := auth.GetAcceptTransferHash(c.domain, typ, id, obj.Version+1, crypto.PubkeyToAddress(c.key.PublicKey))
+	sig, err := crypto.Sign(hash[:], c.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign acceptance")
+	}
+	return sig, nil
+}
+
+// objectHash returns a hash to be signed for writing given data,
+// which includes the expiry only if it is set.
+func objectHash(domain, typ, id string, version uint64, data M, expiresAt time.Time) [32]byte {
+	if expiresAt.IsZero() {
+		return auth.GetObjectHash(domain, typ, id, version, data)
+	}
+	return auth.GetExpiringObjectHash(domain, typ, id, version, data, expiresAt.Unix())
+}
+
+// expiryOf returns the expiry of given unix nanoseconds, which is zero for 0.
+func expiryOf(nanos uint64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(nanos))
+}
+
+// unixNanoOf returns given time in unix nanoseconds, or 0 for the zero time.
+func unixNanoOf(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+// aclOf returns the access control list in given response.
+func aclOf(res *pb.GetResponse) ACL {
+	var acl ACL
+	for _, writer := range res.GetWriters() {
+		acl.Writers = append(acl.Writers, common.HexToAddress(writer))
+	}
+	for _, reader := range res.GetReaders() {
+		acl.Readers = append(acl.Readers, common.HexToAddress(reader))
+	}
+	return acl
+}
+
+// latestVersion returns the current version of the object including deleted ones,
+// or 0 if the object has never existed.
+func (c *client) latestVersion(ctx context.Context, typ, id string) (uint64, error) {
+	res, err := c.api.GetObject(ctx, &pb.GetRequest{
+		Type:           typ,
+		Id:             id,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, nil
+		}
+		return 0, errors.Wrap(err, "failed to call RPC")
+	}
+	return res.GetVersion(), nil
+}
@@ -0,0 +1,74 @@
+package afclient
+
+import (
+	"context"
+)
+
+const defaultPageSize = 100
+
+// Iterator walks through all the results of a query, fetching them page by page.
+//
+//	it := client.Iterate(ctx, "user", afclient.M{"age": afclient.M{"gte": 20}})
+//	for it.Next() {
+//	  obj := it.Object()
+//	}
+//	if err := it.Err(); err != nil {
+//	  ...
+//	}
+type Iterator struct {
+	client *client
+	ctx    context.Context
+	typ    string
+	query  M
+	opt    queryOptions
+
+	page []*Object
+	obj  *Object
+	done bool
+	err  error
+}
+
+// Iterate returns an iterator of the objects matching with given query.
+// `afclient.WithLimit` option sets the size of each page, which is 100 by default.
+func (c *client) Iterate(ctx context.Context, typ string, query M, options ...QueryOption) *Iterator {
+	opt := queryOptions{
+		limit: defaultPageSize,
+	}
+	for _, applyFunc := range options {
+		applyFunc(&opt)
+	}
+	return &Iterator{
+		client: c,
+		ctx:    ctx,
+		typ:    typ,
+		query:  query,
+		opt:    opt,
+	}
+}
+
+// Next advances the iterator to the next object. It returns false when there are
+// no more objects or an error has occurred, which can be checked with Err.
+func (it *Iterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.page, it.opt.cursor, it.err = it.client.queryPage(it.ctx, it.typ, it.query, it.opt)
+
+		// skip should be applied only to the first page
+		it.opt.skip = 0
+		it.done = it.opt.cursor == ""
+	}
+	it.obj, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Object returns the current object.
+func (it *Iterator) Object() *Object {
+	return it.obj
+}
+
+// Err returns the error occurred during the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
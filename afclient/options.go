@@ -1,8 +1,31 @@
 package afclient
 
+import (
+	"crypto/ecdsa"
+	"time"
+)
+
+type dialOptions struct {
+	domain string
+}
+
+type DialOption func(opt *dialOptions)
+
+// WithDomain signs writes and read tokens for given signing domain, which should be
+// the same with the server. `auth.DefaultDomain` is used unless it is given.
+func WithDomain(domain string) DialOption {
+	return func(opt *dialOptions) {
+		opt.domain = domain
+	}
+}
+
 type queryOptions struct {
 	skip  int
 	limit int
+	sort  string
+	order SortOrder
+
+	cursor string
 }
 
 type QueryOption func(opt *queryOptions)
@@ -18,3 +41,120 @@ func WithLimit(limit int) QueryOption {
 		opt.limit = limit
 	}
 }
+
+// WithCursor continues the query after the page which given cursor is issued from.
+func WithCursor(cursor string) QueryOption {
+	return func(opt *queryOptions) {
+		opt.cursor = cursor
+	}
+}
+
+// SortOrder specifies the order of query results.
+type SortOrder string
+
+const (
+	Ascending  SortOrder = "asc"
+	Descending SortOrder = "desc"
+)
+
+// WithSort sorts query results by given field. "id", "createdAt" and "lastUpdatedAt"
+// refer to the ID and timestamps of the objects, instead of fields in the data.
+func WithSort(field string, order SortOrder) QueryOption {
+	return func(opt *queryOptions) {
+		opt.sort = field
+		opt.order = order
+	}
+}
+
+type getOptions struct {
+	revision uint64
+	at       time.Time
+}
+
+type GetOption func(opt *getOptions)
+
+// AtRevision reads the object as of given version.
+func AtRevision(version uint64) GetOption {
+	return func(opt *getOptions) {
+		opt.revision = version
+	}
+}
+
+// AtTime reads the object as of given time.
+func AtTime(at time.Time) GetOption {
+	return func(opt *getOptions) {
+		opt.at = at
+	}
+}
+
+type putOptions struct {
+	expectedVersion uint64
+	private         bool
+	expiresAt       time.Time
+}
+
+type PutOption func(opt *putOptions)
+
+// WithExpectedVersion makes the write fail with ErrConflict
+// unless the current version of the object is the same with given version.
+func WithExpectedVersion(version uint64) PutOption {
+	return func(opt *putOptions) {
+		opt.expectedVersion = version
+	}
+}
+
+// AsPrivate creates the object as private, which is only readable by the owner
+// and the ACL. It is ignored for updates, which keep the visibility of the object.
+func AsPrivate() PutOption {
+	return func(opt *putOptions) {
+		opt.private = true
+	}
+}
+
+// WithExpiry makes the object expire at given time, which is truncated to seconds.
+// Expired objects are treated as nonexistent, and removed by the server afterwards.
+func WithExpiry(expiresAt time.Time) PutOption {
+	return func(opt *putOptions) {
+		opt.expiresAt = expiresAt
+	}
+}
+
+// WithTTL makes the object expire after given duration from now, like `afclient.WithExpiry`.
+func WithTTL(ttl time.Duration) PutOption {
+	return WithExpiry(time.Now().Add(ttl))
+}
+
+// PatchType is a format of patch documents.
+type PatchType string
+
+const (
+	// MergePatch is a JSON Merge Patch (RFC 7396), which is an object of fields to be replaced.
+	// Fields with null values are removed.
+	MergePatch PatchType = "merge"
+
+	// JSONPatch is a JSON Patch (RFC 6902), which is an array of operations.
+	JSONPatch PatchType = "json"
+)
+
+type transferOptions struct {
+	newOwnerKey        *ecdsa.PublicKey
+	recipientSignature []byte
+}
+
+type TransferOption func(opt *transferOptions)
+
+// WithNewOwnerKey gives the public key of the new owner, which is needed
+// unless the transfer is counter-signed by the new owner.
+func WithNewOwnerKey(key *ecdsa.PublicKey) TransferOption {
+	return func(opt *transferOptions) {
+		opt.newOwnerKey = key
+	}
+}
+
+// WithRecipientSignature gives the signature of the new owner accepting the transfer,
+// which is made with `SignTransferAcceptance` by the new owner.
+func WithRecipientSignature(sig []byte) TransferOption {
+	return func(opt *transferOptions) {
+		opt.recipientSignature = sig
+	}
+}
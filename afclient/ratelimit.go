@@ -0,0 +1,30 @@
+package afclient
+
+import (
+	"fmt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// RateLimitError is raised when the client has exceeded the rate limit of the server.
+// Reads are limited by the IP of the client, and writes are limited by the signer.
+type RateLimitError struct {
+	// RetryAfter is how long the client should wait before retrying.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("too many requests. retry after %s.", e.RetryAfter)
+}
+
+// exhaustedErrorOf returns an error of RESOURCE_EXHAUSTED status, which is a *RateLimitError
+// if the status has a retry hint, or ErrQuotaExceeded otherwise.
+func exhaustedErrorOf(err error) error {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return &RateLimitError{RetryAfter: info.GetRetryDelay().AsDuration()}
+		}
+	}
+	return ErrQuotaExceeded
+}
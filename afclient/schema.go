@@ -0,0 +1,117 @@
+package afclient
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	pb "github.com/airbloc/airframe/proto"
+	"github.com/airbloc/logger"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// Schema is a JSON Schema of a type, which every write of the objects of the type
+// is validated against. Writes violating the schema fail with an error listing the violations.
+type Schema struct {
+	Type    string
+	Version uint64
+
+	// Definition is the JSON Schema document.
+	Definition string
+
+	// Signer is the address of the admin who set the schema.
+	Signer    common.Address
+	CreatedAt time.Time
+}
+
+// SetSchema sets the JSON Schema of the type, signed by the client's key which should be
+// one of the admins of the server. The current version of the schema is fetched first
+// unless `afclient.WithExpectedVersion` option is given. ErrNotAuthorized is returned
+// if the client is not an admin.
+func (c *client) SetSchema(ctx context.Context, typ, definition string, options ...PutOption) (*Schema, error) {
+	var opt putOptions
+	for _, applyFunc := range options {
+		applyFunc(&opt)
+	}
+	version := opt.expectedVersion
+	if version == 0 {
+		current, err := c.GetSchema(ctx, typ, 0)
+		if err != nil && err != ErrNotExists {
+			return nil, errors.Wrap(err, "failed to get current version")
+		} else if err == nil {
+			version = current.Version
+		}
+	}
+	hash := auth.GetSchemaHash(c.domain, typ, version+1, definition)
+
+	c.log.Debug("SetSchema({type}) by {admin}", logger.Attrs{
+		"type":    typ,
+		"version": version + 1,
+		"hash":    hash,
+		"admin":   crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+	})
+
+	sig, err := crypto.Sign(hash[:], c.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign schema")
+	}
+	res, err := c.api.SetSchema(ctx, &pb.SetSchemaRequest{
+		Type:       typ,
+		Definition: definition,
+		Signature:  sig,
+
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unauthenticated:
+			return nil, ErrNotAuthorized
+		case codes.Aborted:
+			return nil, ErrConflict
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	return schemaOf(res), nil
+}
+
+// GetSchema returns given version of the schema of the type, or the latest one if the version is 0.
+// ErrNotExists is returned if the schema has never been set.
+func (c *client) GetSchema(ctx context.Context, typ string, version uint64) (*Schema, error) {
+	res, err := c.api.GetSchema(ctx, &pb.GetSchemaRequest{
+		Type:    typ,
+		Version: version,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrNotExists
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	return schemaOf(res), nil
+}
+
+// ListSchemas returns the latest schemas of every type, sorted by type.
+func (c *client) ListSchemas(ctx context.Context) ([]*Schema, error) {
+	res, err := c.api.ListSchemas(ctx, &pb.ListSchemasRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	schemas := make([]*Schema, len(res.GetSchemas()))
+	for i, schema := range res.GetSchemas() {
+		schemas[i] = schemaOf(schema)
+	}
+	return schemas, nil
+}
+
+func schemaOf(res *pb.Schema) *Schema {
+	return &Schema{
+		Type:       res.GetType(),
+		Version:    res.GetVersion(),
+		Definition: res.GetDefinition(),
+		Signer:     common.HexToAddress(res.GetSigner()),
+		CreatedAt:  time.Unix(0, int64(res.GetCreatedAt())),
+	}
+}
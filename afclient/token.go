@@ -0,0 +1,45 @@
+package afclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/airbloc/airframe/auth"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// readTokenTTL is the lifetime of read tokens issued by the client.
+// It is shorter than auth.MaxReadTokenTTL to tolerate clock skews with the server.
+const readTokenTTL = 5 * time.Minute
+
+// readTokenCredentials attaches a read token signed by the client's key to every call,
+// so that the client can read private objects which it is granted to read.
+// Tokens are reused until they are about to expire.
+type readTokenCredentials struct {
+	key    *ecdsa.PrivateKey
+	domain string
+
+	lock      sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (r *readTokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if time.Until(r.expiresAt) < readTokenTTL/2 {
+		expiresAt := time.Now().Add(readTokenTTL)
+		token, err := auth.NewReadToken(r.domain, r.key, expiresAt)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to issue read token")
+		}
+		r.token, r.expiresAt = token, expiresAt
+	}
+	return map[string]string{"authorization": "Bearer " + r.token}, nil
+}
+
+func (r *readTokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,21 @@
+package afclient
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// propagateTraceContext attaches the trace context of the call to the metadata with the global
+// propagator, so that the spans of the server are nested in the span of the caller.
+// Nothing is attached unless the application sets the propagator with otel.SetTextMapPropagator.
+func propagateTraceContext(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	for key, value := range carrier {
+		ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
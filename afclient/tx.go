@@ -0,0 +1,140 @@
+package afclient
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	pb "github.com/airbloc/airframe/proto"
+	"github.com/airbloc/logger"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// MaxTxSize is the maximum number of operations in a transaction.
+const MaxTxSize = 12
+
+// TxOpType is a type of an operation in a transaction.
+type TxOpType string
+
+const (
+	// TxPut creates or updates the object like Put.
+	TxPut TxOpType = "put"
+
+	// TxDelete deletes the object like Delete.
+	TxDelete TxOpType = "delete"
+
+	// TxCheck only checks that the current version of the object including deleted ones
+	// is the same with ExpectedVersion, where 0 means the object has never existed.
+	TxCheck TxOpType = "check"
+)
+
+// TxOp is an operation in a transaction.
+type TxOp struct {
+	Op   TxOpType
+	Type string
+	ID   string
+
+	// Data, Private and ExpiresAt are only for TxPut.
+	Data      M
+	Private   bool
+	ExpiresAt time.Time
+
+	// ExpectedVersion makes the whole transaction fail with ErrConflict unless
+	// the current version of the object is the same with it.
+	ExpectedVersion uint64
+}
+
+// Transact commits given operations all-or-nothing, signed by the client's key.
+// The current versions of the objects to write are fetched first in a batch unless
+// ExpectedVersion is given. If any of the operations fails, nothing is written
+// and the error of the failed operation is returned. The results are in the same
+// order with the operations, and nil for TxCheck.
+func (c *client) Transact(ctx context.Context, ops []TxOp) ([]*PutResult, error) {
+	versions := make([]uint64, len(ops))
+	var keys []ObjectKey
+	var indices []int
+	for i, op := range ops {
+		if versions[i] = op.ExpectedVersion; versions[i] == 0 && op.Op != TxCheck {
+			keys = append(keys, ObjectKey{Type: op.Type, ID: op.ID})
+			indices = append(indices, i)
+		}
+	}
+	if len(keys) > 0 {
+		res, err := c.batchGet(ctx, keys, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get current versions")
+		}
+		for i, r := range res {
+			if r.GetError() != nil && codes.Code(r.GetError().GetCode()) != codes.NotFound {
+				return nil, errors.Wrap(itemErrorOf(r.GetError()), "failed to get current versions")
+			}
+			versions[indices[i]] = r.GetObject().GetVersion()
+		}
+	}
+
+	req := &pb.TransactRequest{Ops: make([]*pb.TxOp, len(ops))}
+	for i, op := range ops {
+		req.Ops[i] = &pb.TxOp{
+			Op:   string(op.Op),
+			Type: op.Type,
+			Id:   op.ID,
+
+			ExpectedVersion: op.ExpectedVersion,
+		}
+		var hash [32]byte
+		switch op.Op {
+		case TxPut:
+			data, err := json.MarshalToString(op.Data)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to marshal data into JSON")
+			}
+			req.Ops[i].Data = data
+			req.Ops[i].Private = op.Private
+			req.Ops[i].ExpiresAt = unixNanoOf(op.ExpiresAt)
+			hash = objectHash(c.domain, op.Type, op.ID, versions[i]+1, op.Data, op.ExpiresAt)
+		case TxDelete:
+			hash = auth.GetDeleteHash(c.domain, op.Type, op.ID, versions[i]+1)
+		default:
+			continue
+		}
+		sig, err := crypto.Sign(hash[:], c.key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to sign operation")
+		}
+		req.Ops[i].Signature = sig
+	}
+
+	c.log.Debug("Transact({count} operations) by {owner}", logger.Attrs{
+		"count": len(ops),
+		"owner": crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+	})
+
+	res, err := c.api.Transact(ctx, req)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return nil, ErrNotExists
+		case codes.Unauthenticated:
+			return nil, ErrNotAuthorized
+		case codes.Aborted:
+			return nil, ErrConflict
+		case codes.ResourceExhausted:
+			return nil, exhaustedErrorOf(err)
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	results := make([]*PutResult, len(res.GetResults()))
+	for i, r := range res.GetResults() {
+		if ops[i].Op == TxCheck {
+			continue
+		}
+		results[i] = &PutResult{
+			FeeUsed: r.GetFeeUsed(),
+			Created: r.GetCreated(),
+			Version: r.GetVersion(),
+		}
+	}
+	return results, nil
+}
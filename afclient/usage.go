@@ -0,0 +1,92 @@
+package afclient
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	pb "github.com/airbloc/airframe/proto"
+	"github.com/airbloc/logger"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Usage is the fees charged to an owner and the prepaid quota deposited by the admins.
+// If the server enforces the quota, writes fail with ErrQuotaExceeded when the payer
+// cannot afford the fee with the balance.
+type Usage struct {
+	Owner common.Address
+
+	// Writes is the number of the charged writes.
+	Writes  uint64
+	FeeUsed uint64
+
+	// Deposited is the total amount of the deposits, and Deposits is the number of them.
+	Deposited uint64
+	Deposits  uint64
+	Balance   uint64
+}
+
+// Usage returns the usage of the owner. Only the owner and the admins can read it,
+// and ErrReadDenied is returned for others.
+func (c *client) Usage(ctx context.Context, owner common.Address) (*Usage, error) {
+	res, err := c.api.GetUsage(ctx, &pb.GetUsageRequest{Owner: owner.Hex()})
+	if err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			return nil, ErrReadDenied
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	return usageOf(res), nil
+}
+
+// Deposit adds the amount to the prepaid quota of the owner, signed by the client's key
+// which should be one of the admins of the server. ErrNotAuthorized is returned if the
+// client is not an admin, and ErrConflict is returned if the owner has been deposited concurrently.
+func (c *client) Deposit(ctx context.Context, owner common.Address, amount uint64) (*Usage, error) {
+	current, err := c.Usage(ctx, owner)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current deposits")
+	}
+	hash := auth.GetDepositHash(c.domain, owner, current.Deposits+1, amount)
+
+	c.log.Debug("Deposit({owner}, {amount}) by {admin}", logger.Attrs{
+		"owner":    owner.Hex(),
+		"amount":   amount,
+		"deposits": current.Deposits + 1,
+		"hash":     hash,
+		"admin":    crypto.PubkeyToAddress(c.key.PublicKey).Hex(),
+	})
+
+	sig, err := crypto.Sign(hash[:], c.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign deposit")
+	}
+	res, err := c.api.Deposit(ctx, &pb.DepositRequest{
+		Owner:     owner.Hex(),
+		Amount:    amount,
+		Signature: sig,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unauthenticated:
+			return nil, ErrNotAuthorized
+		case codes.Aborted:
+			return nil, ErrConflict
+		}
+		return nil, errors.Wrap(err, "failed to call RPC")
+	}
+	return usageOf(res), nil
+}
+
+func usageOf(res *pb.Usage) *Usage {
+	return &Usage{
+		Owner:     common.HexToAddress(res.GetOwner()),
+		Writes:    res.GetWrites(),
+		FeeUsed:   res.GetFeeUsed(),
+		Deposited: res.GetDeposited(),
+		Deposits:  res.GetDeposits(),
+		Balance:   res.GetBalance(),
+	}
+}
@@ -1,13 +1,18 @@
 package apiserver
 
 import (
+	"encoding/json"
 	"github.com/airbloc/airframe/auth"
 	"github.com/airbloc/airframe/database"
 	"github.com/gin-gonic/gin"
+	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/common/hexutil"
 	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var (
@@ -17,31 +22,220 @@ var (
 type PutRequest struct {
 	Data      database.Payload `json:"data" binding:"required"`
 	Signature string           `json:"signature" binding:"required"`
+
+	// ExpectedVersion makes the write fail with 409 Conflict unless the current version
+	// of the object is the same with it. It can be also given with If-Match header.
+	ExpectedVersion uint64 `json:"expectedVersion"`
+
+	// Private makes the created object readable only by the owner and the ACL.
+	// It is ignored for updates, which keep the visibility of the object.
+	Private bool `json:"private"`
+
+	// ExpiresAt makes the object expire at the time in RFC 3339, which is truncated to seconds.
+	// The signature should be made with the expiry in unix seconds. Omitting it on updates
+	// makes the object never expire.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// PatchRequest has a patch document, which is either a JSON Merge Patch (RFC 7396)
+// with type "merge" or a JSON Patch (RFC 6902) with type "json".
+// The signature should be made for the patch as it is sent.
+type PatchRequest struct {
+	Type      string          `json:"type" binding:"required"`
+	Patch     json.RawMessage `json:"patch" binding:"required"`
+	Signature string          `json:"signature" binding:"required"`
+
+	ExpectedVersion uint64 `json:"expectedVersion"`
+}
+
+// TransferRequest moves the ownership of the object to the new owner's address.
+// The public key of the new owner can be omitted if the recipient's signature is given.
+type TransferRequest struct {
+	NewOwner           string `json:"newOwner" binding:"required"`
+	NewOwnerKey        string `json:"newOwnerKey"`
+	Signature          string `json:"signature" binding:"required"`
+	RecipientSignature string `json:"recipientSignature"`
+
+	ExpectedVersion uint64 `json:"expectedVersion"`
+}
+
+// ACLRequest replaces the access control list of the object with given hex addresses.
+// The addresses are signed by the owner in the given order.
+type ACLRequest struct {
+	Writers   []string `json:"writers"`
+	Readers   []string `json:"readers"`
+	Signature string   `json:"signature" binding:"required"`
+
+	ExpectedVersion uint64 `json:"expectedVersion"`
+}
+
+// VisibilityRequest makes the object private or public.
+type VisibilityRequest struct {
+	Private   bool   `json:"private"`
+	Signature string `json:"signature" binding:"required"`
+
+	ExpectedVersion uint64 `json:"expectedVersion"`
 }
 
-func RegisterV1API(r *gin.Engine, db database.Database) {
+// SchemaRequest sets the JSON Schema of the type. The signature should be made
+// by an admin for the definition as it is sent.
+type SchemaRequest struct {
+	Definition json.RawMessage `json:"definition" binding:"required"`
+	Signature  string          `json:"signature" binding:"required"`
+
+	ExpectedVersion uint64 `json:"expectedVersion"`
+}
+
+// DepositRequest adds the amount to the prepaid quota of the owner. The signature should be made
+// by an admin for the next deposit of the owner.
+type DepositRequest struct {
+	Amount    uint64 `json:"amount" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+type DeleteRequest struct {
+	Signature string `json:"signature" binding:"required"`
+}
+
+type BatchGetRequest struct {
+	Keys []struct {
+		Type string `json:"type" binding:"required"`
+		ID   string `json:"id" binding:"required"`
+	} `json:"keys" binding:"required,dive"`
+	IncludeDeleted bool `json:"includeDeleted"`
+}
+
+// BatchPutRequest has writes of objects, each of which is signed like PutRequest.
+type BatchPutRequest struct {
+	Items []struct {
+		Type string `json:"type" binding:"required"`
+		ID   string `json:"id" binding:"required"`
+		PutRequest
+	} `json:"items" binding:"required,dive"`
+}
+
+// TransactRequest has operations committed all-or-nothing. Each operation is either
+// "put", "delete" or "check", where puts and deletes are signed like single writes.
+type TransactRequest struct {
+	Ops []struct {
+		Op              string           `json:"op" binding:"required"`
+		Type            string           `json:"type" binding:"required"`
+		ID              string           `json:"id" binding:"required"`
+		Data            database.Payload `json:"data"`
+		Signature       string           `json:"signature"`
+		ExpectedVersion uint64           `json:"expectedVersion"`
+		Private         bool             `json:"private"`
+		ExpiresAt       time.Time        `json:"expiresAt"`
+	} `json:"ops" binding:"required,dive"`
+}
+
+// RegisterV1API registers the handlers of the backend. Read tokens are verified
+// with the signing domain of the backend, and the admins can read the usage of every owner.
+func RegisterV1API(r *gin.Engine, db database.Database, config *database.Config) {
+	domain := config.Domain
 	route := r.Group("/v1")
-	route.GET("/object/:type/:id", handleGetObject(db))
-	route.GET("/object/:type", handleQuery(db))
+	route.GET("/object/:type/:id", handleGetObject(db, domain))
+	route.GET("/object/:type/:id/history", handleGetHistory(db, domain))
+	route.GET("/object/:type", handleQuery(db, domain))
 	route.POST("/object/:type/:id", handlePutObject(db))
+	route.PATCH("/object/:type/:id", handlePatchObject(db))
+	route.DELETE("/object/:type/:id", handleDeleteObject(db))
+	route.PUT("/object/:type/:id/owner", handleTransferOwnership(db))
+	route.PUT("/object/:type/:id/acl", handleSetACL(db))
+	route.PUT("/object/:type/:id/visibility", handleSetVisibility(db))
+
+	// optional capabilities are served only if the backend implements them
+	if batcher, ok := db.(database.Batcher); ok {
+		route.POST("/batch/get", handleBatchGet(batcher, domain))
+		route.POST("/batch/put", handleBatchPut(batcher))
+	}
+	if transactor, ok := db.(database.Transactor); ok {
+		route.POST("/transact", handleTransact(transactor))
+	}
+	if registry, ok := db.(database.SchemaRegistry); ok {
+		route.GET("/schema", handleListSchemas(registry))
+		route.GET("/schema/:type", handleGetSchema(registry))
+		route.PUT("/schema/:type", handleSetSchema(registry))
+	}
+	if meter, ok := db.(database.Meter); ok {
+		route.GET("/usage/:owner", handleGetUsage(meter, config))
+		route.POST("/usage/:owner/deposit", handleDeposit(meter))
+	}
 
-	// health check
-	route.GET("/", func(c *gin.Context) {
-		priv, _ := crypto.GenerateKey()
-		hash := auth.GetObjectHash("testdata", "deadbeef", database.Payload{"foo": "bar"})
-		sig, _ := crypto.Sign(hash[:], priv)
+	// health check, which is the same with GET /readyz
+	route.GET("/", handleReadiness(db))
+}
 
-		if _, err := db.Put(c, "testdata", "deadbeef", database.Payload{"foo": "bar"}, sig); err != nil {
+func handleGetObject(db database.Database, domain string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		typ, id := c.Param("type"), c.Param("id")
+		reader, ok := readerOf(c, domain)
+		if !ok {
+			return
+		}
+
+		var obj *database.Object
+		var err error
+		if rawRevision := c.Query("revision"); rawRevision != "" {
+			revision, parseErr := strconv.ParseUint(rawRevision, 10, 64)
+			if parseErr != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision: " + parseErr.Error()})
+				return
+			}
+			obj, err = database.GetRevision(c.Request.Context(), db, typ, id, revision)
+
+		} else if rawTime := c.Query("at"); rawTime != "" {
+			at, parseErr := time.Parse(time.RFC3339Nano, rawTime)
+			if parseErr != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time: " + parseErr.Error()})
+				return
+			}
+			obj, err = database.GetAt(c.Request.Context(), db, typ, id, at)
+
+		} else {
+			obj, err = db.Get(c.Request.Context(), typ, id)
+			if includeDeleted, _ := strconv.ParseBool(c.Query("includeDeleted")); err == database.ErrNotExists && includeDeleted {
+				// returns the version of the deleted object, which is needed for re-creating it.
+				version, err := db.Version(c.Request.Context(), typ, id)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				} else if version > 0 {
+					c.JSON(http.StatusOK, gin.H{"version": version, "deleted": true})
+					return
+				}
+			}
+		}
+		if err != nil {
+			if err == database.ErrNotExists {
+				c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"status": "ok", "addr": crypto.PubkeyToAddress(priv.PublicKey).Hex()})
-	})
+		if !database.CanRead(obj, reader) {
+			c.JSON(readDeniedStatus(reader), gin.H{"error": "you're not authorized to read the object"})
+			return
+		}
+		if c.Query("revision") != "" || c.Query("at") != "" {
+			// past states are also protected by the current visibility of the object
+			if !checkCurrentRead(c, db, reader) {
+				return
+			}
+		}
+		c.Header("ETag", etagOf(obj.Version))
+		c.JSON(http.StatusOK, objectToJson(obj))
+	}
 }
 
-func handleGetObject(db database.Database) gin.HandlerFunc {
+func handleGetHistory(db database.Database, domain string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		obj, err := db.Get(c, c.Param("type"), c.Param("id"))
+		reader, ok := readerOf(c, domain)
+		if !ok || !checkCurrentRead(c, db, reader) {
+			return
+		}
+		revisions, err := db.History(c.Request.Context(), c.Param("type"), c.Param("id"))
 		if err != nil {
 			if err == database.ErrNotExists {
 				c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
@@ -50,12 +244,37 @@ func handleGetObject(db database.Database) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusOK, objectToJson(obj))
+		results := make([]gin.H, len(revisions))
+		for i, rev := range revisions {
+			results[i] = gin.H{
+				"version":   rev.Version,
+				"data":      rev.Data,
+				"owner":     pubkeyToAddress(rev.Owner),
+				"signer":    pubkeyToAddress(rev.Signer),
+				"signature": hexutil.Encode(rev.Signature),
+				"timestamp": rev.LastUpdatedAt,
+				"deleted":   rev.Deleted,
+			}
+			if !rev.ExpiresAt.IsZero() {
+				results[i]["expiresAt"] = rev.ExpiresAt
+			}
+			if rev.Transfer != nil {
+				results[i]["transferred"] = true
+				if len(rev.Transfer.RecipientSignature) > 0 {
+					results[i]["recipientSignature"] = hexutil.Encode(rev.Transfer.RecipientSignature)
+				}
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"revisions": results})
 	}
 }
 
-func handleQuery(db database.Database) gin.HandlerFunc {
+func handleQuery(db database.Database, domain string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		reader, ok := readerOf(c, domain)
+		if !ok {
+			return
+		}
 		q := c.Query("query")
 		if q == "" {
 			q = "{}"
@@ -68,30 +287,52 @@ func handleQuery(db database.Database) gin.HandlerFunc {
 		if err != nil {
 			skip = 0
 		}
-		query, err := database.QueryFromJson(q)
+		query, err := parseQuery(c, q)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query: " + err.Error()})
+			return
+		}
+		sort, err := database.SortFromString(c.Query("sort"), c.Query("order"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort: " + err.Error()})
 			return
 		}
 
-		objects, err := db.Query(c, c.Param("type"), query, skip, limit)
+		cursor, err := database.CursorFromString(c.Query("cursor"), sort)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := db.Query(c.Request.Context(), c.Param("type"), query, database.QueryOptions{
+			Sort:   sort,
+			Cursor: cursor,
+			Skip:   skip,
+			Limit:  limit,
+			Reader: reader,
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
+		objects := result.Objects
 		results := make([]gin.H, len(objects))
 		for i := 0; i < len(objects); i++ {
 			results[i] = objectToJson(objects[i])
 		}
-		c.JSON(http.StatusOK, gin.H{"results": results})
+		response := gin.H{"results": results}
+		if result.NextCursor != nil {
+			response["nextCursor"] = result.NextCursor.String()
+		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
 func handlePutObject(db database.Database) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req PutRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
+		if err := bindJSON(c, &req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -107,26 +348,836 @@ func handlePutObject(db database.Database) gin.HandlerFunc {
 			return
 		}
 
-		result, err := db.Put(c, typ, id, req.Data, sig)
+		if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+			if req.ExpectedVersion, err = parseETag(ifMatch); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header: " + err.Error()})
+				return
+			}
+		}
+
+		result, err := db.Put(c.Request.Context(), typ, id, req.Data, sig, database.PutOptions{
+			ExpectedVersion: req.ExpectedVersion,
+			Private:         req.Private,
+			ExpiresAt:       req.ExpiresAt,
+		})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			if respondRateLimited(c, err) {
+				return
+			}
+			if schemaErr, ok := errors.Cause(err).(*database.SchemaError); ok {
+				c.JSON(http.StatusBadRequest, schemaErrorToJson(schemaErr))
+				return
+			}
+			switch errors.Cause(err) {
+			case database.ErrInvalidID, database.ErrInvalidType, database.ErrInvalidExpiry, database.ErrReservedKey, auth.ErrInvalidSignature:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case database.ErrNotAuthorized:
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case database.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			case database.ErrQuotaExceeded:
+				c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
 			return
 		}
+		c.Header("ETag", etagOf(result.Version))
 		c.JSON(http.StatusOK, gin.H{
 			"created": result.Created,
 			"feeUsed": result.FeeUsed,
+			"version": result.Version,
 		})
 	}
 }
 
-func objectToJson(obj *database.Object) gin.H {
-	pub, _ := crypto.DecompressPubkey(obj.Owner[:])
-	ownerAddr := crypto.PubkeyToAddress(*pub)
+func handlePatchObject(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PatchRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 
+		sig, err := hexutil.Decode(req.Signature)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + err.Error()})
+			return
+		}
+		if len(sig) != 65 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + msgInvalidSigLength})
+			return
+		}
+		if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+			if req.ExpectedVersion, err = parseETag(ifMatch); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header: " + err.Error()})
+				return
+			}
+		}
+
+		patch := &database.Patch{
+			Type:     database.PatchType(req.Type),
+			Document: req.Patch,
+		}
+		result, err := db.Patch(c.Request.Context(), c.Param("type"), c.Param("id"), patch, sig, database.PutOptions{
+			ExpectedVersion: req.ExpectedVersion,
+		})
+		if err != nil {
+			if respondRateLimited(c, err) {
+				return
+			}
+			if schemaErr, ok := errors.Cause(err).(*database.SchemaError); ok {
+				c.JSON(http.StatusBadRequest, schemaErrorToJson(schemaErr))
+				return
+			}
+			switch errors.Cause(err) {
+			case database.ErrNotExists:
+				c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+			case auth.ErrInvalidSignature:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case database.ErrNotAuthorized:
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case database.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			case database.ErrQuotaExceeded:
+				c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+			case database.ErrInvalidPatch, database.ErrReservedKey:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.Header("ETag", etagOf(result.Version))
+		c.JSON(http.StatusOK, gin.H{
+			"created": result.Created,
+			"feeUsed": result.FeeUsed,
+			"version": result.Version,
+		})
+	}
+}
+
+func handleTransferOwnership(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req TransferRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sig, err := hexutil.Decode(req.Signature)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + err.Error()})
+			return
+		}
+		if len(sig) != 65 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + msgInvalidSigLength})
+			return
+		}
+		if !common.IsHexAddress(req.NewOwner) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid new owner: " + req.NewOwner})
+			return
+		}
+		transfer := &database.Transfer{To: common.HexToAddress(req.NewOwner)}
+		if req.NewOwnerKey != "" {
+			key, err := hexutil.Decode(req.NewOwnerKey)
+			if err != nil || len(key) != len(transfer.NewOwner) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid new owner key: should be 33-byte compressed public key"})
+				return
+			}
+			copy(transfer.NewOwner[:], key)
+		}
+		if req.RecipientSignature != "" {
+			if transfer.RecipientSignature, err = hexutil.Decode(req.RecipientSignature); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipient signature: " + err.Error()})
+				return
+			}
+			if len(transfer.RecipientSignature) != 65 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipient signature: " + msgInvalidSigLength})
+				return
+			}
+		}
+		if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+			if req.ExpectedVersion, err = parseETag(ifMatch); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header: " + err.Error()})
+				return
+			}
+		}
+
+		result, err := db.TransferOwnership(c.Request.Context(), c.Param("type"), c.Param("id"), transfer, sig, database.PutOptions{
+			ExpectedVersion: req.ExpectedVersion,
+		})
+		if err != nil {
+			if respondRateLimited(c, err) {
+				return
+			}
+			switch errors.Cause(err) {
+			case database.ErrNotExists:
+				c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+			case auth.ErrInvalidSignature:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case database.ErrNotAuthorized:
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case database.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			case database.ErrQuotaExceeded:
+				c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+			case database.ErrInvalidRecipient:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.Header("ETag", etagOf(result.Version))
+		c.JSON(http.StatusOK, gin.H{
+			"created": result.Created,
+			"feeUsed": result.FeeUsed,
+			"version": result.Version,
+		})
+	}
+}
+
+func handleSetACL(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ACLRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sig, err := hexutil.Decode(req.Signature)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + err.Error()})
+			return
+		}
+		if len(sig) != 65 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + msgInvalidSigLength})
+			return
+		}
+		acl := new(database.ACL)
+		for _, writer := range req.Writers {
+			if !common.IsHexAddress(writer) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid writer: " + writer})
+				return
+			}
+			acl.Writers = append(acl.Writers, common.HexToAddress(writer))
+		}
+		for _, reader := range req.Readers {
+			if !common.IsHexAddress(reader) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reader: " + reader})
+				return
+			}
+			acl.Readers = append(acl.Readers, common.HexToAddress(reader))
+		}
+		if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+			if req.ExpectedVersion, err = parseETag(ifMatch); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header: " + err.Error()})
+				return
+			}
+		}
+
+		result, err := db.SetACL(c.Request.Context(), c.Param("type"), c.Param("id"), acl, sig, database.PutOptions{
+			ExpectedVersion: req.ExpectedVersion,
+		})
+		if err != nil {
+			if respondRateLimited(c, err) {
+				return
+			}
+			switch errors.Cause(err) {
+			case database.ErrNotExists:
+				c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+			case auth.ErrInvalidSignature:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case database.ErrNotAuthorized:
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case database.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			case database.ErrQuotaExceeded:
+				c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+			case database.ErrACLTooLarge:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.Header("ETag", etagOf(result.Version))
+		c.JSON(http.StatusOK, gin.H{
+			"created": result.Created,
+			"feeUsed": result.FeeUsed,
+			"version": result.Version,
+		})
+	}
+}
+
+func handleSetVisibility(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req VisibilityRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sig, err := hexutil.Decode(req.Signature)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + err.Error()})
+			return
+		}
+		if len(sig) != 65 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + msgInvalidSigLength})
+			return
+		}
+		if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+			if req.ExpectedVersion, err = parseETag(ifMatch); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header: " + err.Error()})
+				return
+			}
+		}
+
+		result, err := db.SetVisibility(c.Request.Context(), c.Param("type"), c.Param("id"), req.Private, sig, database.PutOptions{
+			ExpectedVersion: req.ExpectedVersion,
+		})
+		if err != nil {
+			if respondRateLimited(c, err) {
+				return
+			}
+			switch errors.Cause(err) {
+			case database.ErrNotExists:
+				c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+			case auth.ErrInvalidSignature:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case database.ErrNotAuthorized:
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case database.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			case database.ErrQuotaExceeded:
+				c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.Header("ETag", etagOf(result.Version))
+		c.JSON(http.StatusOK, gin.H{
+			"created": result.Created,
+			"feeUsed": result.FeeUsed,
+			"version": result.Version,
+		})
+	}
+}
+
+func handleDeleteObject(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DeleteRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sig, err := hexutil.Decode(req.Signature)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + err.Error()})
+			return
+		}
+		if len(sig) != 65 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + msgInvalidSigLength})
+			return
+		}
+
+		if err := db.Delete(c.Request.Context(), c.Param("type"), c.Param("id"), sig); err != nil {
+			if respondRateLimited(c, err) {
+				return
+			}
+			switch errors.Cause(err) {
+			case database.ErrNotExists:
+				c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+			case auth.ErrInvalidSignature:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case database.ErrNotAuthorized:
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case database.ErrQuotaExceeded:
+				c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"deleted": true})
+	}
+}
+
+func handleBatchGet(db database.Batcher, domain string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req BatchGetRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		reader, ok := readerOf(c, domain)
+		if !ok {
+			return
+		}
+		keys := make([]database.ObjectKey, len(req.Keys))
+		for i, key := range req.Keys {
+			keys[i] = database.ObjectKey{Type: key.Type, ID: key.ID}
+		}
+
+		results, err := db.BatchGet(c.Request.Context(), keys, req.IncludeDeleted)
+		if err != nil {
+			if respondNotSupported(c, err) {
+				return
+			}
+			c.JSON(batchErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		response := make([]gin.H, len(results))
+		for i, result := range results {
+			if result.Err != nil {
+				response[i] = itemErrorToJson(result.Err)
+				continue
+			}
+			if !database.CanRead(result.Object, reader) {
+				response[i] = gin.H{"status": readDeniedStatus(reader), "error": "you're not authorized to read the object"}
+				continue
+			}
+			response[i] = gin.H{"object": objectToJson(result.Object)}
+		}
+		c.JSON(http.StatusOK, gin.H{"results": response})
+	}
+}
+
+func handleBatchPut(db database.Batcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req BatchPutRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		response := make([]gin.H, len(req.Items))
+
+		// items with malformed signatures fail without being sent to the database
+		var items []database.PutItem
+		var indices []int
+		for i, item := range req.Items {
+			sig, err := hexutil.Decode(item.Signature)
+			if err != nil {
+				response[i] = gin.H{"status": http.StatusBadRequest, "error": "invalid signature: " + err.Error()}
+				continue
+			}
+			if len(sig) != 65 {
+				response[i] = gin.H{"status": http.StatusBadRequest, "error": "invalid signature: " + msgInvalidSigLength}
+				continue
+			}
+			items = append(items, database.PutItem{
+				ObjectKey: database.ObjectKey{Type: item.Type, ID: item.ID},
+				Data:      item.Data,
+				Signature: sig,
+				Options: database.PutOptions{
+					ExpectedVersion: item.ExpectedVersion,
+					Private:         item.Private,
+					ExpiresAt:       item.ExpiresAt,
+				},
+			})
+			indices = append(indices, i)
+		}
+
+		if len(items) > 0 {
+			results, err := db.BatchPut(c.Request.Context(), items)
+			if err != nil {
+				if respondNotSupported(c, err) {
+					return
+				}
+				c.JSON(batchErrorStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			for i, result := range results {
+				if result.Err != nil {
+					response[indices[i]] = itemErrorToJson(result.Err)
+					continue
+				}
+				response[indices[i]] = gin.H{
+					"created": result.Result.Created,
+					"feeUsed": result.Result.FeeUsed,
+					"version": result.Result.Version,
+				}
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"results": response})
+	}
+}
+
+func handleTransact(db database.Transactor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req TransactRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ops := make([]database.TxOp, len(req.Ops))
+		for i, op := range req.Ops {
+			ops[i] = database.TxOp{
+				ObjectKey: database.ObjectKey{Type: op.Type, ID: op.ID},
+				Op:        database.TxOpType(op.Op),
+				Data:      op.Data,
+				Options: database.PutOptions{
+					ExpectedVersion: op.ExpectedVersion,
+					Private:         op.Private,
+					ExpiresAt:       op.ExpiresAt,
+				},
+			}
+			if ops[i].Op == database.TxCheck {
+				continue
+			}
+			sig, err := hexutil.Decode(op.Signature)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"index": i, "error": "invalid signature: " + err.Error()})
+				return
+			}
+			if len(sig) != 65 {
+				c.JSON(http.StatusBadRequest, gin.H{"index": i, "error": "invalid signature: " + msgInvalidSigLength})
+				return
+			}
+			ops[i].Signature = sig
+		}
+
+		results, err := db.Transact(c.Request.Context(), ops)
+		if err != nil {
+			if respondNotSupported(c, err) {
+				return
+			}
+			if respondRateLimited(c, err) {
+				return
+			}
+			response := gin.H{"error": err.Error()}
+			if txErr, ok := err.(*database.TxError); ok {
+				response["index"] = txErr.Index
+			}
+			if schemaErr, ok := errors.Cause(err).(*database.SchemaError); ok {
+				response["violations"] = schemaErr.Violations
+				c.JSON(http.StatusBadRequest, response)
+				return
+			}
+			switch errors.Cause(err) {
+			case database.ErrTxTooLarge, database.ErrDuplicatedKey, database.ErrInvalidTxOp, database.ErrInvalidID, database.ErrInvalidType, database.ErrInvalidExpiry, database.ErrReservedKey, auth.ErrInvalidSignature:
+				c.JSON(http.StatusBadRequest, response)
+			case database.ErrNotExists:
+				c.JSON(http.StatusNotFound, response)
+			case database.ErrNotAuthorized:
+				c.JSON(http.StatusForbidden, response)
+			case database.ErrConflict:
+				c.JSON(http.StatusConflict, response)
+			case database.ErrQuotaExceeded:
+				c.JSON(http.StatusPaymentRequired, response)
+			default:
+				c.JSON(http.StatusInternalServerError, response)
+			}
+			return
+		}
+		response := make([]gin.H, len(results))
+		for i, result := range results {
+			if result == nil {
+				response[i] = gin.H{}
+				continue
+			}
+			response[i] = gin.H{
+				"created": result.Created,
+				"feeUsed": result.FeeUsed,
+				"version": result.Version,
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"results": response})
+	}
+}
+
+func handleSetSchema(db database.SchemaRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req SchemaRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sig, err := hexutil.Decode(req.Signature)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + err.Error()})
+			return
+		}
+		if len(sig) != 65 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + msgInvalidSigLength})
+			return
+		}
+		if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+			if req.ExpectedVersion, err = parseETag(ifMatch); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header: " + err.Error()})
+				return
+			}
+		}
+
+		schema, err := db.SetSchema(c.Request.Context(), c.Param("type"), string(req.Definition), sig, database.PutOptions{
+			ExpectedVersion: req.ExpectedVersion,
+		})
+		if err != nil {
+			if respondNotSupported(c, err) {
+				return
+			}
+			switch errors.Cause(err) {
+			case database.ErrInvalidSchema, auth.ErrInvalidSignature:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case database.ErrNotAuthorized:
+				c.JSON(http.StatusForbidden, gin.H{"error": "only admins can set schemas"})
+			case database.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.Header("ETag", etagOf(schema.Version))
+		c.JSON(http.StatusOK, schemaToJson(schema))
+	}
+}
+
+func handleGetSchema(db database.SchemaRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var version uint64
+		if rawVersion := c.Query("version"); rawVersion != "" {
+			var err error
+			if version, err = strconv.ParseUint(rawVersion, 10, 64); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version: " + err.Error()})
+				return
+			}
+		}
+		schema, err := db.GetSchema(c.Request.Context(), c.Param("type"), version)
+		if err != nil {
+			if respondNotSupported(c, err) {
+				return
+			}
+			if err == database.ErrNotExists {
+				c.JSON(http.StatusNotFound, gin.H{"error": "schema not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("ETag", etagOf(schema.Version))
+		c.JSON(http.StatusOK, schemaToJson(schema))
+	}
+}
+
+func handleListSchemas(db database.SchemaRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schemas, err := db.ListSchemas(c.Request.Context())
+		if err != nil {
+			if respondNotSupported(c, err) {
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		results := make([]gin.H, len(schemas))
+		for i, schema := range schemas {
+			results[i] = schemaToJson(schema)
+		}
+		c.JSON(http.StatusOK, gin.H{"schemas": results})
+	}
+}
+
+// handleGetUsage responds the usage of the owner, which can be read only by the owner and the admins.
+func handleGetUsage(db database.Meter, config *database.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !common.IsHexAddress(c.Param("owner")) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid owner: " + c.Param("owner")})
+			return
+		}
+		owner := common.HexToAddress(c.Param("owner"))
+		reader, ok := readerOf(c, config.Domain)
+		if !ok {
+			return
+		}
+		if reader == nil || (*reader != owner && !config.IsAdmin(*reader)) {
+			c.JSON(readDeniedStatus(reader), gin.H{"error": "you're not authorized to read the usage"})
+			return
+		}
+		usage, err := db.Usage(c.Request.Context(), owner)
+		if err != nil {
+			if respondNotSupported(c, err) {
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, usageToJson(usage))
+	}
+}
+
+func handleDeposit(db database.Meter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DepositRequest
+		if err := bindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !common.IsHexAddress(c.Param("owner")) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid owner: " + c.Param("owner")})
+			return
+		}
+
+		sig, err := hexutil.Decode(req.Signature)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + err.Error()})
+			return
+		}
+		if len(sig) != 65 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid signature: " + msgInvalidSigLength})
+			return
+		}
+
+		usage, err := db.Deposit(c.Request.Context(), common.HexToAddress(c.Param("owner")), req.Amount, sig)
+		if err != nil {
+			if respondNotSupported(c, err) {
+				return
+			}
+			switch errors.Cause(err) {
+			case auth.ErrInvalidSignature:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case database.ErrNotAuthorized:
+				c.JSON(http.StatusForbidden, gin.H{"error": "only admins can deposit"})
+			case database.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		c.JSON(http.StatusOK, usageToJson(usage))
+	}
+}
+
+// respondNotSupported responds 501 Not Implemented if the backend behind a wrapper
+// does not implement the optional interface of the operation.
+func respondNotSupported(c *gin.Context, err error) bool {
+	if errors.Cause(err) != database.ErrNotSupported {
+		return false
+	}
+	c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+	return true
+}
+
+// batchErrorStatus returns a HTTP status code of the error failing the whole batch.
+func batchErrorStatus(err error) int {
+	switch errors.Cause(err) {
+	case database.ErrBatchTooLarge, database.ErrDuplicatedKey:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// itemErrorToJson returns an error of an item in a batch,
+// with the HTTP status code which would be returned for a single object.
+func itemErrorToJson(err error) gin.H {
+	if schemaErr, ok := errors.Cause(err).(*database.SchemaError); ok {
+		res := schemaErrorToJson(schemaErr)
+		res["status"] = http.StatusBadRequest
+		return res
+	}
+	switch errors.Cause(err) {
+	case database.ErrNotExists:
+		return gin.H{"status": http.StatusNotFound, "error": "resource not found"}
+	case database.ErrInvalidID, database.ErrInvalidType, database.ErrInvalidExpiry, database.ErrReservedKey, auth.ErrInvalidSignature:
+		return gin.H{"status": http.StatusBadRequest, "error": err.Error()}
+	case database.ErrNotAuthorized:
+		return gin.H{"status": http.StatusForbidden, "error": err.Error()}
+	case database.ErrConflict:
+		return gin.H{"status": http.StatusConflict, "error": err.Error()}
+	case database.ErrQuotaExceeded:
+		return gin.H{"status": http.StatusPaymentRequired, "error": err.Error()}
+	}
+	if limitErr, ok := errors.Cause(err).(*database.RateLimitError); ok {
+		return gin.H{
+			"status":     http.StatusTooManyRequests,
+			"error":      err.Error(),
+			"retryAfter": database.RetryAfterSeconds(limitErr.RetryAfter),
+		}
+	}
+	return gin.H{"status": http.StatusInternalServerError, "error": err.Error()}
+}
+
+// schemaErrorToJson returns an error with the violations of the schema.
+func schemaErrorToJson(err *database.SchemaError) gin.H {
+	return gin.H{
+		"error":         err.Error(),
+		"violations":    err.Violations,
+		"schemaVersion": err.Version,
+	}
+}
+
+func schemaToJson(schema *database.Schema) gin.H {
 	return gin.H{
+		"type":       schema.Type,
+		"version":    schema.Version,
+		"definition": json.RawMessage(schema.Definition),
+		"signer":     schema.Signer.Hex(),
+		"createdAt":  schema.CreatedAt,
+	}
+}
+
+func usageToJson(usage *database.Usage) gin.H {
+	return gin.H{
+		"owner":     usage.Owner.Hex(),
+		"writes":    usage.Writes,
+		"feeUsed":   usage.FeeUsed,
+		"deposited": usage.Deposited,
+		"deposits":  usage.Deposits,
+		"balance":   usage.Balance(),
+	}
+}
+
+func objectToJson(obj *database.Object) gin.H {
+	if !database.IsLive(obj) {
+		// tombstones only retain the version, and expired objects are shown as tombstones
+		return gin.H{"version": obj.Version, "deleted": true}
+	}
+	res := gin.H{
 		"data":          obj.Data,
-		"owner":         ownerAddr.Hex(),
+		"owner":         pubkeyToAddress(obj.Owner),
+		"version":       obj.Version,
+		"private":       obj.Private,
 		"createdAt":     obj.CreatedAt,
 		"lastUpdatedAt": obj.LastUpdatedAt,
 	}
+	if !obj.ExpiresAt.IsZero() {
+		res["expiresAt"] = obj.ExpiresAt
+	}
+	if obj.ACL != nil {
+		res["acl"] = gin.H{
+			"writers": obj.ACL.Writers,
+			"readers": obj.ACL.Readers,
+		}
+	}
+	return res
+}
+
+// etagOf returns an entity tag of given version of the object.
+func etagOf(version uint64) string {
+	return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+// parseETag returns the version from given entity tag.
+func parseETag(etag string) (uint64, error) {
+	etag = strings.TrimPrefix(etag, "W/")
+	if unquoted, err := strconv.Unquote(etag); err == nil {
+		etag = unquoted
+	}
+	return strconv.ParseUint(etag, 10, 64)
+}
+
+// pubkeyToAddress returns the address of given public key in hex,
+// or an empty string for the empty key of tombstones.
+func pubkeyToAddress(key auth.PublicKey) string {
+	pub, err := crypto.DecompressPubkey(key[:])
+	if err != nil {
+		return ""
+	}
+	return crypto.PubkeyToAddress(*pub).Hex()
 }
@@ -0,0 +1,123 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/database/databasetest"
+	"github.com/gin-gonic/gin"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var sign = databasetest.Sign
+
+// newTestServer returns a router serving the API of a new in-memory database.
+func newTestServer(t *testing.T) (*gin.Engine, database.Database) {
+	gin.SetMode(gin.TestMode)
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+
+	r := gin.New()
+	RegisterV1API(r, db, database.NewConfig())
+	return r, db
+}
+
+// serve sends a request with given JSON body to the router, and returns the response.
+func serve(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPutObject_InvalidSignature(t *testing.T) {
+	r, _ := newTestServer(t)
+
+	// no public key can be recovered from a zero signature
+	sig := hexutil.Encode(make([]byte, 65))
+	w := serve(r, http.MethodPost, "/v1/object/testdata/1", `{"data": {"foo": "bar"}, "signature": "`+sig+`"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestDeleteObject_InvalidSignature(t *testing.T) {
+	r, db := newTestServer(t)
+	priv, _ := crypto.GenerateKey()
+	data := database.Payload{"foo": "bar"}
+	_, err := db.Put(context.TODO(), "testdata", "1", data, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, data)), database.PutOptions{})
+	require.NoError(t, err)
+
+	sig := hexutil.Encode(make([]byte, 65))
+	w := serve(r, http.MethodDelete, "/v1/object/testdata/1", `{"signature": "`+sig+`"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	// signatures of others are recovered, but they are not authorized
+	other, _ := crypto.GenerateKey()
+	sig = hexutil.Encode(sign(t, other, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 2)))
+	w = serve(r, http.MethodDelete, "/v1/object/testdata/1", `{"signature": "`+sig+`"}`)
+	require.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+}
+
+func TestBatchPut_InvalidID(t *testing.T) {
+	r, _ := newTestServer(t)
+	priv, _ := crypto.GenerateKey()
+	data := database.Payload{"foo": "bar"}
+
+	sig := hexutil.Encode(sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1/1", 1, data)))
+	w := serve(r, http.MethodPost, "/v1/batch/put", `{"items": [{"type": "testdata", "id": "1/1", "data": {"foo": "bar"}, "signature": "`+sig+`"}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), `"status":400`)
+}
+
+func TestRegisterV1API_OptionalCapabilities(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	imdb, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+
+	// the backend only implements database.Database
+	r := gin.New()
+	RegisterV1API(r, struct{ database.Database }{imdb}, database.NewConfig())
+	w := serve(r, http.MethodPost, "/v1/batch/get", `{"keys": [{"type": "testdata", "id": "1"}]}`)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(r, http.MethodGet, "/v1/", "")
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetObject_Private(t *testing.T) {
+	r, db := newTestServer(t)
+	owner, _ := crypto.GenerateKey()
+	ctx := context.TODO()
+
+	data := database.Payload{"foo": "bar"}
+	_, err := db.Put(ctx, "testdata", "1", data, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, data)), database.PutOptions{Private: true})
+	require.NoError(t, err)
+	public := database.Payload{"foo": "baz"}
+	_, err = db.Put(ctx, "testdata", "2", public, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, public)), database.PutOptions{})
+	require.NoError(t, err)
+
+	// anonymous readers can neither get nor find the private object
+	w := serve(r, http.MethodGet, "/v1/object/testdata/1", "")
+	require.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+	w = serve(r, http.MethodGet, "/v1/object/testdata/1/history", "")
+	require.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+	w = serve(r, http.MethodGet, "/v1/object/testdata", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), `"foo":"baz"`)
+	require.NotContains(t, w.Body.String(), `"foo":"bar"`)
+
+	// the owner can read it with a read token
+	token, err := auth.NewReadToken(auth.DefaultDomain, owner, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/v1/object/testdata/1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
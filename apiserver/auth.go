@@ -0,0 +1,51 @@
+package apiserver
+
+import (
+	"github.com/airbloc/airframe/auth"
+	"github.com/airbloc/airframe/database"
+	"github.com/gin-gonic/gin"
+	"github.com/klaytn/klaytn/common"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// readerOf returns the address of the reader from the read token for the domain in the Authorization header,
+// or nil if no token is given. It responds 401 Unauthorized and returns false for invalid tokens.
+func readerOf(c *gin.Context, domain string) (*common.Address, bool) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return nil, true
+	}
+	reader, err := auth.VerifyReadToken(domain, strings.TrimPrefix(header, "Bearer "), time.Now())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &reader, true
+}
+
+// readDeniedStatus returns a HTTP status code for reading a private object which the reader cannot read.
+func readDeniedStatus(reader *common.Address) int {
+	if reader == nil {
+		return http.StatusUnauthorized
+	}
+	return http.StatusForbidden
+}
+
+// checkCurrentRead responds an error and returns false if the reader cannot read the current object.
+// Deleted objects are readable, since their data have been erased.
+func checkCurrentRead(c *gin.Context, db database.Database, reader *common.Address) bool {
+	current, err := db.Get(c.Request.Context(), c.Param("type"), c.Param("id"))
+	if err == database.ErrNotExists {
+		return true
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !database.CanRead(current, reader) {
+		c.JSON(readDeniedStatus(reader), gin.H{"error": "you're not authorized to read the object"})
+		return false
+	}
+	return true
+}
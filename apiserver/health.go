@@ -0,0 +1,44 @@
+package apiserver
+
+import (
+	"context"
+	"github.com/airbloc/airframe/database"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// readinessTimeout is the timeout of pinging the database on readiness probes.
+const readinessTimeout = 5 * time.Second
+
+// RegisterHealthCheck registers probes which never write to the database.
+// GET /healthz is a liveness probe which succeeds while the server is running,
+// and GET /readyz is a readiness probe which succeeds if the database can serve requests.
+func RegisterHealthCheck(r *gin.Engine, db database.Database) {
+	r.GET("/healthz", handleLiveness())
+	r.GET("/readyz", handleReadiness(db))
+}
+
+func handleLiveness() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+func handleReadiness(db database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pinger, ok := db.(database.Pinger)
+		if !ok {
+			// the backend is always ready
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		defer cancel()
+		if err := pinger.Ping(ctx); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
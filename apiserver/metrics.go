@@ -0,0 +1,48 @@
+package apiserver
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"strconv"
+	"time"
+)
+
+var httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "airframe",
+	Subsystem: "http",
+	Name:      "request_duration_seconds",
+	Help:      "Latencies of HTTP requests by route and status code.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+func init() {
+	prometheus.MustRegister(httpRequestDuration)
+}
+
+// RegisterMetrics registers GET /metrics, which exposes the metrics in Prometheus format.
+func RegisterMetrics(r *gin.Engine) {
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+}
+
+// Metrics observes the latency of every request by route. See routeOf for the names of the routes.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		httpRequestDuration.
+			WithLabelValues(c.Request.Method, routeOf(c), strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// routeOf returns the route of the request, which is the path registered in the engine
+// such as "/v1/object/:type/:id", so that the values of the parameters do not make new series.
+// Requests to unknown paths are named "unmatched".
+func routeOf(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
@@ -0,0 +1,59 @@
+package apiserver
+
+import (
+	"github.com/airbloc/airframe/database"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit limits the rate of reads by the API key in X-API-Key header if it is one of the API keys,
+// or the IP of the client otherwise. Writes are limited by the IP of the client with the write limiter
+// before their signatures are recovered, and then by the signer in the database since the signer
+// is recovered with the current version of the object. See database.WithWriteLimiter.
+// Nil limiters allow every request.
+func RateLimit(readLimiter, writeLimiter database.RateLimiter, apiKeys map[string]bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limiter, key := writeLimiter, c.ClientIP()
+		if isRead(c.Request) {
+			limiter = readLimiter
+			if apiKey := c.GetHeader("X-API-Key"); apiKeys[apiKey] {
+				key = apiKey
+			}
+		}
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		if retryAfter := limiter.Take(key); retryAfter > 0 {
+			abortRateLimited(c, retryAfter, "too many requests")
+			return
+		}
+		c.Next()
+	}
+}
+
+// isRead returns true if the request only reads objects.
+func isRead(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead || r.URL.Path == "/v1/batch/get"
+}
+
+// respondRateLimited responds 429 Too Many Requests and returns true if the signer
+// of the write has exceeded the rate limit.
+func respondRateLimited(c *gin.Context, err error) bool {
+	limitErr, ok := errors.Cause(err).(*database.RateLimitError)
+	if !ok {
+		return false
+	}
+	abortRateLimited(c, limitErr.RetryAfter, err.Error())
+	return true
+}
+
+// abortRateLimited responds 429 Too Many Requests with a Retry-After header in seconds.
+func abortRateLimited(c *gin.Context, retryAfter time.Duration, msg string) {
+	seconds := database.RetryAfterSeconds(retryAfter)
+	c.Header("Retry-After", strconv.FormatInt(seconds, 10))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": msg, "retryAfter": seconds})
+}
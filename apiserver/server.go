@@ -17,16 +17,39 @@ type Server struct {
 	server *http.Server
 }
 
-func New(backend database.Database, port int, debug bool) *Server {
+// Options are the options of the server.
+type Options struct {
+	// Config is the configuration of the backend. Read tokens are verified with its signing domain,
+	// and its admins can read the usage of every owner.
+	Config *database.Config
+
+	// ReadLimiter limits the rate of reads by the API key or the IP of the client,
+	// and WriteLimiter limits the rate of writes by the IP. Nil limiters allow every request.
+	ReadLimiter  database.RateLimiter
+	WriteLimiter database.RateLimiter
+
+	// APIKeys are the API keys which reads are limited by. Reads with other keys are limited by the IP.
+	APIKeys map[string]bool
+}
+
+// New creates a HTTP server of the backend with given options.
+func New(backend database.Database, port int, debug bool, options Options) *Server {
 	if !debug {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	r := gin.New()
 	r.Use(loggergin.Middleware("api"))
+	r.Use(Tracing())
+	r.Use(Metrics())
 	r.Use(Recovery())
+
+	// probes and metrics are registered before the rate limit, so that they are never limited
+	RegisterHealthCheck(r, backend)
+	RegisterMetrics(r)
+	r.Use(RateLimit(options.ReadLimiter, options.WriteLimiter, options.APIKeys))
 	r.NoRoute(NotFound())
 
-	RegisterV1API(r, backend)
+	RegisterV1API(r, backend, options.Config)
 
 	return &Server{
 		server: &http.Server{
@@ -0,0 +1,59 @@
+package apiserver
+
+import (
+	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+var tracer = tracing.Tracer("apiserver")
+
+// Tracing starts a span of every request, continuing the trace of the caller in the headers.
+// The context of the span is set to the request, so that handlers should pass
+// c.Request.Context() to the database instead of the gin context.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := routeOf(c)
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("client.address", c.ClientIP()),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// bindJSON parses the body of the request like c.ShouldBindJSON, in a span.
+func bindJSON(c *gin.Context, req interface{}) error {
+	_, span := tracer.Start(c.Request.Context(), "apiserver.bindJSON",
+		trace.WithAttributes(attribute.Int64("http.request.body.size", c.Request.ContentLength)))
+	err := c.ShouldBindJSON(req)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// parseQuery parses the JSON query in a span.
+func parseQuery(c *gin.Context, raw string) (*database.Query, error) {
+	_, span := tracer.Start(c.Request.Context(), "apiserver.parseQuery", trace.WithAttributes(attribute.Int("query.size", len(raw))))
+	query, err := database.QueryFromJson(raw)
+	tracing.EndSpan(span, err)
+	return query, err
+}
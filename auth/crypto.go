@@ -1,25 +1,110 @@
 package auth
 
 import (
+	"context"
 	"fmt"
+	"github.com/airbloc/airframe/tracing"
 	"github.com/json-iterator/go"
+	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
 	"golang.org/x/crypto/sha3"
+	"strings"
 )
 
+// DefaultDomain is the signing domain of deployments which do not set their own.
+// The domain is included in every signed preimage, so that signatures for an Airframe
+// deployment cannot be replayed to another one. Clients should use the same domain with the server.
+const DefaultDomain = "airframe"
+
 var (
 	json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+	tracer = tracing.Tracer("auth")
 )
 
+// ErrInvalidSignature is raised when no public key can be recovered from a signature.
+var ErrInvalidSignature = errors.New("invalid signature.")
+
 // PublicKey is 33-byte compressed ECDSA public key.
 type PublicKey [33]byte
 
 // GetOwnerFromSignature returns 33-byte PublicKey from given signature.
-func GetSigner(typ, id string, data interface{}, sig []byte) (PublicKey, error) {
-	hash := GetObjectHash(typ, id, data)
+func GetSigner(ctx context.Context, domain, typ, id string, version uint64, data interface{}, sig []byte) (PublicKey, error) {
+	hash := GetObjectHash(domain, typ, id, version, data)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// GetExpiringSigner returns 33-byte PublicKey from given signature for writing the object expiring at given unix time.
+func GetExpiringSigner(ctx context.Context, domain, typ, id string, version uint64, data interface{}, expiresAt int64, sig []byte) (PublicKey, error) {
+	hash := GetExpiringObjectHash(domain, typ, id, version, data, expiresAt)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// GetDeleteSigner returns 33-byte PublicKey from given signature for deleting the object.
+func GetDeleteSigner(ctx context.Context, domain, typ, id string, version uint64, sig []byte) (PublicKey, error) {
+	hash := GetDeleteHash(domain, typ, id, version)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// GetPatchSigner returns 33-byte PublicKey from given signature for patching the object.
+func GetPatchSigner(ctx context.Context, domain, typ, id string, baseVersion uint64, patchType string, patch []byte, sig []byte) (PublicKey, error) {
+	hash := GetPatchHash(domain, typ, id, baseVersion, patchType, patch)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// GetTransferSigner returns 33-byte PublicKey from given signature for transferring the object.
+func GetTransferSigner(ctx context.Context, domain, typ, id string, version uint64, to common.Address, sig []byte) (PublicKey, error) {
+	hash := GetTransferHash(domain, typ, id, version, to)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// GetAcceptTransferSigner returns 33-byte PublicKey from given signature for accepting the transfer.
+func GetAcceptTransferSigner(ctx context.Context, domain, typ, id string, version uint64, to common.Address, sig []byte) (PublicKey, error) {
+	hash := GetAcceptTransferHash(domain, typ, id, version, to)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// GetACLSigner returns 33-byte PublicKey from given signature for setting the access control list of the object.
+func GetACLSigner(ctx context.Context, domain, typ, id string, version uint64, writers, readers []common.Address, sig []byte) (PublicKey, error) {
+	hash := GetACLHash(domain, typ, id, version, writers, readers)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// GetVisibilitySigner returns 33-byte PublicKey from given signature for changing the visibility of the object.
+func GetVisibilitySigner(ctx context.Context, domain, typ, id string, version uint64, private bool, sig []byte) (PublicKey, error) {
+	hash := GetVisibilityHash(domain, typ, id, version, private)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// GetSchemaSigner returns 33-byte PublicKey from given signature for setting the schema of the type.
+func GetSchemaSigner(ctx context.Context, domain, typ string, version uint64, definition string, sig []byte) (PublicKey, error) {
+	hash := GetSchemaHash(domain, typ, version, definition)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// GetDepositSigner returns 33-byte PublicKey from given signature for depositing to the prepaid quota of the owner.
+func GetDepositSigner(ctx context.Context, domain string, owner common.Address, deposits uint64, amount uint64, sig []byte) (PublicKey, error) {
+	hash := GetDepositHash(domain, owner, deposits, amount)
+	return recoverSigner(ctx, hash, sig)
+}
+
+// AddressOf returns the address of given public key.
+func AddressOf(key PublicKey) (common.Address, error) {
+	pub, err := crypto.DecompressPubkey(key[:])
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// recoverSigner recovers the public key from the signature of given hash, in a span.
+func recoverSigner(ctx context.Context, hash [32]byte, sig []byte) (PublicKey, error) {
+	_, span := tracer.Start(ctx, "auth.recoverSigner")
 	pubkey, err := crypto.SigToPub(hash[:], sig)
+	tracing.EndSpan(span, err)
 	if err != nil {
-		return PublicKey{}, err
+		return PublicKey{}, errors.Wrap(ErrInvalidSignature, err.Error())
 	}
 	signerPub := crypto.CompressPubkey(pubkey)
 	var p PublicKey
@@ -27,8 +112,91 @@ func GetSigner(typ, id string, data interface{}, sig []byte) (PublicKey, error)
 	return p, nil
 }
 
-func GetObjectHash(typ, id string, data interface{}) [32]byte {
+// GetObjectHash returns a hash to be signed for writing given data to the object.
+// The version is the version of the object after the write, which is 1 on creation
+// and increased by 1 on every update. Since the version is signed together,
+// a signature cannot be replayed once the object is updated.
+func GetObjectHash(domain, typ, id string, version uint64, data interface{}) [32]byte {
+	rawData, _ := json.MarshalToString(data)
+	preimage := fmt.Sprintf("%s/%s/%s/%d/%s", domain, typ, id, version, rawData)
+	return sha3.Sum256([]byte(preimage))
+}
+
+// GetExpiringObjectHash returns a hash to be signed for writing given data to the object
+// which expires at given unix time. It is prefixed differently from GetObjectHash,
+// so that the expiry cannot be stripped from the signed write.
+func GetExpiringObjectHash(domain, typ, id string, version uint64, data interface{}, expiresAt int64) [32]byte {
 	rawData, _ := json.MarshalToString(data)
-	preimage := fmt.Sprintf("%s/%s/%s", typ, id, rawData)
+	preimage := fmt.Sprintf("expiring:%s/%s/%s/%d/%d/%s", domain, typ, id, version, expiresAt, rawData)
+	return sha3.Sum256([]byte(preimage))
+}
+
+// GetDeleteHash returns a hash to be signed for deleting the object.
+// Like GetObjectHash, the version is the version of the object after the deletion.
+// The preimage is prefixed differently, so that a signature for writing
+// cannot be used for deletion, and vice versa.
+func GetDeleteHash(domain, typ, id string, version uint64) [32]byte {
+	preimage := fmt.Sprintf("delete:%s/%s/%s/%d", domain, typ, id, version)
+	return sha3.Sum256([]byte(preimage))
+}
+
+// GetPatchHash returns a hash to be signed for applying given patch document to the object.
+// Unlike GetObjectHash, the version is the base version which the patch is applied to,
+// and the patch document is signed as it is.
+func GetPatchHash(domain, typ, id string, baseVersion uint64, patchType string, patch []byte) [32]byte {
+	preimage := fmt.Sprintf("patch:%s/%s/%s/%d/%s/%s", domain, typ, id, baseVersion, patchType, patch)
+	return sha3.Sum256([]byte(preimage))
+}
+
+// GetTransferHash returns a hash to be signed by the current owner for transferring
+// the object to given address. Like GetObjectHash, the version is the version of
+// the object after the transfer.
+func GetTransferHash(domain, typ, id string, version uint64, to common.Address) [32]byte {
+	preimage := fmt.Sprintf("transfer:%s/%s/%s/%d/%s", domain, typ, id, version, to.Hex())
+	return sha3.Sum256([]byte(preimage))
+}
+
+// GetAcceptTransferHash returns a hash to be signed by the new owner for accepting the transfer.
+// It is prefixed differently from GetTransferHash, so that the signature of
+// the current owner cannot be used as the acceptance.
+func GetAcceptTransferHash(domain, typ, id string, version uint64, to common.Address) [32]byte {
+	preimage := fmt.Sprintf("accept-transfer:%s/%s/%s/%d/%s", domain, typ, id, version, to.Hex())
+	return sha3.Sum256([]byte(preimage))
+}
+
+// GetACLHash returns a hash to be signed by the owner for setting the access control list
+// of the object. Like GetObjectHash, the version is the version of the object after the change.
+func GetACLHash(domain, typ, id string, version uint64, writers, readers []common.Address) [32]byte {
+	preimage := fmt.Sprintf("acl:%s/%s/%s/%d/%s/%s", domain, typ, id, version, joinAddresses(writers), joinAddresses(readers))
+	return sha3.Sum256([]byte(preimage))
+}
+
+func joinAddresses(addrs []common.Address) string {
+	hexes := make([]string, len(addrs))
+	for i, addr := range addrs {
+		hexes[i] = addr.Hex()
+	}
+	return strings.Join(hexes, ",")
+}
+
+// GetVisibilityHash returns a hash to be signed by the owner for making the object private or public.
+// Like GetObjectHash, the version is the version of the object after the change.
+func GetVisibilityHash(domain, typ, id string, version uint64, private bool) [32]byte {
+	preimage := fmt.Sprintf("visibility:%s/%s/%s/%d/%t", domain, typ, id, version, private)
+	return sha3.Sum256([]byte(preimage))
+}
+
+// GetSchemaHash returns a hash to be signed by an admin for setting the JSON Schema of the type.
+// The version is the version of the schema after the change, and the definition is signed as it is.
+func GetSchemaHash(domain, typ string, version uint64, definition string) [32]byte {
+	preimage := fmt.Sprintf("schema:%s/%s/%d/%s", domain, typ, version, definition)
+	return sha3.Sum256([]byte(preimage))
+}
+
+// GetDepositHash returns a hash to be signed by an admin for depositing given amount to the prepaid quota
+// of the owner. The deposits is the number of the deposits of the owner after this one,
+// so that a signature cannot be replayed like the versions of objects.
+func GetDepositHash(domain string, owner common.Address, deposits uint64, amount uint64) [32]byte {
+	preimage := fmt.Sprintf("deposit:%s/%s/%d/%d", domain, owner.Hex(), deposits, amount)
 	return sha3.Sum256([]byte(preimage))
 }
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxReadTokenTTL is the maximum lifetime of read tokens.
+// Tokens expiring later than that are rejected, so that leaked tokens cannot be used for long.
+const MaxReadTokenTTL = 10 * time.Minute
+
+var (
+	ErrInvalidToken = errors.New("invalid read token.")
+	ErrTokenExpired = errors.New("the read token has been expired.")
+)
+
+// NewReadToken issues a read token proving the control of given key until given time,
+// with a format of "<expiry in unix seconds>.<signature in hex>".
+// Servers recover the address of the reader from the token to authorize reads of private objects.
+func NewReadToken(domain string, key *ecdsa.PrivateKey, expiresAt time.Time) (string, error) {
+	hash := GetReadTokenHash(domain, expiresAt.Unix())
+	sig, err := crypto.Sign(hash[:], key)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign read token")
+	}
+	return fmt.Sprintf("%d.%s", expiresAt.Unix(), hexutil.Encode(sig)), nil
+}
+
+// VerifyReadToken returns the address of the reader who issued given token.
+// ErrTokenExpired is returned if the token has been expired or lives longer than MaxReadTokenTTL.
+func VerifyReadToken(domain, token string, now time.Time) (common.Address, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return common.Address{}, ErrInvalidToken
+	}
+	expiresAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return common.Address{}, ErrInvalidToken
+	}
+	if expiry := time.Unix(expiresAt, 0); !expiry.After(now) || expiry.Sub(now) > MaxReadTokenTTL {
+		return common.Address{}, ErrTokenExpired
+	}
+	sig, err := hexutil.Decode(parts[1])
+	if err != nil || len(sig) != 65 {
+		return common.Address{}, ErrInvalidToken
+	}
+	hash := GetReadTokenHash(domain, expiresAt)
+	pub, err := crypto.SigToPub(hash[:], sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidToken
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// GetReadTokenHash returns a hash to be signed for issuing a read token expiring at given unix time.
+// It is prefixed differently from the hashes of writes, so that a read token cannot be used for writes.
+func GetReadTokenHash(domain string, expiresAt int64) [32]byte {
+	preimage := fmt.Sprintf("read:%s/%d", domain, expiresAt)
+	return sha3.Sum256([]byte(preimage))
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"fmt"
+	"github.com/klaytn/klaytn/common/hexutil"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestVerifyReadToken(t *testing.T) {
+	priv, _ := crypto.GenerateKey()
+	now := time.Now()
+
+	token, err := NewReadToken(DefaultDomain, priv, now.Add(time.Minute))
+	require.NoError(t, err)
+	reader, err := VerifyReadToken(DefaultDomain, token, now)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(priv.PublicKey), reader)
+}
+
+func TestVerifyReadToken_Expired(t *testing.T) {
+	priv, _ := crypto.GenerateKey()
+	now := time.Now()
+
+	token, err := NewReadToken(DefaultDomain, priv, now.Add(time.Minute))
+	require.NoError(t, err)
+	_, err = VerifyReadToken(DefaultDomain, token, now.Add(time.Minute))
+	require.Equal(t, ErrTokenExpired, err)
+
+	// tokens living longer than MaxReadTokenTTL are rejected even before their expiry
+	token, err = NewReadToken(DefaultDomain, priv, now.Add(MaxReadTokenTTL+time.Minute))
+	require.NoError(t, err)
+	_, err = VerifyReadToken(DefaultDomain, token, now)
+	require.Equal(t, ErrTokenExpired, err)
+}
+
+func TestVerifyReadToken_WrongDomain(t *testing.T) {
+	priv, _ := crypto.GenerateKey()
+	now := time.Now()
+
+	// the signature is made for another domain, so that another address is recovered
+	token, err := NewReadToken("other", priv, now.Add(time.Minute))
+	require.NoError(t, err)
+	reader, err := VerifyReadToken(DefaultDomain, token, now)
+	require.NoError(t, err)
+	require.NotEqual(t, crypto.PubkeyToAddress(priv.PublicKey), reader)
+}
+
+func TestVerifyReadToken_Malformed(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Minute).Unix()
+
+	for _, token := range []string{
+		"",
+		"invalid",
+		fmt.Sprintf("%d", expiresAt),
+		fmt.Sprintf("soon.%s", hexutil.Encode(make([]byte, 65))),
+		fmt.Sprintf("%d.not-hex", expiresAt),
+		fmt.Sprintf("%d.%s", expiresAt, hexutil.Encode(make([]byte, 64))),
+		fmt.Sprintf("%d.%s", expiresAt, hexutil.Encode(make([]byte, 65))),
+	} {
+		_, err := VerifyReadToken(DefaultDomain, token, now)
+		require.Equal(t, ErrInvalidToken, err, token)
+	}
+}
+
+func TestVerifyReadToken_ReplayedWriteSignature(t *testing.T) {
+	priv, _ := crypto.GenerateKey()
+	now := time.Now()
+
+	// a signature of a write cannot be used as a read token of the writer
+	hash := GetObjectHash(DefaultDomain, "testdata", "1", 1, map[string]interface{}{"foo": "bar"})
+	sig, err := crypto.Sign(hash[:], priv)
+	require.NoError(t, err)
+	token := fmt.Sprintf("%d.%s", now.Add(time.Minute).Unix(), hexutil.Encode(sig))
+
+	reader, err := VerifyReadToken(DefaultDomain, token, now)
+	if err == nil {
+		require.NotEqual(t, crypto.PubkeyToAddress(priv.PublicKey), reader)
+	}
+}
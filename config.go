@@ -1,8 +1,10 @@
 package main
 
 import (
+	"github.com/airbloc/airframe/auth"
 	"github.com/spf13/pflag"
 	"os"
+	"time"
 
 	"github.com/airbloc/logger"
 )
@@ -13,6 +15,43 @@ type Config struct {
 	Port    int    `default:"8080"`
 	RpcPort int    `default:"9090"`
 	Backend string `default:"memory"`
+	DataDir string `default:"./data"`
+	Domain  string `default:"airframe"`
+
+	// Admins are hex addresses of the admins, who can manage the schemas of types.
+	Admins []string
+
+	// SweepInterval is the interval of sweeping expired objects in the backends.
+	SweepInterval time.Duration `default:"1m"`
+
+	// Fees charged to the payers of writes. FeeMultipliers are multipliers of the fees by type.
+	// If EnforceQuota is set, writes fail when the payer cannot afford the fee with the deposits.
+	FeePerWrite    uint64
+	FeePerByte     uint64
+	FeeMultipliers map[string]string
+	EnforceQuota   bool
+
+	// Rate limits of reads by API key or IP, and writes by signer and by IP, in requests per second.
+	// Writes are limited by IP before recovering their signatures. Requests are not limited if the rate is 0.
+	ReadRate     float64
+	ReadBurst    int
+	WriteRate    float64
+	WriteBurst   int
+	IPWriteRate  float64
+	IPWriteBurst int
+
+	// APIKeys are the API keys which reads are limited by. Reads with other keys are limited by IP.
+	APIKeys []string
+
+	// Tracing configurations. Spans are exported to stdout or an OTLP/HTTP collector.
+	TraceExporter    string `default:"none"`
+	OtlpEndpoint     string
+	TraceSampleRatio float64 `default:"1"`
+
+	// DynamoDB backend configurations
+	DynamoRegion      string
+	DynamoEndpoint    string
+	DynamoTablePrefix string `default:"airbloc_"`
 }
 
 // LoadConfigFrom loads default config.
@@ -21,6 +60,27 @@ func LoadConfig() *Config {
 	port := pflag.IntP("port", "p", 8080, "Port of API server.")
 	rpcPort := pflag.IntP("rpcport", "r", 9090, "Port of RPC server.")
 	backend := pflag.StringP("backend", "b", "memory", "Backend type. [memory|leveldb|dynamodb]")
+	dataDir := pflag.String("datadir", "./data", "Data directory of LevelDB backend.")
+	domain := pflag.String("domain", auth.DefaultDomain, "Signing domain which separates signatures of this deployment from others.")
+	admins := pflag.StringSlice("admin", nil, "Addresses of the admins who can manage the schemas of types.")
+	sweepInterval := pflag.Duration("sweep-interval", time.Minute, "Interval of sweeping expired objects.")
+	feePerWrite := pflag.Uint64("fee-per-write", 0, "Fee charged for every write.")
+	feePerByte := pflag.Uint64("fee-per-byte", 0, "Fee charged for every byte of the data stored by a write.")
+	feeMultipliers := pflag.StringToString("fee-multiplier", nil, "Multipliers of the fees by type. (e.g. logs=0.5,files=2)")
+	enforceQuota := pflag.Bool("enforce-quota", false, "Reject writes which the payer cannot afford with the deposits.")
+	readRate := pflag.Float64("read-rate", 0, "Rate limit of reads per second for each API key or IP. 0 for no limit.")
+	readBurst := pflag.Int("read-burst", 20, "Burst of reads for each API key or IP.")
+	writeRate := pflag.Float64("write-rate", 0, "Rate limit of writes per second for each signer. 0 for no limit.")
+	writeBurst := pflag.Int("write-burst", 10, "Burst of writes for each signer.")
+	ipWriteRate := pflag.Float64("ip-write-rate", 0, "Rate limit of writes per second for each IP. 0 for no limit.")
+	ipWriteBurst := pflag.Int("ip-write-burst", 20, "Burst of writes for each IP.")
+	apiKeys := pflag.StringSlice("api-key", nil, "API keys which reads are limited by, instead of the IP.")
+	traceExporter := pflag.String("trace-exporter", "none", "Exporter of the spans. [none|stdout|otlp]")
+	otlpEndpoint := pflag.String("otlp-endpoint", "", "URL of the OTLP/HTTP collector. (e.g. http://localhost:4318)")
+	traceSampleRatio := pflag.Float64("trace-sample-ratio", 1, "Ratio of the requests traced, unless the caller has decided.")
+	dynamoRegion := pflag.String("dynamodb-region", os.Getenv("AWS_REGION"), "AWS region of DynamoDB backend.")
+	dynamoEndpoint := pflag.String("dynamodb-endpoint", os.Getenv("DYNAMODB_ENDPOINT"), "Custom endpoint of DynamoDB (e.g. DynamoDB Local).")
+	dynamoTablePrefix := pflag.String("dynamodb-table-prefix", "airbloc_", "Prefix of DynamoDB table names.")
 	pflag.Parse()
 
 	// setup config from flag
@@ -28,6 +88,32 @@ func LoadConfig() *Config {
 		Port:    *port,
 		RpcPort: *rpcPort,
 		Backend: *backend,
+		DataDir: *dataDir,
+		Domain:  *domain,
+		Admins:  *admins,
+
+		SweepInterval: *sweepInterval,
+
+		FeePerWrite:    *feePerWrite,
+		FeePerByte:     *feePerByte,
+		FeeMultipliers: *feeMultipliers,
+		EnforceQuota:   *enforceQuota,
+
+		ReadRate:     *readRate,
+		ReadBurst:    *readBurst,
+		WriteRate:    *writeRate,
+		WriteBurst:   *writeBurst,
+		IPWriteRate:  *ipWriteRate,
+		IPWriteBurst: *ipWriteBurst,
+		APIKeys:      *apiKeys,
+
+		TraceExporter:    *traceExporter,
+		OtlpEndpoint:     *otlpEndpoint,
+		TraceSampleRatio: *traceSampleRatio,
+
+		DynamoRegion:      *dynamoRegion,
+		DynamoEndpoint:    *dynamoEndpoint,
+		DynamoTablePrefix: *dynamoTablePrefix,
 	}
 	if *isDev {
 		config.Profile = "dev"
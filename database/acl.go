@@ -0,0 +1,133 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/common"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// MaxACLSize is the maximum number of writers or readers in an access control list.
+const MaxACLSize = 100
+
+// ErrACLTooLarge is raised when an access control list has too many writers or readers.
+var ErrACLTooLarge = errors.Errorf("an access control list cannot have more than %d writers or readers.", MaxACLSize)
+
+// ACL is an access control list of an object, which grants permissions to addresses
+// other than the owner. Only the owner can change it.
+type ACL struct {
+	// Writers can update the object on behalf of the owner, but cannot delete or transfer it.
+	Writers []common.Address `dynamo:",omitempty"`
+
+	// Readers can read the object.
+	Readers []common.Address `dynamo:",omitempty"`
+}
+
+// CanWrite returns true if given address is one of the writers.
+func (acl *ACL) CanWrite(addr common.Address) bool {
+	return acl != nil && containsAddress(acl.Writers, addr)
+}
+
+// CanRead returns true if given address is one of the readers or the writers.
+func (acl *ACL) CanRead(addr common.Address) bool {
+	return acl != nil && (containsAddress(acl.Readers, addr) || containsAddress(acl.Writers, addr))
+}
+
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// CanRead returns true if the object is public, or given reader is the owner of the private
+// object or one of its readers and writers. The reader is nil for anonymous readers.
+func CanRead(obj *Object, reader *common.Address) bool {
+	if !obj.Private {
+		return true
+	}
+	if reader == nil {
+		return false
+	}
+	if owner, err := auth.AddressOf(obj.Owner); err == nil && owner == *reader {
+		return true
+	}
+	return obj.ACL.CanRead(*reader)
+}
+
+// canWrite returns true if given signer is the owner of the object or one of its writers.
+func canWrite(obj *Object, signer auth.PublicKey) bool {
+	if bytes.Equal(signer[:], obj.Owner[:]) {
+		return true
+	}
+	addr, err := auth.AddressOf(signer)
+	return err == nil && obj.ACL.CanWrite(addr)
+}
+
+// ApplyVisibility verifies that the visibility is signed by the owner for the next version
+// of the object, and returns the write of the object with the visibility.
+func ApplyVisibility(ctx context.Context, config *Config, current *Object, private bool, signature []byte, opts PutOptions) (*Write, error) {
+	if !IsLive(current) {
+		return nil, ErrNotExists
+	}
+	if err := opts.CheckVersion(current.Version); err != nil {
+		return nil, err
+	}
+	version := current.Version + 1
+	signer, err := auth.GetVisibilitySigner(ctx, config.Domain, current.Type, current.ID, version, private, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signature")
+	}
+	if !bytes.Equal(signer[:], current.Owner[:]) {
+		return nil, ErrNotAuthorized
+	}
+
+	updated := *current
+	updated.Private = private
+	updated.Version = version
+	updated.LastUpdatedAt = time.Now()
+	return &Write{
+		Object:   &updated,
+		Revision: NewRevision(&updated, signer, signature),
+	}, nil
+}
+
+// ApplyACL verifies that the access control list is signed by the owner for the next version
+// of the object, and returns the write of the object with the list.
+// An empty list removes every permission granted to others.
+func ApplyACL(ctx context.Context, config *Config, current *Object, acl *ACL, signature []byte, opts PutOptions) (*Write, error) {
+	if !IsLive(current) {
+		return nil, ErrNotExists
+	}
+	if len(acl.Writers) > MaxACLSize || len(acl.Readers) > MaxACLSize {
+		return nil, ErrACLTooLarge
+	}
+	if err := opts.CheckVersion(current.Version); err != nil {
+		return nil, err
+	}
+	version := current.Version + 1
+	signer, err := auth.GetACLSigner(ctx, config.Domain, current.Type, current.ID, version, acl.Writers, acl.Readers, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signature")
+	}
+	// only the owner can change the list, even if the signer is a writer
+	if !bytes.Equal(signer[:], current.Owner[:]) {
+		return nil, ErrNotAuthorized
+	}
+
+	updated := *current
+	updated.ACL = nil
+	if len(acl.Writers) > 0 || len(acl.Readers) > 0 {
+		updated.ACL = &ACL{Writers: acl.Writers, Readers: acl.Readers}
+	}
+	updated.Version = version
+	updated.LastUpdatedAt = time.Now()
+	return &Write{
+		Object:   &updated,
+		Revision: NewRevision(&updated, signer, signature),
+	}, nil
+}
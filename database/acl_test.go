@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestACL(t *testing.T) {
+	writer := common.HexToAddress("0x1")
+	reader := common.HexToAddress("0x2")
+	acl := &ACL{Writers: []common.Address{writer}, Readers: []common.Address{reader}}
+	require.True(t, acl.CanWrite(writer))
+	require.False(t, acl.CanWrite(reader))
+	require.True(t, acl.CanRead(writer))
+	require.True(t, acl.CanRead(reader))
+
+	var nilACL *ACL
+	require.False(t, nilACL.CanWrite(writer))
+	require.False(t, nilACL.CanRead(reader))
+}
+
+func TestInMemoryDatabase_SetACL(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	owner, _ := crypto.GenerateKey()
+	writer, _ := crypto.GenerateKey()
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// writers cannot update the object before being granted
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, writer, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	// only the owner can change the list
+	acl := &ACL{Writers: []common.Address{crypto.PubkeyToAddress(writer.PublicKey)}}
+	_, err = imdb.SetACL(ctx, "testdata", "1", acl, sign(t, writer, auth.GetACLHash(auth.DefaultDomain, "testdata", "1", 2, acl.Writers, acl.Readers)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	result, err := imdb.SetACL(ctx, "testdata", "1", acl, sign(t, owner, auth.GetACLHash(auth.DefaultDomain, "testdata", "1", 2, acl.Writers, acl.Readers)), PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result.Version)
+
+	// writers can update the object on behalf of the owner
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, writer, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 3, testData2)), PutOptions{})
+	require.NoError(t, err)
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, testData2, obj.Data)
+	require.Equal(t, acl.Writers, obj.ACL.Writers)
+	require.Equal(t, publicKeyOf(owner), obj.Owner)
+
+	// but cannot delete the object or change the list
+	require.Equal(t, ErrNotAuthorized, imdb.Delete(ctx, "testdata", "1", sign(t, writer, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 4))))
+	emptyACL := &ACL{}
+	_, err = imdb.SetACL(ctx, "testdata", "1", emptyACL, sign(t, writer, auth.GetACLHash(auth.DefaultDomain, "testdata", "1", 4, emptyACL.Writers, emptyACL.Readers)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	// revoke the permission
+	_, err = imdb.SetACL(ctx, "testdata", "1", emptyACL, sign(t, owner, auth.GetACLHash(auth.DefaultDomain, "testdata", "1", 4, emptyACL.Writers, emptyACL.Readers)), PutOptions{})
+	require.NoError(t, err)
+	obj, err = imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Nil(t, obj.ACL)
+	_, err = imdb.Put(ctx, "testdata", "1", testData1, sign(t, writer, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 5, testData1)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+}
+
+func TestInMemoryDatabase_TransferOwnership_RevokesACL(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	owner, _ := crypto.GenerateKey()
+	writer, _ := crypto.GenerateKey()
+	recipient, _ := crypto.GenerateKey()
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+	acl := &ACL{Writers: []common.Address{crypto.PubkeyToAddress(writer.PublicKey)}}
+	_, err = imdb.SetACL(ctx, "testdata", "1", acl, sign(t, owner, auth.GetACLHash(auth.DefaultDomain, "testdata", "1", 2, acl.Writers, acl.Readers)), PutOptions{})
+	require.NoError(t, err)
+
+	to := crypto.PubkeyToAddress(recipient.PublicKey)
+	transfer := &Transfer{To: to, NewOwner: publicKeyOf(recipient)}
+	_, err = imdb.TransferOwnership(ctx, "testdata", "1", transfer, sign(t, owner, auth.GetTransferHash(auth.DefaultDomain, "testdata", "1", 3, to)), PutOptions{})
+	require.NoError(t, err)
+
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, writer, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 4, testData2)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+}
+
+func TestCanRead(t *testing.T) {
+	owner, _ := crypto.GenerateKey()
+	ownerAddr := crypto.PubkeyToAddress(owner.PublicKey)
+	writer := common.HexToAddress("0x1")
+	reader := common.HexToAddress("0x2")
+	stranger := common.HexToAddress("0x3")
+
+	obj := &Object{
+		Owner: publicKeyOf(owner),
+		ACL:   &ACL{Writers: []common.Address{writer}, Readers: []common.Address{reader}},
+	}
+	require.True(t, CanRead(obj, nil))
+	require.True(t, CanRead(obj, &stranger))
+
+	obj.Private = true
+	require.False(t, CanRead(obj, nil))
+	require.False(t, CanRead(obj, &stranger))
+	require.True(t, CanRead(obj, &ownerAddr))
+	require.True(t, CanRead(obj, &writer))
+	require.True(t, CanRead(obj, &reader))
+}
+
+func TestInMemoryDatabase_SetVisibility(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	owner, _ := crypto.GenerateKey()
+	writer, _ := crypto.GenerateKey()
+	ownerAddr := crypto.PubkeyToAddress(owner.PublicKey)
+	writerAddr := crypto.PubkeyToAddress(writer.PublicKey)
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{Private: true})
+	require.NoError(t, err)
+	_, err = imdb.Put(ctx, "testdata", "2", testData1, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.True(t, obj.Private)
+
+	// private objects are only queried by those who can read them
+	result, err := imdb.Query(ctx, "testdata", nil, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+	require.Equal(t, "2", result.Objects[0].ID)
+
+	result, err = imdb.Query(ctx, "testdata", nil, QueryOptions{Reader: &writerAddr})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+
+	result, err = imdb.Query(ctx, "testdata", nil, QueryOptions{Reader: &ownerAddr})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(result.Objects))
+
+	// updates keep the visibility
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), PutOptions{})
+	require.NoError(t, err)
+	obj, err = imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.True(t, obj.Private)
+
+	// only the owner can change the visibility, even if the signer is a writer
+	acl := &ACL{Writers: []common.Address{writerAddr}}
+	_, err = imdb.SetACL(ctx, "testdata", "1", acl, sign(t, owner, auth.GetACLHash(auth.DefaultDomain, "testdata", "1", 3, acl.Writers, acl.Readers)), PutOptions{})
+	require.NoError(t, err)
+	_, err = imdb.SetVisibility(ctx, "testdata", "1", false, sign(t, writer, auth.GetVisibilityHash(auth.DefaultDomain, "testdata", "1", 4, false)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	written, err := imdb.SetVisibility(ctx, "testdata", "1", false, sign(t, owner, auth.GetVisibilityHash(auth.DefaultDomain, "testdata", "1", 4, false)), PutOptions{ExpectedVersion: 3})
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), written.Version)
+
+	result, err = imdb.Query(ctx, "testdata", nil, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(result.Objects))
+}
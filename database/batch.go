@@ -0,0 +1,76 @@
+package database
+
+import (
+	"github.com/pkg/errors"
+)
+
+// MaxBatchSize is the maximum number of objects in a batch,
+// which is the same with the limit of DynamoDB BatchGetItem.
+const MaxBatchSize = 100
+
+var (
+	ErrBatchTooLarge = errors.Errorf("a batch cannot have more than %d objects.", MaxBatchSize)
+	ErrDuplicatedKey = errors.New("a batch cannot have the same object twice.")
+)
+
+// ObjectKey identifies an object in a batch.
+type ObjectKey struct {
+	Type string
+	ID   string
+}
+
+// PutItem is a write of an object in a batch, signed for the object like Put.
+type PutItem struct {
+	ObjectKey
+	Data      Payload
+	Signature []byte
+	Options   PutOptions
+}
+
+// BatchGetResult is the result of an object in a batch get, in the same order with the keys.
+// Err is ErrNotExists if the object does not exist.
+type BatchGetResult struct {
+	Object *Object
+	Err    error
+}
+
+// BatchPutResult is the result of a write in a batch put, in the same order with the items.
+// A failed write does not affect the other writes in the batch.
+type BatchPutResult struct {
+	Result *PutResult
+	Err    error
+}
+
+// ValidateBatch checks the number of the objects in a batch, and their uniqueness.
+func ValidateBatch(keys []ObjectKey) error {
+	if len(keys) > MaxBatchSize {
+		return ErrBatchTooLarge
+	}
+	seen := make(map[ObjectKey]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			return ErrDuplicatedKey
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// KeysOf returns the keys of the objects written by given items.
+func KeysOf(items []PutItem) []ObjectKey {
+	keys := make([]ObjectKey, len(items))
+	for i, item := range items {
+		keys[i] = item.ObjectKey
+	}
+	return keys
+}
+
+// NewBatchGetResult returns the result of a batch get from the stored object including tombstones,
+// which is nil if the object has never existed. Tombstones and expired objects are returned
+// only if includeDeleted is true.
+func NewBatchGetResult(obj *Object, includeDeleted bool) *BatchGetResult {
+	if obj == nil || (!IsLive(obj) && !includeDeleted) {
+		return &BatchGetResult{Err: ErrNotExists}
+	}
+	return &BatchGetResult{Object: obj}
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func TestValidateBatch(t *testing.T) {
+	require.NoError(t, ValidateBatch([]ObjectKey{{"testdata", "1"}, {"testdata", "2"}, {"another", "1"}}))
+	require.Equal(t, ErrDuplicatedKey, ValidateBatch([]ObjectKey{{"testdata", "1"}, {"testdata", "1"}}))
+
+	keys := make([]ObjectKey, MaxBatchSize+1)
+	for i := range keys {
+		keys[i] = ObjectKey{Type: "testdata", ID: strconv.Itoa(i)}
+	}
+	require.Equal(t, ErrBatchTooLarge, ValidateBatch(keys))
+	require.NoError(t, ValidateBatch(keys[:MaxBatchSize]))
+}
+
+func TestInMemoryDatabase_BatchPut(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+
+	_, err := imdb.Put(ctx, "testdata", "2", testData1, sign(t, other, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	results, err := imdb.BatchPut(ctx, []PutItem{
+		{ObjectKey: ObjectKey{"testdata", "1"}, Data: testData1, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))},
+		{ObjectKey: ObjectKey{"testdata", "2"}, Data: testData2, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 2, testData2))},
+		{
+			ObjectKey: ObjectKey{"testdata", "3"},
+			Data:      testData1,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "3", 1, testData1)),
+			Options:   PutOptions{ExpectedVersion: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	// each write should fail independently
+	require.NoError(t, results[0].Err)
+	require.Equal(t, true, results[0].Result.Created)
+	require.Equal(t, uint64(1), results[0].Result.Version)
+	require.Equal(t, ErrNotAuthorized, results[1].Err)
+	require.Equal(t, ErrConflict, results[2].Err)
+
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, testData1, obj.Data)
+	exists, err := imdb.Exists(ctx, "testdata", "3")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = imdb.BatchPut(ctx, []PutItem{{ObjectKey: ObjectKey{"testdata", "1"}}, {ObjectKey: ObjectKey{"testdata", "1"}}})
+	require.Equal(t, ErrDuplicatedKey, err)
+}
+
+func TestInMemoryDatabase_BatchGet(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+	_, err = imdb.Put(ctx, "testdata", "2", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData2)), PutOptions{})
+	require.NoError(t, err)
+	require.NoError(t, imdb.Delete(ctx, "testdata", "2", sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "2", 2))))
+
+	keys := []ObjectKey{{"testdata", "1"}, {"testdata", "2"}, {"testdata", "3"}, {"another", "1"}}
+	results, err := imdb.BatchGet(ctx, keys, false)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, testData1, results[0].Object.Data)
+	require.Equal(t, ErrNotExists, results[1].Err)
+	require.Equal(t, ErrNotExists, results[2].Err)
+	require.Equal(t, ErrNotExists, results[3].Err)
+
+	// tombstones are returned with includeDeleted
+	results, err = imdb.BatchGet(ctx, keys, true)
+	require.NoError(t, err)
+	require.NoError(t, results[1].Err)
+	require.True(t, results[1].Object.Deleted)
+	require.Equal(t, uint64(2), results[1].Object.Version)
+	require.Equal(t, ErrNotExists, results[2].Err)
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"github.com/pkg/errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// compare returns true if the field of given object satisfies the operator.
+// Values of mismatched types never satisfy range operators.
+func compare(obj *Object, op Operator) bool {
+	fieldVal := obj.Data[op.Field]
+	switch op.Type {
+	case OpEquals:
+		return equals(fieldVal, op.Operand)
+	case OpGreaterThan:
+		c, ok := order(fieldVal, op.Operand)
+		return ok && c > 0
+	case OpGreaterThanOrEqual:
+		c, ok := order(fieldVal, op.Operand)
+		return ok && c >= 0
+	case OpLessThan:
+		c, ok := order(fieldVal, op.Operand)
+		return ok && c < 0
+	case OpLessThanOrEqual:
+		c, ok := order(fieldVal, op.Operand)
+		return ok && c <= 0
+	case OpContains:
+		return contains(fieldVal, op.Operand)
+	}
+	return false
+}
+
+// validateOperand checks whether the operand can be used with the operator.
+func validateOperand(op Operator) error {
+	switch op.Type {
+	case OpGreaterThan, OpGreaterThanOrEqual, OpLessThan, OpLessThanOrEqual:
+		if _, isNumber := toNumber(op.Operand); isNumber {
+			return nil
+		}
+		if _, isString := op.Operand.(string); isString {
+			return nil
+		}
+		return errors.Errorf("operand of %s should be a number, string or timestamp", op.Field)
+
+	case OpContains:
+		if op.Operand == nil {
+			return errors.Errorf("operand of %s should not be null", op.Field)
+		}
+		switch reflect.ValueOf(op.Operand).Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array:
+			return errors.Errorf("operand of %s should be a scalar value", op.Field)
+		}
+	}
+	return nil
+}
+
+// equals compares two values regardless of their numeric types.
+func equals(a, b interface{}) bool {
+	if x, ok := toNumber(a); ok {
+		y, ok := toNumber(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// order returns the ordering of two values as -1, 0 or 1.
+// Numbers are compared numerically, RFC3339 timestamps chronologically,
+// and other strings lexicographically. The second return value is false
+// if the values are not comparable with each other.
+func order(a, b interface{}) (int, bool) {
+	if x, ok := toNumber(a); ok {
+		y, ok := toNumber(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	x, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	if xTime, err := time.Parse(time.RFC3339Nano, x); err == nil {
+		if yTime, err := time.Parse(time.RFC3339Nano, y); err == nil {
+			switch {
+			case xTime.Before(yTime):
+				return -1, true
+			case xTime.After(yTime):
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return strings.Compare(x, y), true
+}
+
+// contains returns true if the value is a string containing given substring,
+// or an array containing given element.
+func contains(val, elem interface{}) bool {
+	switch v := val.(type) {
+	case string:
+		s, ok := elem.(string)
+		return ok && strings.Contains(v, s)
+	case []byte:
+		b, ok := elem.([]byte)
+		return ok && bytes.Contains(v, b)
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if equals(rv.Index(i).Interface(), elem) {
+			return true
+		}
+	}
+	return false
+}
+
+// toNumber converts numeric values, including json.Number, into float64.
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case stdjson.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	stdjson "encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCompare_Numbers(t *testing.T) {
+	for _, fieldVal := range []interface{}{25, int64(25), 25.0, stdjson.Number("25")} {
+		obj := &Object{Data: Payload{"age": fieldVal}}
+		require.True(t, compare(obj, Operator{Type: OpEquals, Field: "age", Operand: 25.0}))
+		require.True(t, compare(obj, Operator{Type: OpGreaterThan, Field: "age", Operand: 20.0}))
+		require.True(t, compare(obj, Operator{Type: OpGreaterThanOrEqual, Field: "age", Operand: 25}))
+		require.True(t, compare(obj, Operator{Type: OpLessThan, Field: "age", Operand: stdjson.Number("30")}))
+		require.False(t, compare(obj, Operator{Type: OpLessThanOrEqual, Field: "age", Operand: 24.5}))
+	}
+}
+
+func TestCompare_Strings(t *testing.T) {
+	obj := &Object{Data: Payload{"name": "Kim"}}
+	require.True(t, compare(obj, Operator{Type: OpGreaterThan, Field: "name", Operand: "Kang"}))
+	require.True(t, compare(obj, Operator{Type: OpLessThan, Field: "name", Operand: "Lee"}))
+	require.False(t, compare(obj, Operator{Type: OpLessThan, Field: "name", Operand: "Kim"}))
+}
+
+func TestCompare_Timestamps(t *testing.T) {
+	obj := &Object{Data: Payload{"at": "2019-04-01T09:00:00+09:00"}}
+
+	// lexicographically greater, but chronologically same
+	require.False(t, compare(obj, Operator{Type: OpGreaterThan, Field: "at", Operand: "2019-04-01T00:00:00Z"}))
+	require.True(t, compare(obj, Operator{Type: OpGreaterThanOrEqual, Field: "at", Operand: "2019-04-01T00:00:00Z"}))
+	require.True(t, compare(obj, Operator{Type: OpLessThan, Field: "at", Operand: "2019-04-01T00:00:01Z"}))
+}
+
+func TestCompare_MismatchedTypes(t *testing.T) {
+	obj := &Object{Data: Payload{
+		"age":  "twenty",
+		"tags": []interface{}{"a", map[string]interface{}{"b": 1.0}},
+		"meta": map[string]interface{}{"foo": "bar"},
+	}}
+	for _, op := range []Operator{
+		{Type: OpGreaterThan, Field: "age", Operand: 20.0},
+		{Type: OpLessThan, Field: "missing", Operand: 20.0},
+		{Type: OpGreaterThan, Field: "tags", Operand: "a"},
+		{Type: OpContains, Field: "age", Operand: 20.0},
+		{Type: OpContains, Field: "meta", Operand: "foo"},
+		{Type: OpEquals, Field: "tags", Operand: "a"},
+	} {
+		require.NotPanics(t, func() {
+			require.False(t, compare(obj, op))
+		})
+	}
+	require.True(t, compare(obj, Operator{Type: OpEquals, Field: "meta", Operand: map[string]interface{}{"foo": "bar"}}))
+	require.True(t, compare(obj, Operator{Type: OpContains, Field: "tags", Operand: "a"}))
+}
+
+func TestQueryFromJson_InvalidOperand(t *testing.T) {
+	for _, rawQuery := range []string{
+		`{"age": {"gt": null}}`,
+		`{"age": {"gte": [1, 2]}}`,
+		`{"age": {"lt": {"foo": "bar"}}}`,
+		`{"age": {"lte": true}}`,
+		`{"tags": {"contains": null}}`,
+		`{"tags": {"contains": ["a"]}}`,
+	} {
+		_, err := QueryFromJson(rawQuery)
+		require.Error(t, err, rawQuery)
+	}
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/common"
+)
+
+// Config is the configuration of the verification of writes, which is shared by every backend.
+// It is set once on the creation of the backend with Options.
+type Config struct {
+	// Domain is the signing domain which the signatures of writes are made for.
+	Domain string
+
+	// Admins are the addresses of the admins, who can manage the schemas of types and deposit.
+	// No one is an admin unless it is set with WithAdmins.
+	Admins map[common.Address]bool
+
+	// FeeModel computes the fees of writes. Every write is free by default.
+	FeeModel FeeModel
+
+	// QuotaEnforced makes writes fail with ErrQuotaExceeded if the payer
+	// cannot afford the fee with the prepaid quota.
+	QuotaEnforced bool
+
+	// WriteLimiter limits the rate of writes by the address of the signer. Writes are not limited if it is nil.
+	WriteLimiter RateLimiter
+}
+
+// Option sets a configuration of backends.
+type Option func(config *Config)
+
+// WithDomain makes the backend accept signatures made for given signing domain,
+// instead of auth.DefaultDomain.
+func WithDomain(domain string) Option {
+	return func(config *Config) {
+		config.Domain = domain
+	}
+}
+
+// WithAdmins makes given addresses the admins of the backend.
+func WithAdmins(addrs ...common.Address) Option {
+	return func(config *Config) {
+		config.Admins = make(map[common.Address]bool, len(addrs))
+		for _, addr := range addrs {
+			config.Admins[addr] = true
+		}
+	}
+}
+
+// WithFeeModel charges the fees of writes computed by given fee model.
+func WithFeeModel(model FeeModel) Option {
+	return func(config *Config) {
+		config.FeeModel = model
+	}
+}
+
+// WithQuotaEnforced makes writes fail if the payer cannot afford the fee with the prepaid quota.
+func WithQuotaEnforced(enforced bool) Option {
+	return func(config *Config) {
+		config.QuotaEnforced = enforced
+	}
+}
+
+// WithWriteLimiter limits the rate of writes by the address of the signer with given limiter.
+func WithWriteLimiter(limiter RateLimiter) Option {
+	return func(config *Config) {
+		config.WriteLimiter = limiter
+	}
+}
+
+// NewConfig returns the default configuration with given options applied.
+func NewConfig(options ...Option) *Config {
+	config := &Config{
+		Domain:   auth.DefaultDomain,
+		FeeModel: &LinearFeeModel{},
+	}
+	for _, applyFunc := range options {
+		applyFunc(config)
+	}
+	return config
+}
+
+// IsAdmin returns true if given address is one of the admins.
+func (config *Config) IsAdmin(addr common.Address) bool {
+	return config.Admins[addr]
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// ErrInvalidCursor is raised when given cursor is malformed or issued for a different sort.
+var ErrInvalidCursor = errors.New("invalid cursor.")
+
+// Cursor points the last object of a page, so that the next page can continue after it.
+// It is exposed to clients as an opaque token using String.
+type Cursor struct {
+	ID string `json:"id"`
+
+	// sort field and value of the object, if the results are sorted.
+	SortField  string      `json:"f,omitempty"`
+	Descending bool        `json:"d,omitempty"`
+	SortValue  interface{} `json:"v,omitempty"`
+}
+
+// NewCursor returns a cursor pointing given object in given sort order.
+func NewCursor(obj *Object, sort *Sort) *Cursor {
+	c := &Cursor{ID: obj.ID}
+	if sort != nil {
+		c.SortField = sort.Field
+		c.Descending = sort.Descending
+		c.SortValue = sort.value(obj)
+	}
+	return c
+}
+
+// CursorFromString decodes given token into a cursor, and checks whether the cursor
+// has been issued for the same sort. A nil cursor is returned for an empty token.
+func CursorFromString(token string, sort *Sort) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	rawCursor, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	c := new(Cursor)
+	if err := json.Unmarshal(rawCursor, c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	if sort == nil {
+		if c.SortField != "" {
+			return nil, ErrInvalidCursor
+		}
+		return c, nil
+	}
+	if c.SortField != sort.Field || c.Descending != sort.Descending {
+		return nil, ErrInvalidCursor
+	}
+	if sort.Field == SortFieldCreatedAt || sort.Field == SortFieldLastUpdatedAt {
+		// timestamps are encoded as RFC3339 strings in JSON
+		rawTime, ok := c.SortValue.(string)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		if c.SortValue, err = time.Parse(time.RFC3339Nano, rawTime); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return c, nil
+}
+
+// String encodes the cursor into an opaque token.
+func (c *Cursor) String() string {
+	rawCursor, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(rawCursor)
+}
+
+// Precedes returns true if the object comes after the cursor in given sort order.
+// Objects are ordered by ID if the sort is nil.
+func (c *Cursor) Precedes(obj *Object, sort *Sort) bool {
+	if sort == nil {
+		return c.ID < obj.ID
+	}
+	return sort.Less(c.object(sort), obj)
+}
+
+// object returns a pseudo-object which has the same sort value with the object pointed by the cursor.
+func (c *Cursor) object(sort *Sort) *Object {
+	obj := &Object{ID: c.ID, Data: Payload{}}
+	switch sort.Field {
+	case SortFieldID:
+	case SortFieldCreatedAt:
+		obj.CreatedAt, _ = c.SortValue.(time.Time)
+	case SortFieldLastUpdatedAt:
+		obj.LastUpdatedAt, _ = c.SortValue.(time.Time)
+	default:
+		obj.Data[sort.Field] = c.SortValue
+	}
+	return obj
+}
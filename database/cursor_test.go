@@ -0,0 +1,53 @@
+package database
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCursorFromString(t *testing.T) {
+	c, err := CursorFromString("", nil)
+	require.NoError(t, err)
+	require.Nil(t, c)
+
+	c, err = CursorFromString(NewCursor(&Object{ID: "foo"}, nil).String(), nil)
+	require.NoError(t, err)
+	require.Equal(t, &Cursor{ID: "foo"}, c)
+
+	sort := &Sort{Field: "age", Descending: true}
+	c, err = CursorFromString(NewCursor(&Object{ID: "foo", Data: Payload{"age": 20}}, sort).String(), sort)
+	require.NoError(t, err)
+	require.Equal(t, "foo", c.ID)
+	require.Equal(t, 20.0, c.SortValue)
+}
+
+func TestCursorFromString_Timestamp(t *testing.T) {
+	now := time.Now()
+	sort := &Sort{Field: SortFieldCreatedAt}
+	token := NewCursor(&Object{ID: "foo", CreatedAt: now}, sort).String()
+
+	c, err := CursorFromString(token, sort)
+	require.NoError(t, err)
+	require.True(t, now.Equal(c.SortValue.(time.Time)))
+
+	require.False(t, c.Precedes(&Object{ID: "bar", CreatedAt: now.Add(-time.Second)}, sort))
+	require.True(t, c.Precedes(&Object{ID: "goo", CreatedAt: now}, sort))
+	require.True(t, c.Precedes(&Object{ID: "bar", CreatedAt: now.Add(time.Second)}, sort))
+}
+
+func TestCursorFromString_Invalid(t *testing.T) {
+	_, err := CursorFromString("not a cursor", nil)
+	require.Equal(t, ErrInvalidCursor, err)
+
+	_, err = CursorFromString("eyJmb28iOiJiYXIifQ", nil)
+	require.Equal(t, ErrInvalidCursor, err)
+
+	// cursors should not be reused for another sort
+	sort := &Sort{Field: "age"}
+	token := NewCursor(&Object{ID: "foo", Data: Payload{"age": 20}}, sort).String()
+	_, err = CursorFromString(token, nil)
+	require.Equal(t, ErrInvalidCursor, err)
+	_, err = CursorFromString(token, &Sort{Field: "age", Descending: true})
+	require.Equal(t, ErrInvalidCursor, err)
+}
@@ -3,7 +3,9 @@ package database
 import (
 	"context"
 	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/common"
 	"github.com/pkg/errors"
+	"strings"
 	"time"
 )
 
@@ -11,18 +13,130 @@ var (
 	ErrInvalidID = errors.New("invalid ID format.")
 	ErrNotExists = errors.New("given object not exists.")
 
+	// ErrInvalidType is raised when the type has a slash or a null byte, which separate the keys of backends.
+	ErrInvalidType = errors.New("invalid type format.")
+
 	// ErrNotAuthorized is raised when given signature mismatched with the object owner's one.
 	ErrNotAuthorized = errors.New("you're not authorized to update the object.")
+
+	// ErrConflict is raised when the object has been updated by others,
+	// so that the version of the object is not the expected one.
+	ErrConflict = errors.New("the object has been updated by others.")
+
+	// ErrInvalidExpiry is raised when a write sets an expiry which has already passed.
+	ErrInvalidExpiry = errors.New("the expiry should be in the future.")
+
+	// ErrReservedKey is raised when the data has a top-level key in ReservedKeys.
+	ErrReservedKey = errors.New("the data has a reserved key.")
+
+	// ErrNotSupported is raised by wrappers of backends when the backend does not implement
+	// the optional interface of the operation, such as Batcher.
+	ErrNotSupported = errors.New("the operation is not supported by the backend.")
 )
 
+// IsValidType returns whether objects of given type can be stored. Backends treat objects of invalid types
+// as nonexistent, since their keys may collide with the keys of other types or revisions.
+func IsValidType(typ string) bool {
+	return !strings.ContainsAny(typ, "/\x00")
+}
+
+// IsValidID returns whether objects can have given ID. Backends treat objects of invalid IDs as nonexistent,
+// since the keys of revisions are made of the IDs of the objects and their versions separated by a slash.
+func IsValidID(id string) bool {
+	return !strings.Contains(id, "/")
+}
+
+// ReservedKeys are the fields of objects and the attributes stored with them, which cannot be used
+// as the top-level keys of the data since backends such as DynamoDB store the data next to the fields.
+// "Expiring" is the attribute which DynamoDB indexes the expiring objects by.
+var ReservedKeys = []string{"ID", "Data", "Owner", "ACL", "Private", "Version", "Deleted", "ExpiresAt", "CreatedAt", "LastUpdatedAt", "Revision", "Expiring"}
+
 // Payload is a shorthand of `map[string]interface{}`.
 type Payload map[string]interface{}
 
+// Database is implemented by every backend. Optional capabilities such as batches, transactions,
+// schemas and fees are separate interfaces like Sweeper, which callers should assert on the backend.
 type Database interface {
 	Get(ctx context.Context, typ, id string) (*Object, error)
 	Exists(ctx context.Context, typ, id string) (bool, error)
-	Query(ctx context.Context, typ string, query *Query, skip, limit int) ([]*Object, error)
-	Put(ctx context.Context, typ, id string, data Payload, signature []byte) (*PutResult, error)
+	Query(ctx context.Context, typ string, query *Query, opts QueryOptions) (*QueryResult, error)
+	Put(ctx context.Context, typ, id string, data Payload, signature []byte, opts PutOptions) (*PutResult, error)
+
+	// Patch applies given patch to the existing object. The patch should be signed
+	// by the owner or a writer for the current version of the object.
+	Patch(ctx context.Context, typ, id string, patch *Patch, signature []byte, opts PutOptions) (*PutResult, error)
+	Delete(ctx context.Context, typ, id string, signature []byte) error
+
+	// TransferOwnership moves the ownership of the object to the new owner of the transfer.
+	// The transfer should be signed by the current owner for the next version of the object.
+	TransferOwnership(ctx context.Context, typ, id string, transfer *Transfer, signature []byte, opts PutOptions) (*PutResult, error)
+
+	// SetACL replaces the access control list of the object. The list should be signed
+	// by the owner for the next version of the object.
+	SetACL(ctx context.Context, typ, id string, acl *ACL, signature []byte, opts PutOptions) (*PutResult, error)
+
+	// SetVisibility makes the object private or public. It should be signed
+	// by the owner for the next version of the object.
+	SetVisibility(ctx context.Context, typ, id string, private bool, signature []byte, opts PutOptions) (*PutResult, error)
+
+	// Version returns the latest version of the object including deleted ones,
+	// so that writers can sign the next version. 0 is returned if the object has never existed.
+	Version(ctx context.Context, typ, id string) (uint64, error)
+
+	// History returns every revision of the object including deletions, sorted by version.
+	// ErrNotExists is returned if the object has never existed.
+	History(ctx context.Context, typ, id string) ([]*Revision, error)
+}
+
+// Batcher is implemented by the backends which read and write objects in batches.
+type Batcher interface {
+	// BatchGet returns the objects of given keys in the same order.
+	// Tombstones are returned instead of ErrNotExists if includeDeleted is true.
+	BatchGet(ctx context.Context, keys []ObjectKey, includeDeleted bool) ([]*BatchGetResult, error)
+
+	// BatchPut writes the objects of given items like Put, and returns the result of each write.
+	// The returned error is only for the failure of the whole batch.
+	BatchPut(ctx context.Context, items []PutItem) ([]*BatchPutResult, error)
+}
+
+// Transactor is implemented by the backends which commit operations on multiple objects all-or-nothing.
+type Transactor interface {
+	// Transact commits given operations all-or-nothing, and returns the result of each
+	// operation, which is nil for TxCheck. If any of the operations fails, nothing is written
+	// and a TxError is returned if the failed operation is known.
+	Transact(ctx context.Context, ops []TxOp) ([]*PutResult, error)
+}
+
+// SchemaRegistry is implemented by the backends which validate writes against the JSON Schemas of types.
+type SchemaRegistry interface {
+	// SetSchema sets the JSON Schema of the type, which should be signed by one of the admins
+	// for the next version of the schema. Writes afterwards are validated against it.
+	SetSchema(ctx context.Context, typ, definition string, signature []byte, opts PutOptions) (*Schema, error)
+
+	// GetSchema returns given version of the schema of the type, or the latest one if the version is 0.
+	// ErrNotExists is returned if the schema has never been set.
+	GetSchema(ctx context.Context, typ string, version uint64) (*Schema, error)
+
+	// ListSchemas returns the latest schemas of every type, sorted by type.
+	ListSchemas(ctx context.Context) ([]*Schema, error)
+}
+
+// Meter is implemented by the backends which charge the fees of writes to the owners.
+type Meter interface {
+	// Usage returns the fees charged to the owner and the deposits to the prepaid quota.
+	// An empty usage is returned if nothing has been charged or deposited.
+	Usage(ctx context.Context, owner common.Address) (*Usage, error)
+
+	// Deposit adds given amount to the prepaid quota of the owner. It should be signed
+	// by one of the admins for the next deposit of the owner.
+	Deposit(ctx context.Context, owner common.Address, amount uint64, signature []byte) (*Usage, error)
+}
+
+// Pinger is implemented by the backends which depend on external services.
+// Backends which do not implement it are always ready to serve requests.
+type Pinger interface {
+	// Ping checks whether the backend can serve requests, without writing anything.
+	Ping(ctx context.Context) error
 }
 
 type Object struct {
@@ -32,12 +146,129 @@ type Object struct {
 
 	Owner auth.PublicKey
 
+	// ACL grants permissions to others than the owner. It is nil if nothing is granted.
+	ACL *ACL `dynamo:",omitempty"`
+
+	// Private objects can be read only by the owner and the readers and writers in the ACL.
+	Private bool `dynamo:",omitempty"`
+
+	// Version is 1 on creation, and increased by 1 on every update.
+	// It keeps increasing even if the object is deleted and created again.
+	Version uint64
+
+	// Deleted marks a tombstone of the deleted object, which only retains its ID and version.
+	// Tombstones are never returned from Get or Query.
+	Deleted bool `dynamo:",omitempty"`
+
+	// ExpiresAt is the time when the object expires, which is zero if it never expires.
+	// Expired objects are treated as nonexistent, and replaced with tombstones by the backends.
+	// It is stored in unix seconds by the DynamoDB backend, so it is not marshalled with the item.
+	ExpiresAt time.Time `dynamo:"-"`
+
 	// timestamps
 	CreatedAt     time.Time
 	LastUpdatedAt time.Time
 }
 
+// Tombstone returns a tombstone of given object after deleting it.
+// The version of the tombstone is increased by 1 like updates.
+func Tombstone(obj *Object) *Object {
+	return &Object{
+		ID:      obj.ID,
+		Type:    obj.Type,
+		Version: obj.Version + 1,
+		Deleted: true,
+
+		LastUpdatedAt: time.Now(),
+	}
+}
+
+// Expired returns true if the object has an expiry which is not after given time.
+func (obj *Object) Expired(now time.Time) bool {
+	return !obj.ExpiresAt.IsZero() && !obj.ExpiresAt.After(now)
+}
+
+// IsLive returns true if the stored object exists, which is neither deleted nor expired.
+func IsLive(obj *Object) bool {
+	return obj != nil && !obj.Deleted && !obj.Expired(time.Now())
+}
+
+// Expire returns a tombstone of given expired object. Unlike deletions, the version is kept as is,
+// since no revision is written for the expiry.
+func Expire(obj *Object) *Object {
+	return &Object{
+		ID:      obj.ID,
+		Type:    obj.Type,
+		Version: obj.Version,
+		Deleted: true,
+
+		LastUpdatedAt: time.Now(),
+	}
+}
+
+// Sweeper is implemented by the backends which replace expired objects with tombstones in the background.
+type Sweeper interface {
+	// Sweep replaces the objects expired at given time with tombstones, and erases
+	// the data of their revisions. It returns the number of the swept objects.
+	Sweep(ctx context.Context, now time.Time) (int, error)
+}
+
+// PutOptions specifies conditions of a write.
+type PutOptions struct {
+	// ExpectedVersion makes the write fail with ErrConflict unless the current version
+	// of the object is the same with it. 0 means no expectation.
+	ExpectedVersion uint64
+
+	// Private creates the object as a private object. It is ignored on updates,
+	// and the visibility of existing objects can be changed only with SetVisibility.
+	Private bool
+
+	// ExpiresAt makes the object expire at given time, which is signed with the data.
+	// Since every Put sets the expiry, an update without it makes the object never expire.
+	// It is truncated to seconds.
+	ExpiresAt time.Time
+
+	// Schema is the latest schema of the type, which the data of the write should conform to.
+	// It is set by backends from their schema registry, and nil if no schema has been set.
+	Schema *Schema
+}
+
+// CheckVersion returns ErrConflict if the current version mismatches with the expected version.
+func (opts PutOptions) CheckVersion(current uint64) error {
+	if opts.ExpectedVersion != 0 && opts.ExpectedVersion != current {
+		return ErrConflict
+	}
+	return nil
+}
+
 type PutResult struct {
 	FeeUsed uint64
 	Created bool
+
+	// Version is the version of the object after the write.
+	Version uint64
+}
+
+// QueryOptions specifies the order and the range of query results.
+type QueryOptions struct {
+	// Sort is the order of the results. Backends use their own stable order if it is nil.
+	Sort *Sort
+
+	// Cursor continues the query after the last object of the previous page.
+	Cursor *Cursor
+
+	Skip  int
+	Limit int
+
+	// Reader is the address of the authenticated reader, which is nil for anonymous readers.
+	// Private objects which the reader cannot read are excluded from the results.
+	Reader *common.Address
+}
+
+type QueryResult struct {
+	Objects []*Object
+
+	// NextCursor points the last object of the results.
+	// It is nil if there are no more results.
+	NextCursor *Cursor
 }
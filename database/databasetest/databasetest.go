@@ -0,0 +1,18 @@
+// Package databasetest provides helpers for the tests of database backends.
+package databasetest
+
+import (
+	"crypto/ecdsa"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// Sign signs given hash with the key, and fails the test if it cannot be signed.
+// It should be called from the goroutine running the test.
+func Sign(t testing.TB, key *ecdsa.PrivateKey, hash [32]byte) []byte {
+	t.Helper()
+	sig, err := crypto.Sign(hash[:], key)
+	require.NoError(t, err)
+	return sig
+}
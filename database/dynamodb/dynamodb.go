@@ -2,20 +2,47 @@
 package dynamodatabase
 
 import (
-	"bytes"
 	"context"
-	"github.com/airbloc/airframe/auth"
+	"fmt"
 	"github.com/airbloc/airframe/database"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	awsclient "github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/guregu/dynamo"
+	"github.com/klaytn/klaytn/common"
 	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
-var (
-	reservedFields = []string{"ID", "Data", "Owner", "CreatedAt", "LastUpdatedAt"}
+// maxConcurrentWrites limits the number of concurrent transactions in a batch put.
+const maxConcurrentWrites = 10
+
+// schemaTableSuffix is the suffix of the table of schemas after the table prefix.
+// Since tables of types are named in the same way, the type with this name is reserved.
+const schemaTableSuffix = "_schemas"
+
+// usageTableSuffix is the suffix of the table of usages after the table prefix, which is reserved like schemaTableSuffix.
+const usageTableSuffix = "_usage"
+
+// expiryAttribute is the attribute of the expiry of objects in unix seconds.
+const expiryAttribute = "ExpiresAt"
+
+// expiryIndex is the sparse global secondary index of the tables of types, which has only the live objects
+// with an expiry, so that Sweep can query the expired objects instead of scanning the tables. Since an index
+// needs a hash key, the expiring objects have expiringAttribute with expiringPartition as the hash key.
+const expiryIndex = "Expiry"
+
+const (
+	expiringAttribute = "Expiring"
+	expiringPartition = "expiring"
+)
 
+var (
 	dynamoOperators = map[database.OperatorType]string{
 		database.OpEquals:             "$ = ?",
 		database.OpGreaterThan:        "$ > ?",
@@ -26,155 +53,1046 @@ var (
 	}
 )
 
+// tableSchema describes the key schema of per-type tables and their expiry index.
+type tableSchema struct {
+	ID        string `dynamo:",hash"`
+	Expiring  string `index:"Expiry,hash"`
+	ExpiresAt int64  `index:"Expiry,range"`
+}
+
+// revisionItem stores a revision in the table of the type, keyed by "<id>/<version>".
+// Since IDs cannot contain slashes, revisions never collide with objects.
+// Revisions are never indexed by the expiry, and their data are erased when the object is swept.
+type revisionItem struct {
+	ID        string `dynamo:",hash"`
+	Revision  *database.Revision
+	ExpiresAt int64 `dynamo:",omitempty"`
+}
+
+func newRevisionItem(rev *database.Revision) revisionItem {
+	item := revisionItem{ID: revisionID(rev.ID, rev.Version), Revision: rev}
+	if !rev.ExpiresAt.IsZero() {
+		item.ExpiresAt = rev.ExpiresAt.Unix()
+	}
+	return item
+}
+
+func revisionID(id string, version uint64) string {
+	return fmt.Sprintf("%s/%d", id, version)
+}
+
+// schemaTableSchema describes the key schema of the table of schemas,
+// which has every version of the schemas of types.
+type schemaTableSchema struct {
+	Type    string `dynamo:",hash"`
+	Version uint64 `dynamo:",range"`
+}
+
+// DynamoDatabase stores objects in a table per type. Expired objects are replaced with tombstones by Sweep
+// like the other backends, so that the signatures of their versions cannot be replayed, and the data of
+// their revisions are erased.
 type DynamoDatabase struct {
-	svc *dynamo.DB
+	svc    *dynamo.DB
+	config *database.Config
 
 	tablePrefix string
+
+	// tables caches names of the tables known to be ready.
+	tables     map[string]bool
+	tablesLock sync.RWMutex
 }
 
-func New(session awsclient.ConfigProvider) *DynamoDatabase {
+func New(session awsclient.ConfigProvider, tablePrefix string, options ...database.Option) *DynamoDatabase {
+	client := dynamodb.New(session)
+	traceRequests(&client.Handlers)
 	return &DynamoDatabase{
-		svc: dynamo.New(session),
+		svc:    dynamo.NewFromIface(client),
+		config: database.NewConfig(options...),
+
+		tablePrefix: tablePrefix,
+		tables:      make(map[string]bool),
+	}
+}
 
-		tablePrefix: "airbloc_",
+// Init checks whether the credentials are valid, and whether the existing tables
+// with the table prefix have a compatible key schema.
+func (db *DynamoDatabase) Init(ctx context.Context) error {
+	tables, err := db.svc.ListTables().AllWithContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list tables from DynamoDB")
+	}
+	for _, name := range tables {
+		if !db.isTypeTable(name) {
+			continue
+		}
+		desc, err := db.svc.Table(name).Describe().RunWithContext(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to describe table %s", name)
+		}
+		if desc.HashKey != "ID" || desc.HashKeyType != dynamo.StringType || desc.RangeKey != "" {
+			return errors.Errorf("table %s should have a string hash key named ID", name)
+		}
+		if !hasIndex(desc, expiryIndex) {
+			// tables created before supporting expiry do not have the expiry index
+			if err := db.createExpiryIndex(ctx, name); err != nil {
+				return err
+			}
+		}
+		db.tables[name] = true
 	}
+	return nil
+}
+
+// isTypeTable returns whether given table is the table of a type.
+func (db *DynamoDatabase) isTypeTable(name string) bool {
+	return strings.HasPrefix(name, db.tablePrefix) && name != db.schemaTableName() && name != db.usageTableName()
+}
+
+// Ping checks whether DynamoDB is reachable with the credentials by listing a table.
+func (db *DynamoDatabase) Ping(ctx context.Context) error {
+	_, err := db.svc.Client().ListTablesWithContext(ctx, &dynamodb.ListTablesInput{Limit: aws.Int64(1)})
+	if err != nil {
+		return errors.Wrap(err, "failed to list tables from DynamoDB")
+	}
+	return nil
+}
+
+// table returns the table of given type, creating it if it does not exist.
+func (db *DynamoDatabase) table(ctx context.Context, typ string) (dynamo.Table, error) {
+	if typ == schemaTableSuffix || typ == usageTableSuffix {
+		return dynamo.Table{}, errors.Errorf("type %s is reserved", typ)
+	}
+	return db.createTable(ctx, db.tablePrefix+typ, tableSchema{})
+}
+
+// createTable returns the table of given name, creating it with given key schema if it does not exist.
+func (db *DynamoDatabase) createTable(ctx context.Context, name string, schema interface{}) (dynamo.Table, error) {
+	db.tablesLock.RLock()
+	ready := db.tables[name]
+	db.tablesLock.RUnlock()
+	if ready {
+		return db.svc.Table(name), nil
+	}
+
+	db.tablesLock.Lock()
+	defer db.tablesLock.Unlock()
+	if _, err := db.svc.Table(name).Describe().RunWithContext(ctx); err != nil {
+		if !isTableNotFound(err) {
+			return dynamo.Table{}, errors.Wrapf(err, "failed to describe table %s", name)
+		}
+		create := db.svc.CreateTable(name, schema).OnDemand(true)
+		if _, ok := schema.(tableSchema); ok {
+			// sweeping only needs the versions of the expired objects
+			create.Project(expiryIndex, dynamo.IncludeProjection, "Version")
+		}
+		if err := create.RunWithContext(ctx); err != nil {
+			return dynamo.Table{}, errors.Wrapf(err, "failed to create table %s", name)
+		}
+	}
+	input := &dynamodb.DescribeTableInput{TableName: aws.String(name)}
+	if err := db.svc.Client().WaitUntilTableExistsWithContext(ctx, input); err != nil {
+		return dynamo.Table{}, errors.Wrapf(err, "failed to wait for table %s", name)
+	}
+	db.tables[name] = true
+	return db.svc.Table(name), nil
+}
+
+// createExpiryIndex adds the expiry index to the existing table of a type.
+// Sweep fails on the table until DynamoDB finishes backfilling the index.
+func (db *DynamoDatabase) createExpiryIndex(ctx context.Context, name string) error {
+	_, err := db.svc.Table(name).UpdateTable().CreateIndex(dynamo.Index{
+		Name:              expiryIndex,
+		HashKey:           expiringAttribute,
+		HashKeyType:       dynamo.StringType,
+		RangeKey:          expiryAttribute,
+		RangeKeyType:      dynamo.NumberType,
+		ProjectionType:    dynamo.IncludeProjection,
+		ProjectionAttribs: []string{"Version"},
+	}).RunWithContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create expiry index of table %s", name)
+	}
+	return nil
+}
+
+// hasIndex returns whether the table has the global secondary index of given name.
+func hasIndex(desc dynamo.Description, name string) bool {
+	for _, index := range desc.GSI {
+		if index.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isConditionalCheckFailed returns true if given error is caused by a failed condition of a write,
+// or a transaction canceled by the condition.
+func isConditionalCheckFailed(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException ||
+			awsErr.Code() == dynamodb.ErrCodeTransactionCanceledException
+	}
+	return false
+}
+
+// isTableNotFound returns true if given error is caused by accessing nonexistent table.
+func isTableNotFound(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == dynamodb.ErrCodeResourceNotFoundException
+	}
+	return false
 }
 
 func (db *DynamoDatabase) Get(ctx context.Context, typ, id string) (*database.Object, error) {
+	obj, err := db.get(ctx, typ, id)
+	if err != nil {
+		return nil, err
+	}
+	// expired objects remain until they are swept
+	if !database.IsLive(obj) {
+		return nil, database.ErrNotExists
+	}
+	return obj, nil
+}
+
+// get returns the stored object including tombstones.
+// Revisions are stored in the same table, so IDs of revisions are treated as nonexistent.
+func (db *DynamoDatabase) get(ctx context.Context, typ, id string) (*database.Object, error) {
+	if !database.IsValidID(id) {
+		return nil, database.ErrNotExists
+	}
 	table := db.svc.Table(db.tablePrefix + typ)
 
 	items := make(map[string]*dynamodb.AttributeValue)
 	if err := table.Get("ID", id).OneWithContext(ctx, &items); err != nil {
-		if err == dynamo.ErrNotFound {
+		if err == dynamo.ErrNotFound || isTableNotFound(err) {
 			return nil, database.ErrNotExists
 		}
 		return nil, errors.Wrap(err, "failed to get item from DynamoDB")
 	}
+	return unmarshalObject(typ, items)
+}
 
-	// trick: copy the data attrs into Data object, since the result is flattened
-	items["Data"] = &dynamodb.AttributeValue{M: make(map[string]*dynamodb.AttributeValue)}
-CopyData:
-	for key, value := range items {
-		for _, reserved := range reservedFields {
-			if key == reserved {
-				continue CopyData
+// current returns the stored object including tombstones, or nil if the object has never existed.
+func (db *DynamoDatabase) current(ctx context.Context, typ, id string) (*database.Object, error) {
+	obj, err := db.get(ctx, typ, id)
+	if err == database.ErrNotExists {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "error while checking existence")
+	}
+	return obj, nil
+}
+
+func (db *DynamoDatabase) Exists(ctx context.Context, typ, id string) (bool, error) {
+	_, err := db.Get(ctx, typ, id)
+	if err == database.ErrNotExists {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (db *DynamoDatabase) Version(ctx context.Context, typ, id string) (uint64, error) {
+	obj, err := db.get(ctx, typ, id)
+	if err == database.ErrNotExists {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return obj.Version, nil
+}
+
+// Query returns objects matching with given query.
+// Without a sort, objects are returned in the order of DynamoDB Scan, which is stable unless
+// the table is modified, and the cursor is used as ExclusiveStartKey of the scan. The scan is paged
+// until an extra object is found after the requested page, since private objects which the reader
+// cannot read are filtered after each page. With a sort, every matching object is scanned and sorted
+// before applying the cursor, skip and limit.
+func (db *DynamoDatabase) Query(ctx context.Context, typ string, query *database.Query, opts database.QueryOptions) (*database.QueryResult, error) {
+	table := db.svc.Table(db.tablePrefix + typ)
+
+	// TODO: Use Query instead of Scan.
+	q := table.Scan().Filter("attribute_not_exists($) AND attribute_not_exists($)", "Deleted", "Revision")
+	q.Filter("attribute_not_exists($) OR $ > ?", expiryAttribute, expiryAttribute, time.Now().Unix())
+	if filter, args := buildFilter(query); filter != "" {
+		q.Filter(filter, args...)
+	}
+	if opts.Reader == nil {
+		// anonymous readers can only read public objects
+		q.Filter("attribute_not_exists($)", "Private")
+	}
+	if opts.Sort != nil {
+		var items []map[string]*dynamodb.AttributeValue
+		if err := q.AllWithContext(ctx, &items); err != nil {
+			if isTableNotFound(err) {
+				return &database.QueryResult{Objects: []*database.Object{}}, nil
 			}
+			return nil, errors.Wrap(err, "failed to scan item from DynamoDB")
+		}
+		results, err := readableObjects(typ, items, opts.Reader)
+		if err != nil {
+			return nil, err
 		}
-		items["Data"].M[key] = value
+		database.SortObjects(results, opts.Sort)
+		return database.Paginate(results, opts), nil
 	}
 
-	// now we can unmarshal it xD
-	obj := database.Object{}
-	if err := dynamo.UnmarshalItem(items, &obj); err != nil {
+	if opts.Cursor != nil {
+		q.StartFrom(dynamo.PagingKey{"ID": &dynamodb.AttributeValue{S: aws.String(opts.Cursor.ID)}})
+	}
+	if opts.Limit > 0 {
+		q.SearchLimit(int64(opts.Skip + opts.Limit + 1))
+	}
+	results := []*database.Object{}
+	for {
+		var items []map[string]*dynamodb.AttributeValue
+		next, err := q.AllWithLastEvaluatedKeyContext(ctx, &items)
+		if err != nil {
+			if isTableNotFound(err) {
+				return &database.QueryResult{Objects: []*database.Object{}}, nil
+			}
+			return nil, errors.Wrap(err, "failed to scan item from DynamoDB")
+		}
+		objects, err := readableObjects(typ, items, opts.Reader)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, objects...)
+
+		// stop at the end of the table, or after an extra object to check whether there are more objects
+		if next == nil || (opts.Limit > 0 && len(results) > opts.Skip+opts.Limit) {
+			break
+		}
+		q.StartFrom(next)
+	}
+	// the scan has already started from the cursor
+	opts.Cursor = nil
+	return database.Paginate(results, opts), nil
+}
+
+// readableObjects unmarshals given items of the type, and returns the objects which the reader can read.
+// Private objects are filtered after the scan, since ACLs cannot be evaluated in filter expressions.
+func readableObjects(typ string, items []map[string]*dynamodb.AttributeValue, reader *common.Address) ([]*database.Object, error) {
+	objects := make([]*database.Object, 0, len(items))
+	for _, item := range items {
+		obj, err := unmarshalObject(typ, item)
+		if err != nil {
+			return nil, err
+		}
+		if database.CanRead(obj, reader) {
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+// buildFilter translates given query into a DynamoDB filter expression with its arguments.
+// An empty expression is returned if the query has no conditions.
+func buildFilter(query *database.Query) (string, []interface{}) {
+	var exprs []string
+	var args []interface{}
+	for _, op := range query.Conditions {
+		exprs = append(exprs, dynamoOperators[op.Type])
+		args = append(args, op.Field, op.Operand)
+	}
+	for _, subquery := range query.Subqueries {
+		if expr, subArgs := buildFilter(subquery); expr != "" {
+			exprs = append(exprs, "("+expr+")")
+			args = append(args, subArgs...)
+		}
+	}
+	if len(exprs) == 0 {
+		return "", nil
+	}
+
+	switch query.Type {
+	case database.QueryOr:
+		return strings.Join(exprs, " OR "), args
+	case database.QueryNot:
+		return "NOT (" + strings.Join(exprs, " AND ") + ")", args
+	}
+	return strings.Join(exprs, " AND "), args
+}
+
+func (db *DynamoDatabase) Put(ctx context.Context, typ, id string, data database.Payload, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return db.update(ctx, typ, id, func(current *database.Object) (w *database.Write, err error) {
+		if opts.Schema, err = db.schemaOf(ctx, typ); err != nil {
+			return nil, err
+		}
+		return database.ApplyPut(ctx, db.config, current, typ, id, data, signature, opts)
+	})
+}
+
+func (db *DynamoDatabase) Patch(ctx context.Context, typ, id string, patch *database.Patch, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return db.update(ctx, typ, id, func(current *database.Object) (w *database.Write, err error) {
+		if opts.Schema, err = db.schemaOf(ctx, typ); err != nil {
+			return nil, err
+		}
+		return database.ApplyPatch(ctx, db.config, current, patch, signature, opts)
+	})
+}
+
+func (db *DynamoDatabase) TransferOwnership(ctx context.Context, typ, id string, transfer *database.Transfer, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return db.update(ctx, typ, id, func(current *database.Object) (*database.Write, error) {
+		return database.ApplyTransfer(ctx, db.config, current, transfer, signature, opts)
+	})
+}
+
+func (db *DynamoDatabase) SetACL(ctx context.Context, typ, id string, acl *database.ACL, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return db.update(ctx, typ, id, func(current *database.Object) (*database.Write, error) {
+		return database.ApplyACL(ctx, db.config, current, acl, signature, opts)
+	})
+}
+
+func (db *DynamoDatabase) SetVisibility(ctx context.Context, typ, id string, private bool, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return db.update(ctx, typ, id, func(current *database.Object) (*database.Write, error) {
+		return database.ApplyVisibility(ctx, db.config, current, private, signature, opts)
+	})
+}
+
+// Delete removes the object if the signature is made by the owner.
+// Only a tombstone retaining the ID and the version is left, and the data of
+// the previous revisions are erased while their signatures are kept in the history.
+func (db *DynamoDatabase) Delete(ctx context.Context, typ, id string, signature []byte) error {
+	_, err := db.update(ctx, typ, id, func(current *database.Object) (*database.Write, error) {
+		return database.ApplyDelete(ctx, db.config, current, signature)
+	})
+	return err
+}
+
+// update writes the object with the write which apply makes from the current object.
+// Every write of an object goes through it, and is conditioned on the version which
+// the write is based on, so that ErrConflict is returned if the object has been
+// written concurrently. The current object is nil if the object has never existed.
+func (db *DynamoDatabase) update(ctx context.Context, typ, id string, apply func(current *database.Object) (*database.Write, error)) (*database.PutResult, error) {
+	current, err := db.current(ctx, typ, id)
+	if err != nil {
 		return nil, err
 	}
-	return &obj, nil
+	w, err := apply(current)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.write(ctx, w); err != nil {
+		return nil, err
+	}
+	return w.Result(), nil
 }
 
-func (db *DynamoDatabase) Exists(ctx context.Context, typ, id string) (bool, error) {
-	table := db.svc.Table(db.tablePrefix + typ)
-	count, err := table.Get("ID", id).Count()
+// eraseHistory erases the data of the revisions while keeping their signatures.
+func (db *DynamoDatabase) eraseHistory(ctx context.Context, typ, id string) error {
+	revisions, err := db.History(ctx, typ, id)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to get item from DynamoDB")
+		return errors.Wrap(err, "failed to get history")
+	}
+	erased := make([]interface{}, 0, len(revisions))
+	for _, rev := range revisions {
+		if rev.Data != nil || rev.Patch != nil {
+			rev.Data = nil
+			rev.Patch = nil
+			erased = append(erased, newRevisionItem(rev))
+		}
 	}
-	return count > 0, nil
+	if len(erased) == 0 {
+		return nil
+	}
+	table, err := db.table(ctx, typ)
+	if err != nil {
+		return err
+	}
+	if _, err := table.Batch().Write().Put(erased...).RunWithContext(ctx); err != nil {
+		return errors.Wrap(err, "failed to erase history")
+	}
+	return nil
 }
 
-func (db *DynamoDatabase) Query(ctx context.Context, typ string, query *database.Query, skip, limit int) ([]*database.Object, error) {
+func (db *DynamoDatabase) History(ctx context.Context, typ, id string) ([]*database.Revision, error) {
+	version, err := db.Version(ctx, typ, id)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		return nil, database.ErrNotExists
+	}
+	keys := make([]dynamo.Keyed, version)
+	for v := uint64(1); v <= version; v++ {
+		keys[v-1] = dynamo.Keys{revisionID(id, v)}
+	}
+
+	var items []revisionItem
 	table := db.svc.Table(db.tablePrefix + typ)
+	if err := table.Batch("ID").Get(keys...).AllWithContext(ctx, &items); err != nil && err != dynamo.ErrNotFound {
+		return nil, errors.Wrap(err, "failed to get history from DynamoDB")
+	}
+	revisions := make([]*database.Revision, len(items))
+	for i, item := range items {
+		item.Revision.Type = typ
+		if item.ExpiresAt > 0 {
+			item.Revision.ExpiresAt = time.Unix(item.ExpiresAt, 0)
+		}
+		revisions[i] = item.Revision
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Version < revisions[j].Version
+	})
+	return database.EraseExpired(revisions), nil
+}
 
-	// TODO: Use Query instead of Scan.
-	q := table.Scan()
-	for _, op := range query.Conditions {
-		filter := dynamoOperators[op.Type]
-		q.Filter(filter, op.Field, op.Operand)
+// BatchGet gets the objects with BatchGetItem for each type.
+func (db *DynamoDatabase) BatchGet(ctx context.Context, keys []database.ObjectKey, includeDeleted bool) ([]*database.BatchGetResult, error) {
+	if err := database.ValidateBatch(keys); err != nil {
+		return nil, err
+	}
+	idsOfType := make(map[string][]dynamo.Keyed)
+	for _, key := range keys {
+		if !database.IsValidID(key.ID) {
+			// revisions are never returned as objects
+			continue
+		}
+		idsOfType[key.Type] = append(idsOfType[key.Type], dynamo.Keys{key.ID})
 	}
-	q.Limit(int64(skip + limit))
 
-	var items []map[string]*dynamodb.AttributeValue
-	if err := q.AllWithContext(ctx, &items); err != nil {
-		return nil, errors.Wrap(err, "failed to scan item from DynamoDB")
+	objects := make(map[database.ObjectKey]*database.Object, len(keys))
+	for typ, ids := range idsOfType {
+		var items []map[string]*dynamodb.AttributeValue
+		table := db.svc.Table(db.tablePrefix + typ)
+		if err := table.Batch("ID").Get(ids...).AllWithContext(ctx, &items); err != nil {
+			if err == dynamo.ErrNotFound || isTableNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrap(err, "failed to get items from DynamoDB")
+		}
+		for _, item := range items {
+			obj, err := unmarshalObject(typ, item)
+			if err != nil {
+				return nil, err
+			}
+			objects[database.ObjectKey{Type: typ, ID: obj.ID}] = obj
+		}
 	}
-	items = items[skip:]
 
-	results := make([]*database.Object, len(items))
-	for i := 0; i < len(items); i++ {
-		item := items[i]
+	results := make([]*database.BatchGetResult, len(keys))
+	for i, key := range keys {
+		results[i] = database.NewBatchGetResult(objects[key], includeDeleted)
+	}
+	return results, nil
+}
 
-		// trick: copy the data attrs into Data object, since the result is flattened
-		item["Data"] = &dynamodb.AttributeValue{M: make(map[string]*dynamodb.AttributeValue)}
-	CopyData:
-		for key, value := range item {
-			for _, reserved := range reservedFields {
-				if key == reserved {
-					continue CopyData
-				}
-			}
-			item["Data"].M[key] = value
+// BatchPut gets the current objects with BatchGetItem, and writes verified objects concurrently.
+// Since BatchWriteItem does not support condition expressions, each object is written
+// in its own transaction with its revision, so that ErrConflict is still returned
+// for concurrent writes like Put.
+func (db *DynamoDatabase) BatchPut(ctx context.Context, items []database.PutItem) ([]*database.BatchPutResult, error) {
+	current, err := db.BatchGet(ctx, database.KeysOf(items), true)
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := db.schemasOf(ctx, database.KeysOf(items))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*database.BatchPutResult, len(items))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentWrites)
+	for i, item := range items {
+		item.Options.Schema = schemas[item.Type]
+		w, err := database.ApplyPut(ctx, db.config, current[i].Object, item.Type, item.ID, item.Data, item.Signature, item.Options)
+		if err != nil {
+			results[i] = &database.BatchPutResult{Err: err}
+			continue
 		}
 
-		// now we can unmarshal it xD
-		obj := database.Object{}
-		if err := dynamo.UnmarshalItem(item, &obj); err != nil {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, w *database.Write) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := db.write(ctx, w); err != nil {
+				results[i] = &database.BatchPutResult{Err: err}
+				return
+			}
+			results[i] = &database.BatchPutResult{Result: w.Result()}
+		}(i, w)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// Transact commits given operations with TransactWriteItems. Since the current objects
+// are read before the transaction, each write is conditioned on the version which it is
+// based on, and ErrConflict is returned if any of them has been written concurrently.
+// Data of the revisions of deleted objects are erased after the transaction like write.
+func (db *DynamoDatabase) Transact(ctx context.Context, ops []database.TxOp) ([]*database.PutResult, error) {
+	if err := database.ValidateTx(ops); err != nil {
+		return nil, err
+	}
+	current, err := db.BatchGet(ctx, database.TxKeysOf(ops), true)
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := db.schemasOf(ctx, database.TxKeysOf(ops))
+	if err != nil {
+		return nil, err
+	}
+	ops = append([]database.TxOp{}, ops...)
+	objects := make([]*database.Object, len(ops))
+	for i, result := range current {
+		objects[i] = result.Object
+		ops[i].Options.Schema = schemas[ops[i].Type]
+	}
+	writes, err := database.ApplyTx(ctx, db.config, objects, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := db.svc.WriteTx()
+	results := make([]*database.PutResult, len(ops))
+	for i, w := range writes {
+		if w == nil {
+			// the version has been checked with the current object,
+			// but it should be checked again in the transaction.
+			table, err := db.table(ctx, ops[i].Type)
+			if err != nil {
+				return nil, err
+			}
+			check := table.Check("ID", ops[i].ID)
+			if version := ops[i].Options.ExpectedVersion; version > 0 {
+				check.If("$ = ?", "Version", version)
+			} else {
+				check.IfNotExists()
+			}
+			tx.Check(check)
+			continue
+		}
+		if err := db.addToTx(ctx, tx, w); err != nil {
 			return nil, err
 		}
-		results[i] = &obj
+	}
+	charges, err := db.charge(ctx, writes...)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.runTx(ctx, tx); err != nil {
+		if refundErr := db.refund(ctx, charges); refundErr != nil {
+			return nil, refundErr
+		}
+		return nil, err
+	}
+	for i, w := range writes {
+		if w != nil {
+			results[i] = w.Result()
+		}
+	}
+
+	for _, w := range writes {
+		if w != nil && w.EraseHistory {
+			if err := db.eraseHistory(ctx, w.Object.Type, w.Object.ID); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return results, nil
 }
 
-func (db *DynamoDatabase) Put(ctx context.Context, typ, id string, data database.Payload, signature []byte) (*database.PutResult, error) {
-	created := false
-	obj, err := db.Get(ctx, typ, id)
-	if err == database.ErrNotExists {
-		// create new
-		obj = &database.Object{
-			ID:   id,
-			Type: typ,
+// SetSchema puts the next version of the schema only if it does not exist,
+// so that ErrConflict is returned if the schema has been set concurrently.
+func (db *DynamoDatabase) SetSchema(ctx context.Context, typ, definition string, signature []byte, opts database.PutOptions) (*database.Schema, error) {
+	current, err := db.schemaOf(ctx, typ)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := database.ApplySchema(ctx, db.config, current, typ, definition, signature, opts)
+	if err != nil {
+		return nil, err
+	}
+	table, err := db.createTable(ctx, db.schemaTableName(), schemaTableSchema{})
+	if err != nil {
+		return nil, err
+	}
+	if err := table.Put(schema).If("attribute_not_exists($)", "Version").RunWithContext(ctx); err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, database.ErrConflict
+		}
+		return nil, errors.Wrap(err, "failed to write to DynamoDB")
+	}
+	return schema, nil
+}
 
-			CreatedAt:     time.Now(),
-			LastUpdatedAt: time.Now(),
+func (db *DynamoDatabase) GetSchema(ctx context.Context, typ string, version uint64) (*database.Schema, error) {
+	if version == 0 {
+		schema, err := db.schemaOf(ctx, typ)
+		if err == nil && schema == nil {
+			return nil, database.ErrNotExists
 		}
-		if obj.Owner, err = auth.GetSigner(typ, id, data, signature); err != nil {
-			return nil, errors.Wrap(err, "invalid signature")
+		return schema, err
+	}
+	schema := new(database.Schema)
+	table := db.svc.Table(db.schemaTableName())
+	if err := table.Get("Type", typ).Range("Version", dynamo.Equal, version).OneWithContext(ctx, schema); err != nil {
+		if err == dynamo.ErrNotFound || isTableNotFound(err) {
+			return nil, database.ErrNotExists
 		}
-		created = true
+		return nil, errors.Wrap(err, "failed to get schema from DynamoDB")
+	}
+	return schema, nil
+}
 
-	} else if err == nil {
-		// update object
-		signer, err := auth.GetSigner(typ, id, data, signature)
+// ListSchemas scans every version of the schemas, and returns the latest ones.
+func (db *DynamoDatabase) ListSchemas(ctx context.Context) ([]*database.Schema, error) {
+	var all []*database.Schema
+	if err := db.svc.Table(db.schemaTableName()).Scan().AllWithContext(ctx, &all); err != nil {
+		if isTableNotFound(err) {
+			return []*database.Schema{}, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan schemas from DynamoDB")
+	}
+	latest := make(map[string]*database.Schema)
+	for _, schema := range all {
+		if prev, ok := latest[schema.Type]; !ok || prev.Version < schema.Version {
+			latest[schema.Type] = schema
+		}
+	}
+	schemas := make([]*database.Schema, 0, len(latest))
+	for _, schema := range latest {
+		schemas = append(schemas, schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Type < schemas[j].Type
+	})
+	return schemas, nil
+}
+
+func (db *DynamoDatabase) schemaTableName() string {
+	return db.tablePrefix + schemaTableSuffix
+}
+
+// schemaOf returns the latest schema of the type, or nil if none has been set.
+func (db *DynamoDatabase) schemaOf(ctx context.Context, typ string) (*database.Schema, error) {
+	var schemas []*database.Schema
+	q := db.svc.Table(db.schemaTableName()).Get("Type", typ).Order(dynamo.Descending).Limit(1).Consistent(true)
+	if err := q.AllWithContext(ctx, &schemas); err != nil {
+		if isTableNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get schema from DynamoDB")
+	}
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+	return schemas[0], nil
+}
+
+// schemasOf returns the latest schemas of the types of given keys.
+func (db *DynamoDatabase) schemasOf(ctx context.Context, keys []database.ObjectKey) (map[string]*database.Schema, error) {
+	schemas := make(map[string]*database.Schema)
+	for _, key := range keys {
+		if _, ok := schemas[key.Type]; ok {
+			continue
+		}
+		schema, err := db.schemaOf(ctx, key.Type)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to recover signature")
+			return nil, err
+		}
+		schemas[key.Type] = schema
+	}
+	return schemas, nil
+}
+
+// Sweep replaces the expired objects with tombstones, and erases the data of their revisions like deletions.
+// The expired objects are queried from the expiry index of the tables of types, which is eventually consistent,
+// so each tombstone is written only if the object has not been written since the query.
+func (db *DynamoDatabase) Sweep(ctx context.Context, now time.Time) (int, error) {
+	tables, err := db.svc.ListTables().AllWithContext(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list tables from DynamoDB")
+	}
+	swept := 0
+	for _, name := range tables {
+		if !db.isTypeTable(name) {
+			continue
 		}
+		typ := strings.TrimPrefix(name, db.tablePrefix)
+		table := db.svc.Table(name)
+		q := table.Get(expiringAttribute, expiringPartition).Index(expiryIndex).Range(expiryAttribute, dynamo.LessOrEqual, now.Unix())
 
-		// only object owners can update the object
-		if !bytes.Equal(signer[:], obj.Owner[:]) {
-			return nil, database.ErrNotAuthorized
+		var items []map[string]*dynamodb.AttributeValue
+		if err := q.AllWithContext(ctx, &items); err != nil {
+			return swept, errors.Wrapf(err, "failed to query expired objects of %s from DynamoDB", typ)
 		}
-		obj.Data = data
-		obj.LastUpdatedAt = time.Now()
+		for _, item := range items {
+			obj, err := unmarshalObject(typ, item)
+			if err != nil {
+				return swept, errors.Wrap(err, "failed to unmarshal object")
+			}
+			tombstone, err := marshalObject(database.Expire(obj))
+			if err != nil {
+				return swept, err
+			}
+			if err := table.Put(tombstone).If("$ = ?", "Version", obj.Version).RunWithContext(ctx); err != nil {
+				if isConditionalCheckFailed(err) {
+					// the object has been written after the query
+					continue
+				}
+				return swept, errors.Wrap(err, "failed to write to DynamoDB")
+			}
+			if err := db.eraseHistory(ctx, typ, obj.ID); err != nil {
+				return swept, err
+			}
+			swept++
+		}
+	}
+	return swept, nil
+}
 
+// usageItem stores the usage of an owner in the table of usages. Balance is the deposits minus the fees,
+// which is kept to check the quota in condition expressions, since they cannot compute it from the others.
+type usageItem struct {
+	Owner     string `dynamo:",hash"`
+	Writes    uint64
+	FeeUsed   uint64
+	Deposited uint64
+	Deposits  uint64
+	Balance   int64
+}
+
+func (item *usageItem) usage() *database.Usage {
+	return &database.Usage{
+		Owner:     common.HexToAddress(item.Owner),
+		Writes:    item.Writes,
+		FeeUsed:   item.FeeUsed,
+		Deposited: item.Deposited,
+		Deposits:  item.Deposits,
+	}
+}
+
+func (db *DynamoDatabase) Usage(ctx context.Context, owner common.Address) (*database.Usage, error) {
+	item := new(usageItem)
+	table := db.svc.Table(db.usageTableName())
+	if err := table.Get("Owner", owner.Hex()).Consistent(true).OneWithContext(ctx, item); err != nil {
+		if err == dynamo.ErrNotFound || isTableNotFound(err) {
+			return &database.Usage{Owner: owner}, nil
+		}
+		return nil, errors.Wrap(err, "failed to get usage from DynamoDB")
+	}
+	return item.usage(), nil
+}
+
+// Deposit adds the amount to the usage with a condition expression on the number of the deposits,
+// so that ErrConflict is returned if the owner has been deposited concurrently.
+func (db *DynamoDatabase) Deposit(ctx context.Context, owner common.Address, amount uint64, signature []byte) (*database.Usage, error) {
+	current, err := db.Usage(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := database.ApplyDeposit(ctx, db.config, current, amount, signature)
+	if err != nil {
+		return nil, err
+	}
+	table, err := db.createTable(ctx, db.usageTableName(), usageItem{})
+	if err != nil {
+		return nil, err
+	}
+	updated := new(usageItem)
+	err = table.Update("Owner", owner.Hex()).
+		Add("Deposited", amount).
+		Add("Deposits", 1).
+		Add("Balance", amount).
+		If("attribute_not_exists($) OR $ = ?", "Deposits", "Deposits", current.Deposits).
+		ValueWithContext(ctx, updated)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, database.ErrConflict
+		}
+		return nil, errors.Wrap(err, "failed to write to DynamoDB")
+	}
+	// fees may have been charged since the usage is read
+	usage.Writes, usage.FeeUsed = updated.Writes, updated.FeeUsed
+	return usage, nil
+}
+
+func (db *DynamoDatabase) usageTableName() string {
+	return db.tablePrefix + usageTableSuffix
+}
+
+// charge charges the fees of given writes to the payers with conditional updates, which are made
+// before the writes since a transaction has a limit of items. If any payer cannot afford the fees,
+// ErrQuotaExceeded is returned after refunding the others. The returned charges should be
+// refunded if the writes fail. Writes of the signers exceeding the rate limit are not charged.
+func (db *DynamoDatabase) charge(ctx context.Context, writes ...*database.Write) ([]*database.Charge, error) {
+	if err := database.LimitWrites(db.config, writes...); err != nil {
+		return nil, err
+	}
+	charges, err := database.ChargesOf(db.config, writes...)
+	if err != nil {
+		return nil, err
+	}
+	table, err := db.createTable(ctx, db.usageTableName(), usageItem{})
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range charges {
+		update := table.Update("Owner", c.Payer.Hex()).
+			Add("Writes", c.Writes).
+			Add("FeeUsed", c.Fee).
+			Add("Balance", -int64(c.Fee))
+		if c.Fee > 0 && db.config.QuotaEnforced {
+			update.If("$ >= ?", "Balance", c.Fee)
+		}
+		if err := update.RunWithContext(ctx); err != nil {
+			if refundErr := db.refund(ctx, charges[:i]); refundErr != nil {
+				return nil, refundErr
+			}
+			if isConditionalCheckFailed(err) {
+				return nil, database.ErrQuotaExceeded
+			}
+			return nil, errors.Wrap(err, "failed to charge fee")
+		}
+	}
+	return charges, nil
+}
+
+// refund cancels given charges.
+func (db *DynamoDatabase) refund(ctx context.Context, charges []*database.Charge) error {
+	table := db.svc.Table(db.usageTableName())
+	for _, c := range charges {
+		err := table.Update("Owner", c.Payer.Hex()).
+			Add("Writes", -int64(c.Writes)).
+			Add("FeeUsed", -int64(c.Fee)).
+			Add("Balance", c.Fee).
+			RunWithContext(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to refund fee of %s", c.Payer.Hex())
+		}
+	}
+	return nil
+}
+
+// write charges the fee of given write, and puts the object with its revision in a transaction.
+// The fee is refunded if the transaction fails. If the write erases the history, data of
+// the previous revisions are erased after the transaction, since a transaction has a limit of items.
+func (db *DynamoDatabase) write(ctx context.Context, w *database.Write) error {
+	tx := db.svc.WriteTx()
+	if err := db.addToTx(ctx, tx, w); err != nil {
+		return err
+	}
+	charges, err := db.charge(ctx, w)
+	if err != nil {
+		return err
+	}
+	if err := db.runTx(ctx, tx); err != nil {
+		if refundErr := db.refund(ctx, charges); refundErr != nil {
+			return refundErr
+		}
+		return err
+	}
+	if w.EraseHistory {
+		return db.eraseHistory(ctx, w.Object.Type, w.Object.ID)
+	}
+	return nil
+}
+
+// addToTx adds the object of given write with its revision to the transaction, only if
+// the stored version is still the previous version, so that concurrent writes with
+// the same version cannot overwrite each other. If the object is created without
+// a previous version, the object should not exist.
+func (db *DynamoDatabase) addToTx(ctx context.Context, tx *dynamo.WriteTx, w *database.Write) error {
+	obj, rev := w.Object, w.Revision
+	table, err := db.table(ctx, obj.Type)
+	if err != nil {
+		return err
+	}
+	item, err := marshalObject(obj)
+	if err != nil {
+		return err
+	}
+	put := table.Put(item)
+	if prevVersion := w.PrevVersion(); prevVersion > 0 {
+		put.If("$ = ?", "Version", prevVersion)
+	} else if w.Created {
+		put.If("attribute_not_exists($)", "ID")
 	} else {
-		return nil, errors.Wrap(err, "error while checking existence")
+		// objects created before versioning
+		put.If("attribute_not_exists($)", "Version")
 	}
+	tx.Put(put).Put(table.Put(newRevisionItem(rev)))
+	return nil
+}
 
+func (db *DynamoDatabase) runTx(ctx context.Context, tx *dynamo.WriteTx) error {
+	if err := tx.RunWithContext(ctx); err != nil {
+		if isConditionalCheckFailed(err) {
+			// the object has been written concurrently with the same version.
+			return database.ErrConflict
+		}
+		return errors.Wrap(err, "failed to write to DynamoDB")
+	}
+	return nil
+}
+
+// unmarshalObject unmarshals given DynamoDB item of the type into an object.
+func unmarshalObject(typ string, items map[string]*dynamodb.AttributeValue) (*database.Object, error) {
+	// trick: copy the data attrs into Data object, since the result is flattened
+	items["Data"] = &dynamodb.AttributeValue{M: make(map[string]*dynamodb.AttributeValue)}
+	for key, value := range items {
+		if !isReserved(key) {
+			items["Data"].M[key] = value
+		}
+	}
+
+	// now we can unmarshal it xD
+	obj := database.Object{}
+	if err := dynamo.UnmarshalItem(items, &obj); err != nil {
+		return nil, err
+	}
+	obj.Type = typ
+	if expiry, ok := items[expiryAttribute]; ok && expiry.N != nil {
+		expiresAt, err := strconv.ParseInt(*expiry.N, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid expiry")
+		}
+		obj.ExpiresAt = time.Unix(expiresAt, 0)
+	}
+	return &obj, nil
+}
+
+// marshalObject marshals given object into a DynamoDB item, flattening the data.
+// Keys of the data should have been checked by the writes, but they are checked again
+// since a reserved key would overwrite the fields of the object.
+func marshalObject(obj *database.Object) (map[string]*dynamodb.AttributeValue, error) {
 	item, err := dynamo.MarshalItem(obj)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal data")
 	}
-
-	// flatten the obj.Data
-	for k, v := range item["Data"].M {
-		item[k] = v
+	if data, ok := item["Data"]; ok {
+		for k, v := range data.M {
+			if isReserved(k) {
+				return nil, database.ErrReservedKey
+			}
+			item[k] = v
+		}
+		delete(item, "Data")
+	}
+	if !obj.ExpiresAt.IsZero() {
+		item[expiryAttribute] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(obj.ExpiresAt.Unix(), 10))}
+		item[expiringAttribute] = &dynamodb.AttributeValue{S: aws.String(expiringPartition)}
 	}
-	delete(item, "Data")
+	if obj.Deleted {
+		// tombstones only retain the ID and the version
+		delete(item, "Owner")
+		delete(item, "CreatedAt")
+	}
+	return item, nil
+}
 
-	table := db.svc.Table(db.tablePrefix + typ)
-	if err := table.Put(item).RunWithContext(ctx); err != nil {
-		return nil, errors.Wrap(err, "failed to write to DynamoDB")
+// isReserved returns whether given attribute is one of the fields of objects rather than the data.
+func isReserved(key string) bool {
+	for _, reserved := range database.ReservedKeys {
+		if key == reserved {
+			return true
+		}
 	}
-	return &database.PutResult{
-		FeeUsed: 0,
-		Created: created,
-	}, nil
+	return false
 }
@@ -0,0 +1,520 @@
+package dynamodatabase
+
+import (
+	"context"
+	"fmt"
+	"github.com/airbloc/airframe/auth"
+	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/database/databasetest"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var sign = databasetest.Sign
+
+// newTestDatabase connects to the DynamoDB at DYNAMODB_ENDPOINT such as DynamoDB Local,
+// with tables prefixed by the name of the test. The test is skipped if it is not set.
+func newTestDatabase(t *testing.T) *DynamoDatabase {
+	endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DYNAMODB_ENDPOINT is not set")
+	}
+	sess, err := session.NewSession(aws.NewConfig().
+		WithEndpoint(endpoint).
+		WithRegion("us-east-1").
+		WithCredentials(credentials.NewStaticCredentials("test", "test", "")))
+	require.NoError(t, err)
+
+	db := New(sess, fmt.Sprintf("%s_%d", t.Name(), time.Now().UnixNano()))
+	require.NoError(t, db.Init(context.TODO()))
+	return db
+}
+
+// newStubDatabase returns a database whose requests are answered by respond instead of DynamoDB,
+// so that the backend can be tested without DYNAMODB_ENDPOINT. respond fills the output of the
+// operation or returns an error. The tables of testdata, schemas and usages are assumed to exist.
+func newStubDatabase(t *testing.T, respond func(op string, input, output interface{}) error, options ...database.Option) *DynamoDatabase {
+	sess, err := session.NewSession(aws.NewConfig().
+		WithRegion("us-east-1").
+		WithMaxRetries(0).
+		WithCredentials(credentials.NewStaticCredentials("test", "test", "")))
+	require.NoError(t, err)
+
+	db := New(sess, "test_", options...)
+	client := db.svc.Client().(*dynamodb.DynamoDB)
+	client.Handlers.Send.Clear()
+	client.Handlers.UnmarshalMeta.Clear()
+	client.Handlers.Unmarshal.Clear()
+	client.Handlers.UnmarshalError.Clear()
+	client.Handlers.ValidateResponse.Clear()
+	client.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}
+		if r.Error = respond(r.Operation.Name, r.Params, r.Data); r.Error != nil {
+			r.HTTPResponse.StatusCode = http.StatusBadRequest
+			r.Retryable = aws.Bool(false)
+		}
+	})
+	for _, name := range []string{"test_testdata", db.schemaTableName(), db.usageTableName()} {
+		db.tables[name] = true
+	}
+	return db
+}
+
+// stubItem returns the DynamoDB item of a testdata object.
+func stubItem(t *testing.T, id string) map[string]*dynamodb.AttributeValue {
+	item, err := marshalObject(&database.Object{ID: id, Type: "testdata", Data: database.Payload{"foo": "bar"}, Version: 1})
+	require.NoError(t, err)
+	return item
+}
+
+func TestMarshalObject(t *testing.T) {
+	obj := &database.Object{
+		ID:        "1",
+		Type:      "testdata",
+		Data:      database.Payload{"foo": "bar"},
+		Version:   2,
+		ExpiresAt: time.Unix(time.Now().Unix()+60, 0),
+	}
+	item, err := marshalObject(obj)
+	require.NoError(t, err)
+	require.NotContains(t, item, "Data")
+
+	unmarshaled, err := unmarshalObject("testdata", item)
+	require.NoError(t, err)
+	require.Equal(t, obj.Data, unmarshaled.Data)
+	require.Equal(t, obj.Version, unmarshaled.Version)
+	require.Equal(t, obj.ExpiresAt, unmarshaled.ExpiresAt)
+	require.Equal(t, expiringPartition, aws.StringValue(item[expiringAttribute].S))
+	require.NotContains(t, unmarshaled.Data, expiringAttribute)
+
+	// tombstones only retain the ID and the version, so that they are removed from the expiry index
+	item, err = marshalObject(database.Expire(obj))
+	require.NoError(t, err)
+	require.NotContains(t, item, "Owner")
+	require.NotContains(t, item, expiryAttribute)
+	require.NotContains(t, item, expiringAttribute)
+}
+
+func TestMarshalObject_ReservedKeys(t *testing.T) {
+	for _, key := range database.ReservedKeys {
+		// reserved keys would overwrite the fields of the object, such as the version or the tombstone
+		obj := &database.Object{
+			ID:      "1",
+			Type:    "testdata",
+			Data:    database.Payload{"foo": "bar", key: 1},
+			Version: 2,
+		}
+		_, err := marshalObject(obj)
+		require.Equal(t, database.ErrReservedKey, err, key)
+	}
+}
+
+func TestDynamoDatabase_Put_ReservedKeys(t *testing.T) {
+	ctx := context.TODO()
+	db := newTestDatabase(t)
+	priv, _ := crypto.GenerateKey()
+
+	data := database.Payload{"foo": "bar"}
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, data))
+	_, err := db.Put(ctx, "testdata", "1", data, sig, database.PutOptions{})
+	require.NoError(t, err)
+
+	for _, key := range database.ReservedKeys {
+		data := database.Payload{"foo": "bar", key: 1}
+		sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, data))
+		_, err = db.Put(ctx, "testdata", "1", data, sig, database.PutOptions{})
+		require.Equal(t, database.ErrReservedKey, err, key)
+	}
+	obj, err := db.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), obj.Version)
+	require.Equal(t, data, obj.Data)
+}
+
+func TestDynamoDatabase_Sweep(t *testing.T) {
+	ctx := context.TODO()
+	db := newTestDatabase(t)
+	priv, _ := crypto.GenerateKey()
+	data := database.Payload{"foo": "bar"}
+
+	expiresAt := time.Now().Add(time.Hour)
+	sig := sign(t, priv, auth.GetExpiringObjectHash(auth.DefaultDomain, "testdata", "1", 1, data, expiresAt.Unix()))
+	_, err := db.Put(ctx, "testdata", "1", data, sig, database.PutOptions{ExpiresAt: expiresAt})
+	require.NoError(t, err)
+
+	swept, err := db.Sweep(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 0, swept)
+
+	swept, err = db.Sweep(ctx, expiresAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, swept)
+
+	// a tombstone without TTL is left, so that the version is kept after the expiry
+	results, err := db.BatchGet(ctx, []database.ObjectKey{{Type: "testdata", ID: "1"}}, true)
+	require.NoError(t, err)
+	require.True(t, results[0].Object.Deleted)
+	require.Equal(t, uint64(1), results[0].Object.Version)
+	require.True(t, results[0].Object.ExpiresAt.IsZero())
+
+	// the revisions remain with their signatures, but their data are erased
+	revisions, err := db.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	require.Nil(t, revisions[0].Data)
+	require.Equal(t, sig, revisions[0].Signature)
+
+	// replaying the signature of the first version does not recreate the object of the owner,
+	// since it is recovered for the next version of the tombstone
+	result, err := db.Put(ctx, "testdata", "1", data, sig, database.PutOptions{ExpiresAt: expiresAt})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result.Version)
+	obj, err := db.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.NotEqual(t, crypto.CompressPubkey(&priv.PublicKey), obj.Owner[:])
+}
+
+func TestBuildFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		filter string
+		args   []interface{}
+	}{
+		{"Empty", `{}`, "", nil},
+		{"Equals", `{"foo": "bar"}`, "$ = ?", []interface{}{"foo", "bar"}},
+		{"Operator", `{"age": {"gte": 20}}`, "$ >= ?", []interface{}{"age", float64(20)}},
+		{
+			"And", `{"$and": [{"age": {"gte": 20}}, {"age": {"lt": 30}}]}`,
+			"(($ >= ?) AND ($ < ?))", []interface{}{"age", float64(20), "age", float64(30)},
+		},
+		{
+			"Or", `{"$or": [{"gender": "Male"}, {"name": {"contains": "Kim"}}]}`,
+			"(($ = ?) OR (contains($, ?)))", []interface{}{"gender", "Male", "name", "Kim"},
+		},
+		{
+			"Not", `{"$not": {"name": {"contains": "Kim"}}}`,
+			"(NOT (contains($, ?)))", []interface{}{"name", "Kim"},
+		},
+		{
+			"Nested", `{"$or": [{"gender": "Male"}, {"$not": {"age": {"gt": 30}}}]}`,
+			"(($ = ?) OR ((NOT ($ > ?))))", []interface{}{"gender", "Male", "age", float64(30)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := database.QueryFromJson(test.query)
+			require.NoError(t, err)
+
+			filter, args := buildFilter(query)
+			require.Equal(t, test.filter, filter)
+			require.Equal(t, test.args, args)
+		})
+	}
+}
+
+func TestDynamoDatabase_Init(t *testing.T) {
+	db := newStubDatabase(t, func(op string, input, output interface{}) error {
+		switch op {
+		case "ListTables":
+			output.(*dynamodb.ListTablesOutput).TableNames = aws.StringSlice([]string{"other_testdata", "test_testdata"})
+		case "DescribeTable":
+			// only the tables with the table prefix are checked
+			require.Equal(t, "test_testdata", aws.StringValue(input.(*dynamodb.DescribeTableInput).TableName))
+			output.(*dynamodb.DescribeTableOutput).Table = &dynamodb.TableDescription{
+				TableName: aws.String("test_testdata"),
+				AttributeDefinitions: []*dynamodb.AttributeDefinition{
+					{AttributeName: aws.String("ID"), AttributeType: aws.String(dynamodb.ScalarAttributeTypeS)},
+					{AttributeName: aws.String("Version"), AttributeType: aws.String(dynamodb.ScalarAttributeTypeN)},
+				},
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("ID"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+					{AttributeName: aws.String("Version"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+				},
+			}
+		default:
+			t.Fatalf("unexpected %s", op)
+		}
+		return nil
+	})
+	require.EqualError(t, db.Init(context.TODO()), "table test_testdata should have a string hash key named ID")
+}
+
+func TestDynamoDatabase_Init_ExpiryIndex(t *testing.T) {
+	var update *dynamodb.UpdateTableInput
+	db := newStubDatabase(t, func(op string, input, output interface{}) error {
+		switch op {
+		case "ListTables":
+			output.(*dynamodb.ListTablesOutput).TableNames = aws.StringSlice([]string{"test_testdata"})
+		case "DescribeTable":
+			// a table created before supporting expiry
+			output.(*dynamodb.DescribeTableOutput).Table = &dynamodb.TableDescription{
+				TableName: aws.String("test_testdata"),
+				AttributeDefinitions: []*dynamodb.AttributeDefinition{
+					{AttributeName: aws.String("ID"), AttributeType: aws.String(dynamodb.ScalarAttributeTypeS)},
+				},
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("ID"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+				},
+			}
+		case "UpdateTable":
+			update = input.(*dynamodb.UpdateTableInput)
+			output.(*dynamodb.UpdateTableOutput).TableDescription = &dynamodb.TableDescription{TableName: aws.String("test_testdata")}
+		default:
+			t.Fatalf("unexpected %s", op)
+		}
+		return nil
+	})
+	require.NoError(t, db.Init(context.TODO()))
+
+	require.NotNil(t, update)
+	require.Len(t, update.GlobalSecondaryIndexUpdates, 1)
+	index := update.GlobalSecondaryIndexUpdates[0].Create
+	require.Equal(t, expiryIndex, aws.StringValue(index.IndexName))
+	require.Equal(t, expiringAttribute, aws.StringValue(index.KeySchema[0].AttributeName))
+	require.Equal(t, expiryAttribute, aws.StringValue(index.KeySchema[1].AttributeName))
+}
+
+func TestDynamoDatabase_Sweep_ExpiryIndex(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	ops := []string{}
+	var tombstone map[string]*dynamodb.AttributeValue
+	var erased []*dynamodb.WriteRequest
+	db := newStubDatabase(t, func(op string, input, output interface{}) error {
+		ops = append(ops, op)
+		switch op {
+		case "ListTables":
+			output.(*dynamodb.ListTablesOutput).TableNames = aws.StringSlice([]string{"test_testdata", "test__schemas"})
+		case "Query":
+			// the expired objects are queried from the index, which only projects their versions
+			q := input.(*dynamodb.QueryInput)
+			require.Equal(t, expiryIndex, aws.StringValue(q.IndexName))
+			require.Equal(t, expiringPartition, aws.StringValue(q.KeyConditions[expiringAttribute].AttributeValueList[0].S))
+			require.Equal(t, dynamodb.ComparisonOperatorLe, aws.StringValue(q.KeyConditions[expiryAttribute].ComparisonOperator))
+			require.Equal(t, strconv.FormatInt(now.Unix(), 10), aws.StringValue(q.KeyConditions[expiryAttribute].AttributeValueList[0].N))
+			output.(*dynamodb.QueryOutput).Items = []map[string]*dynamodb.AttributeValue{{
+				"ID":              {S: aws.String("1")},
+				"Version":         {N: aws.String("1")},
+				expiryAttribute:   {N: aws.String(strconv.FormatInt(now.Unix()-1, 10))},
+				expiringAttribute: {S: aws.String(expiringPartition)},
+			}}
+		case "PutItem":
+			tombstone = input.(*dynamodb.PutItemInput).Item
+		case "GetItem":
+			output.(*dynamodb.GetItemOutput).Item = tombstone
+		case "BatchGetItem":
+			rev := &database.Revision{Object: database.Object{ID: "1", Data: database.Payload{"foo": "bar"}, Version: 1}}
+			item, err := dynamo.MarshalItem(newRevisionItem(rev))
+			require.NoError(t, err)
+			output.(*dynamodb.BatchGetItemOutput).Responses = map[string][]map[string]*dynamodb.AttributeValue{
+				"test_testdata": {item},
+			}
+		case "BatchWriteItem":
+			erased = input.(*dynamodb.BatchWriteItemInput).RequestItems["test_testdata"]
+		default:
+			t.Fatalf("unexpected %s", op)
+		}
+		return nil
+	})
+
+	swept, err := db.Sweep(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, swept)
+
+	// the tables are never scanned
+	require.Equal(t, []string{"ListTables", "Query", "PutItem", "GetItem", "BatchGetItem", "BatchWriteItem"}, ops)
+	require.Equal(t, "1", aws.StringValue(tombstone["Version"].N))
+	require.NotContains(t, tombstone, expiringAttribute)
+
+	require.Len(t, erased, 1)
+	require.Equal(t, "1/1", aws.StringValue(erased[0].PutRequest.Item["ID"].S))
+	require.NotContains(t, erased[0].PutRequest.Item["Revision"].M, "Data")
+}
+
+func TestDynamoDatabase_Query_Cursor(t *testing.T) {
+	var scans []*dynamodb.ScanInput
+	db := newStubDatabase(t, func(op string, input, output interface{}) error {
+		require.Equal(t, "Scan", op)
+		scan := *input.(*dynamodb.ScanInput)
+		scans = append(scans, &scan)
+
+		// each page has two items, and the table has more pages
+		from := 2 * len(scans)
+		page := output.(*dynamodb.ScanOutput)
+		page.Items = []map[string]*dynamodb.AttributeValue{
+			stubItem(t, strconv.Itoa(from)), stubItem(t, strconv.Itoa(from+1)),
+		}
+		page.LastEvaluatedKey = map[string]*dynamodb.AttributeValue{"ID": {S: aws.String(strconv.Itoa(from + 1))}}
+		return nil
+	})
+
+	query, err := database.QueryFromJson(`{"foo": "bar"}`)
+	require.NoError(t, err)
+	result, err := db.Query(context.TODO(), "testdata", query, database.QueryOptions{
+		Cursor: &database.Cursor{ID: "1"},
+		Limit:  2,
+	})
+	require.NoError(t, err)
+
+	// the scan starts from the cursor, and stops after an extra item to check whether there are more items
+	require.Len(t, scans, 2)
+	require.Equal(t, "test_testdata", aws.StringValue(scans[0].TableName))
+	require.Equal(t, int64(3), aws.Int64Value(scans[0].Limit))
+	require.Equal(t, "1", aws.StringValue(scans[0].ExclusiveStartKey["ID"].S))
+	require.Equal(t, "3", aws.StringValue(scans[1].ExclusiveStartKey["ID"].S))
+	require.Len(t, result.Objects, 2)
+	require.Equal(t, "2", result.Objects[0].ID)
+	require.Equal(t, &database.Cursor{ID: "3"}, result.NextCursor)
+}
+
+func TestDynamoDatabase_BatchGet(t *testing.T) {
+	keysOfTable := make(map[string]int)
+	db := newStubDatabase(t, func(op string, input, output interface{}) error {
+		require.Equal(t, "BatchGetItem", op)
+		for table, keys := range input.(*dynamodb.BatchGetItemInput).RequestItems {
+			keysOfTable[table] += len(keys.Keys)
+		}
+		// objects are returned in any order, and nonexistent ones are omitted
+		if _, ok := input.(*dynamodb.BatchGetItemInput).RequestItems["test_testdata"]; ok {
+			output.(*dynamodb.BatchGetItemOutput).Responses = map[string][]map[string]*dynamodb.AttributeValue{
+				"test_testdata": {stubItem(t, "2"), stubItem(t, "1")},
+			}
+		}
+		return nil
+	})
+
+	results, err := db.BatchGet(context.TODO(), []database.ObjectKey{
+		{Type: "testdata", ID: "1"},
+		{Type: "other", ID: "1"},
+		{Type: "testdata", ID: "2"},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"test_testdata": 2, "test_other": 1}, keysOfTable)
+	require.Len(t, results, 3)
+	require.Equal(t, "1", results[0].Object.ID)
+	require.Equal(t, database.ErrNotExists, results[1].Err)
+	require.Equal(t, "2", results[2].Object.ID)
+}
+
+func TestDynamoDatabase_Transact_Conflict(t *testing.T) {
+	priv, _ := crypto.GenerateKey()
+	var updates []*dynamodb.UpdateItemInput
+	var tx *dynamodb.TransactWriteItemsInput
+	db := newStubDatabase(t, func(op string, input, output interface{}) error {
+		switch op {
+		case "BatchGetItem", "Query":
+			// neither the objects nor the schemas exist
+		case "UpdateItem":
+			updates = append(updates, input.(*dynamodb.UpdateItemInput))
+		case "TransactWriteItems":
+			tx = input.(*dynamodb.TransactWriteItemsInput)
+			return awserr.New(dynamodb.ErrCodeTransactionCanceledException, "transaction cancelled", nil)
+		default:
+			t.Fatalf("unexpected %s", op)
+		}
+		return nil
+	})
+
+	data := database.Payload{"foo": "bar"}
+	_, err := db.Transact(context.TODO(), []database.TxOp{
+		{
+			ObjectKey: database.ObjectKey{Type: "testdata", ID: "1"},
+			Op:        database.TxPut,
+			Data:      data,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, data)),
+		},
+		{ObjectKey: database.ObjectKey{Type: "testdata", ID: "2"}, Op: database.TxCheck},
+	})
+	require.Equal(t, database.ErrConflict, err)
+
+	// the object and its revision are written with the check in a transaction
+	require.Len(t, tx.TransactItems, 3)
+	put := tx.TransactItems[0].Put
+	require.NotNil(t, put)
+	require.Contains(t, aws.StringValue(put.ConditionExpression), "attribute_not_exists")
+	for _, name := range put.ExpressionAttributeNames {
+		require.Equal(t, "ID", aws.StringValue(name))
+	}
+	require.NotNil(t, tx.TransactItems[1].Put)
+	require.NotNil(t, tx.TransactItems[2].ConditionCheck)
+
+	// the fee charged before the transaction is refunded
+	require.Len(t, updates, 2)
+	require.Equal(t, updates[0].Key, updates[1].Key)
+}
+
+func TestDynamoDatabase_Get_RevisionID(t *testing.T) {
+	ctx := context.TODO()
+	db := newStubDatabase(t, func(op string, input, output interface{}) error {
+		// revisions are in the same table, but they should never be read as objects
+		t.Fatalf("unexpected %s", op)
+		return nil
+	})
+
+	_, err := db.Get(ctx, "testdata", "1/1")
+	require.Equal(t, database.ErrNotExists, err)
+	exists, err := db.Exists(ctx, "testdata", "1/1")
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = db.History(ctx, "testdata", "1/1")
+	require.Equal(t, database.ErrNotExists, err)
+	_, err = database.GetRevision(ctx, db, "testdata", "1/1", 1)
+	require.Equal(t, database.ErrNotExists, err)
+
+	results, err := db.BatchGet(ctx, []database.ObjectKey{{Type: "testdata", ID: "1/1"}}, true)
+	require.NoError(t, err)
+	require.Equal(t, database.ErrNotExists, results[0].Err)
+}
+
+func TestDynamoDatabase_Query_PrivateObjects(t *testing.T) {
+	priv, _ := crypto.GenerateKey()
+	reader := crypto.PubkeyToAddress(priv.PublicKey)
+	var owner auth.PublicKey
+	copy(owner[:], crypto.CompressPubkey(&priv.PublicKey))
+
+	scans := 0
+	db := newStubDatabase(t, func(op string, input, output interface{}) error {
+		require.Equal(t, "Scan", op)
+		scans++
+
+		// each page has a private object of the reader and a private object of others
+		page := output.(*dynamodb.ScanOutput)
+		for _, id := range []string{strconv.Itoa(2 * scans), strconv.Itoa(2*scans + 1)} {
+			obj := &database.Object{ID: id, Type: "testdata", Data: database.Payload{"foo": "bar"}, Version: 1, Private: true}
+			if len(page.Items) == 0 {
+				obj.Owner = owner
+			}
+			item, err := marshalObject(obj)
+			require.NoError(t, err)
+			page.Items = append(page.Items, item)
+		}
+		page.LastEvaluatedKey = map[string]*dynamodb.AttributeValue{"ID": {S: aws.String(strconv.Itoa(2*scans + 1))}}
+		return nil
+	})
+
+	result, err := db.Query(context.TODO(), "testdata", &database.Query{}, database.QueryOptions{
+		Limit:  2,
+		Reader: &reader,
+	})
+	require.NoError(t, err)
+
+	// the scan is paged until an extra readable object is found, rather than scanning the whole table
+	require.Equal(t, 3, scans)
+	require.Len(t, result.Objects, 2)
+	require.Equal(t, "2", result.Objects[0].ID)
+	require.Equal(t, "4", result.Objects[1].ID)
+	require.Equal(t, &database.Cursor{ID: "4"}, result.NextCursor)
+}
@@ -0,0 +1,36 @@
+package dynamodatabase
+
+import (
+	"github.com/airbloc/airframe/tracing"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("database/dynamodb")
+
+// traceRequests starts a span of every DynamoDB request including its retries,
+// as a child of the span in the context of the request.
+func traceRequests(handlers *request.Handlers) {
+	// validation is the first step of a request, and completion is the last one even if it fails
+	handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "airframe.StartSpan",
+		Fn: func(r *request.Request) {
+			ctx, _ := tracer.Start(r.Context(), "DynamoDB."+r.Operation.Name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("db.system", "dynamodb"),
+					attribute.String("rpc.method", r.Operation.Name),
+				))
+			r.SetContext(ctx)
+		},
+	})
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "airframe.EndSpan",
+		Fn: func(r *request.Request) {
+			span := trace.SpanFromContext(r.Context())
+			span.SetAttributes(attribute.Int("aws.retry_count", r.RetryCount))
+			tracing.EndSpan(span, r.Error)
+		},
+	})
+}
@@ -0,0 +1,150 @@
+package database
+
+// Evaluator filters objects with a query in process, for backends which cannot
+// evaluate queries natively. Objects are fed one by one, and the matching objects
+// after the cursor are collected after skipping first `Skip` matches, up to `Limit` objects.
+//
+// If a sort is given, every matching object is collected and sorted before applying
+// the cursor, skip and limit. Otherwise, objects are expected to be fed in the order of IDs,
+// so that the evaluator can stop as soon as the limit is reached.
+// Tombstones of deleted objects and private objects which the reader cannot read are ignored.
+type Evaluator struct {
+	query *Query
+	opts  QueryOptions
+
+	skipped int
+	seen    map[string]bool
+	objects []*Object
+}
+
+// NewEvaluator creates an evaluator of given query.
+// A nil or empty query matches all objects, and a zero limit means no limit.
+func NewEvaluator(q *Query, opts QueryOptions) *Evaluator {
+	return &Evaluator{
+		query:   q,
+		opts:    opts,
+		seen:    make(map[string]bool),
+		objects: []*Object{},
+	}
+}
+
+// Feed evaluates given object. It returns false if the evaluator
+// does not need any more objects since the limit has been reached.
+func (e *Evaluator) Feed(obj *Object) bool {
+	if e.opts.Sort == nil && e.full() {
+		return false
+	}
+	key := obj.Type + "/" + obj.ID
+	if !IsLive(obj) || e.seen[key] || !CanRead(obj, e.opts.Reader) {
+		return true
+	}
+	if e.query != nil && !e.query.Match(obj) {
+		return true
+	}
+	e.seen[key] = true
+
+	if e.opts.Sort != nil {
+		// cursor, skip and limit are applied after sorting
+		e.objects = append(e.objects, obj)
+		return true
+	}
+	if e.opts.Cursor != nil && !e.opts.Cursor.Precedes(obj, nil) {
+		return true
+	}
+	if e.skipped < e.opts.Skip {
+		e.skipped++
+		return true
+	}
+	e.objects = append(e.objects, obj)
+	return !e.full()
+}
+
+// full returns true if an extra object has been collected beyond the limit,
+// which tells that there are more results after the page.
+func (e *Evaluator) full() bool {
+	return e.opts.Limit > 0 && len(e.objects) > e.opts.Limit
+}
+
+// Result returns the page of the matching objects fed so far.
+func (e *Evaluator) Result() *QueryResult {
+	if e.opts.Sort != nil {
+		SortObjects(e.objects, e.opts.Sort)
+		return Paginate(e.objects, e.opts)
+	}
+	// cursor and skip has been already applied
+	return page(e.objects, nil, e.opts.Limit)
+}
+
+// Paginate returns a page of given objects after applying the cursor, skip and limit of the options.
+// The objects should be already sorted in the order of the options.
+func Paginate(objects []*Object, opts QueryOptions) *QueryResult {
+	if opts.Cursor != nil {
+		objects = objectsAfter(objects, opts.Cursor, opts.Sort)
+	}
+	if opts.Skip >= len(objects) {
+		objects = []*Object{}
+	} else {
+		objects = objects[opts.Skip:]
+	}
+	return page(objects, opts.Sort, opts.Limit)
+}
+
+// page cuts given objects by the limit, and returns them with the cursor of the next page.
+// The cursor is set only if there are more objects than the limit.
+func page(objects []*Object, sort *Sort, limit int) *QueryResult {
+	if limit <= 0 || len(objects) <= limit {
+		return &QueryResult{Objects: objects}
+	}
+	objects = objects[:limit]
+	return &QueryResult{
+		Objects:    objects,
+		NextCursor: NewCursor(objects[limit-1], sort),
+	}
+}
+
+// objectsAfter returns objects after the cursor, from the objects sorted in given order.
+func objectsAfter(objects []*Object, c *Cursor, sort *Sort) []*Object {
+	for i, obj := range objects {
+		if c.Precedes(obj, sort) {
+			return objects[i:]
+		}
+	}
+	return []*Object{}
+}
+
+// Match returns true if given object satisfies the query.
+func (q *Query) Match(obj *Object) bool {
+	switch q.Type {
+	case QueryOr:
+		for _, op := range q.Conditions {
+			if compare(obj, op) {
+				return true
+			}
+		}
+		for _, subquery := range q.Subqueries {
+			if subquery.Match(obj) {
+				return true
+			}
+		}
+		return false
+
+	case QueryNot:
+		return !q.matchAll(obj)
+	}
+	return q.matchAll(obj)
+}
+
+// matchAll returns true if given object satisfies all conditions and subqueries.
+func (q *Query) matchAll(obj *Object) bool {
+	for _, op := range q.Conditions {
+		if !compare(obj, op) {
+			return false
+		}
+	}
+	for _, subquery := range q.Subqueries {
+		if !subquery.Match(obj) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestQuery_Match(t *testing.T) {
+	obj := &Object{Data: Payload{"gender": "Female", "name": "Hyojun Kim"}}
+
+	for rawQuery, expected := range map[string]bool{
+		`{}`:                                     true,
+		`{"gender": "Female"}`:                   true,
+		`{"gender": "Female", "name": "Nobody"}`: false,
+		`{"$or": [{"gender": "Male"}, {"name": "Hyojun Kim"}]}`:          true,
+		`{"$or": [{"gender": "Male"}, {"name": "Nobody"}]}`:              false,
+		`{"$not": {"gender": "Male"}}`:                                   true,
+		`{"$not": {"name": {"contains": "Kim"}}}`:                        false,
+		`{"$and": [{"gender": "Female"}, {"$not": {"name": "Nobody"}}]}`: true,
+	} {
+		q, err := QueryFromJson(rawQuery)
+		require.NoError(t, err)
+		require.Equal(t, expected, q.Match(obj), rawQuery)
+	}
+}
+
+func TestEvaluator_And(t *testing.T) {
+	q, err := QueryFromJson(`{"gender": "Female", "name": "Hyojun Kim"}`)
+	require.NoError(t, err)
+
+	evaluator := NewEvaluator(q, QueryOptions{})
+	evaluator.Feed(&Object{ID: "1", Data: Payload{"gender": "Female", "name": "Hyojun Kim"}})
+	evaluator.Feed(&Object{ID: "2", Data: Payload{"gender": "Female", "name": "Nobody"}})
+	evaluator.Feed(&Object{ID: "3", Data: Payload{"gender": "Male", "name": "Hyojun Kim"}})
+
+	results := evaluator.Result().Objects
+	require.Equal(t, 1, len(results))
+	require.Equal(t, "1", results[0].ID)
+}
+
+func TestEvaluator_EmptyQuery(t *testing.T) {
+	q, err := QueryFromJson(`{}`)
+	require.NoError(t, err)
+
+	evaluator := NewEvaluator(q, QueryOptions{})
+	evaluator.Feed(&Object{ID: "1", Data: Payload{"foo": "bar"}})
+	evaluator.Feed(&Object{ID: "2", Data: Payload{}})
+	require.Equal(t, 2, len(evaluator.Result().Objects))
+
+	evaluator = NewEvaluator(nil, QueryOptions{})
+	evaluator.Feed(&Object{ID: "1", Data: Payload{"foo": "bar"}})
+	require.Equal(t, 1, len(evaluator.Result().Objects))
+}
+
+func TestEvaluator_NoDuplicates(t *testing.T) {
+	q, err := QueryFromJson(`{"$or": [{"foo": "bar"}, {"foo": {"contains": "b"}}]}`)
+	require.NoError(t, err)
+
+	obj := &Object{ID: "1", Type: "testdata", Data: Payload{"foo": "bar"}}
+	evaluator := NewEvaluator(q, QueryOptions{})
+	evaluator.Feed(obj)
+	evaluator.Feed(obj)
+	require.Equal(t, 1, len(evaluator.Result().Objects))
+}
+
+func TestEvaluator_SkipAndLimit(t *testing.T) {
+	evaluator := NewEvaluator(nil, QueryOptions{Skip: 1, Limit: 2})
+	require.True(t, evaluator.Feed(&Object{ID: "1"}))
+	require.True(t, evaluator.Feed(&Object{ID: "2"}))
+	require.True(t, evaluator.Feed(&Object{ID: "3"}))
+	require.False(t, evaluator.Feed(&Object{ID: "4"}))
+	require.False(t, evaluator.Feed(&Object{ID: "5"}))
+
+	result := evaluator.Result()
+	require.Equal(t, 2, len(result.Objects))
+	require.Equal(t, "2", result.Objects[0].ID)
+	require.Equal(t, "3", result.Objects[1].ID)
+	require.Equal(t, &Cursor{ID: "3"}, result.NextCursor)
+}
+
+func TestEvaluator_Cursor(t *testing.T) {
+	evaluator := NewEvaluator(nil, QueryOptions{Cursor: &Cursor{ID: "2"}, Limit: 2})
+	for _, id := range []string{"1", "2", "3", "4"} {
+		evaluator.Feed(&Object{ID: id})
+	}
+	result := evaluator.Result()
+	require.Equal(t, 2, len(result.Objects))
+	require.Equal(t, "3", result.Objects[0].ID)
+	require.Equal(t, "4", result.Objects[1].ID)
+
+	// no more pages
+	require.Nil(t, result.NextCursor)
+}
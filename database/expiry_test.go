@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestApplyPut_Expiry(t *testing.T) {
+	ctx := context.TODO()
+	priv, _ := crypto.GenerateKey()
+
+	past := time.Now().Add(-time.Hour)
+	_, err := ApplyPut(ctx, NewConfig(), nil, "testdata", "1", testData1, sign(t, priv, auth.GetExpiringObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1, past.Unix())), PutOptions{ExpiresAt: past})
+	require.Equal(t, ErrInvalidExpiry, err)
+
+	// the expiry is signed, so that it cannot be stripped or changed
+	expiresAt := time.Now().Add(time.Hour)
+	w, err := ApplyPut(ctx, NewConfig(), nil, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{ExpiresAt: expiresAt})
+	require.NoError(t, err)
+	require.NotEqual(t, publicKeyOf(priv), w.Object.Owner)
+
+	w, err = ApplyPut(ctx, NewConfig(), nil, "testdata", "1", testData1, sign(t, priv, auth.GetExpiringObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1, expiresAt.Unix())), PutOptions{ExpiresAt: expiresAt})
+	require.NoError(t, err)
+	require.Equal(t, publicKeyOf(priv), w.Object.Owner)
+	require.Equal(t, expiresAt.Unix(), w.Object.ExpiresAt.Unix())
+	require.Equal(t, 0, w.Object.ExpiresAt.Nanosecond())
+
+	// updates without the expiry make the object never expire
+	w, err = ApplyPut(ctx, NewConfig(), w.Object, "testdata", "1", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), PutOptions{})
+	require.NoError(t, err)
+	require.True(t, w.Object.ExpiresAt.IsZero())
+}
+
+func TestInMemoryDatabase_Expiry(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	owner, _ := crypto.GenerateKey()
+
+	expiresAt := time.Unix(time.Now().Add(time.Second).Unix(), 0)
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, owner, auth.GetExpiringObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1, expiresAt.Unix())), PutOptions{ExpiresAt: expiresAt})
+	require.NoError(t, err)
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, expiresAt, obj.ExpiresAt)
+
+	time.Sleep(time.Until(expiresAt))
+
+	// expired objects are treated as nonexistent before being swept
+	_, err = imdb.Get(ctx, "testdata", "1")
+	require.Equal(t, ErrNotExists, err)
+	exists, err := imdb.Exists(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.False(t, exists)
+	result, err := imdb.Query(ctx, "testdata", &Query{}, QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Objects, 0)
+	revisions, err := imdb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Nil(t, revisions[0].Data)
+
+	// the version continues from the expired object
+	version, err := imdb.Version(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), version)
+
+	other, _ := crypto.GenerateKey()
+	putResult, err := imdb.Put(ctx, "testdata", "1", testData2, sign(t, other, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), PutOptions{})
+	require.NoError(t, err)
+	require.True(t, putResult.Created)
+	require.Equal(t, uint64(2), putResult.Version)
+
+	revisions, err = imdb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Nil(t, revisions[0].Data)
+	require.Equal(t, testData2, revisions[1].Data)
+}
+
+func TestInMemoryDatabase_Sweep(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	expiresAt := time.Now().Add(time.Hour)
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetExpiringObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1, expiresAt.Unix())), PutOptions{ExpiresAt: expiresAt})
+	require.NoError(t, err)
+	_, err = imdb.Put(ctx, "testdata", "2", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData2)), PutOptions{})
+	require.NoError(t, err)
+
+	var sweeper Sweeper = imdb
+	swept, err := sweeper.Sweep(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 0, swept)
+
+	swept, err = sweeper.Sweep(ctx, expiresAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, swept)
+
+	// only a tombstone of the version is left
+	results, err := imdb.BatchGet(ctx, []ObjectKey{{"testdata", "1"}, {"testdata", "2"}}, true)
+	require.NoError(t, err)
+	require.True(t, results[0].Object.Deleted)
+	require.Equal(t, uint64(1), results[0].Object.Version)
+	require.Nil(t, results[0].Object.Data)
+	require.Equal(t, testData2, results[1].Object.Data)
+
+	revisions, err := imdb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Nil(t, revisions[0].Data)
+	require.NotEmpty(t, revisions[0].Signature)
+
+	swept, err = sweeper.Sweep(ctx, expiresAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 0, swept)
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/common"
+	"github.com/pkg/errors"
+	"math"
+)
+
+// ErrQuotaExceeded is raised when the fee of a write exceeds the prepaid quota of the payer.
+var ErrQuotaExceeded = errors.New("the prepaid quota has been exhausted.")
+
+// FeeModel computes the fee of a write, which is charged to the payer of the write.
+type FeeModel interface {
+	Fee(w *Write) uint64
+}
+
+// LinearFeeModel charges PerWrite for every write and PerByte for every byte of the data
+// stored by the write, multiplied by the multiplier of the type. Tombstones have no data.
+type LinearFeeModel struct {
+	PerWrite uint64
+	PerByte  uint64
+
+	// Multipliers are the multipliers of the types, which are 1 if not given.
+	Multipliers map[string]float64
+}
+
+func (m *LinearFeeModel) Fee(w *Write) uint64 {
+	fee := m.PerWrite
+	if m.PerByte > 0 && w.Object.Data != nil {
+		data, _ := json.Marshal(w.Object.Data)
+		fee += m.PerByte * uint64(len(data))
+	}
+	if multiplier, ok := m.Multipliers[w.Object.Type]; ok {
+		return uint64(math.Ceil(float64(fee) * multiplier))
+	}
+	return fee
+}
+
+// Usage is the fees charged to an owner and the prepaid quota deposited by the admins.
+type Usage struct {
+	Owner common.Address
+
+	// Writes is the number of the charged writes.
+	Writes  uint64
+	FeeUsed uint64
+
+	// Deposited is the total amount of the deposits. Deposits is the number of them,
+	// which is signed with each deposit like the versions of objects.
+	Deposited uint64
+	Deposits  uint64
+}
+
+// Balance returns the remaining quota, which is 0 if the fees have exceeded the deposits.
+func (u *Usage) Balance() uint64 {
+	if u.FeeUsed >= u.Deposited {
+		return 0
+	}
+	return u.Deposited - u.FeeUsed
+}
+
+// Charge returns the usage after charging given fees. If the quota is enforced by the configuration,
+// ErrQuotaExceeded is returned when the fees exceed the balance.
+func (u *Usage) Charge(config *Config, c *Charge) (*Usage, error) {
+	if config.QuotaEnforced && c.Fee > u.Balance() {
+		return nil, ErrQuotaExceeded
+	}
+	charged := *u
+	charged.Writes += c.Writes
+	charged.FeeUsed += c.Fee
+	return &charged, nil
+}
+
+// Charge is the fees of writes charged to a payer.
+type Charge struct {
+	Payer  common.Address
+	Fee    uint64
+	Writes uint64
+}
+
+// Payer returns the public key of the payer of the write, who is the owner of the object
+// before the write. The signer pays for deletions and transfers, which is the previous owner.
+func (w *Write) Payer() auth.PublicKey {
+	if w.Object.Deleted || w.Revision.Transfer != nil {
+		return w.Revision.Signer
+	}
+	return w.Object.Owner
+}
+
+// ChargesOf sets the fees of given writes with the fee model of the configuration, and returns
+// the charges aggregated by payer in the order of the writes. Nil writes are skipped.
+func ChargesOf(config *Config, writes ...*Write) ([]*Charge, error) {
+	var charges []*Charge
+	chargeOf := make(map[common.Address]*Charge)
+	for _, w := range writes {
+		if w == nil {
+			continue
+		}
+		payer, err := auth.AddressOf(w.Payer())
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid payer")
+		}
+		w.Fee = config.FeeModel.Fee(w)
+
+		c, ok := chargeOf[payer]
+		if !ok {
+			c = &Charge{Payer: payer}
+			chargeOf[payer] = c
+			charges = append(charges, c)
+		}
+		c.Fee += w.Fee
+		c.Writes++
+	}
+	return charges, nil
+}
+
+// ApplyDeposit verifies that the deposit is signed by one of the admins for the next deposit
+// of the owner, and returns the usage after the deposit.
+func ApplyDeposit(ctx context.Context, config *Config, current *Usage, amount uint64, signature []byte) (*Usage, error) {
+	signer, err := auth.GetDepositSigner(ctx, config.Domain, current.Owner, current.Deposits+1, amount, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signature")
+	}
+	addr, err := auth.AddressOf(signer)
+	if err != nil || !config.IsAdmin(addr) {
+		return nil, ErrNotAuthorized
+	}
+	deposited := *current
+	deposited.Deposited += amount
+	deposited.Deposits++
+	return &deposited, nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLinearFeeModel_Fee(t *testing.T) {
+	ctx := context.TODO()
+	priv, _ := crypto.GenerateKey()
+	w, err := ApplyPut(ctx, NewConfig(), nil, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// {"foo":"bar"} is 13 bytes
+	model := &LinearFeeModel{PerWrite: 10, PerByte: 2}
+	require.Equal(t, uint64(36), model.Fee(w))
+
+	model.Multipliers = map[string]float64{"testdata": 0.5, "other": 2}
+	require.Equal(t, uint64(18), model.Fee(w))
+
+	// tombstones are charged only for the write
+	w, err = ApplyDelete(ctx, NewConfig(), w.Object, sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 2)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), model.Fee(w))
+}
+
+func TestInMemoryDatabase_Usage(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase(WithFeeModel(&LinearFeeModel{PerWrite: 10, PerByte: 1}))
+	priv, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(priv.PublicKey)
+
+	result, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(23), result.FeeUsed)
+	require.NoError(t, imdb.Delete(ctx, "testdata", "1", sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 2))))
+
+	usage, err := imdb.Usage(ctx, owner)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), usage.Writes)
+	require.Equal(t, uint64(33), usage.FeeUsed)
+
+	// fees exceeding the deposits are recorded unless the quota is enforced
+	require.Equal(t, uint64(0), usage.Balance())
+}
+
+func TestInMemoryDatabase_Deposit(t *testing.T) {
+	ctx := context.TODO()
+	admin, withAdmin := newTestAdmin()
+	imdb, _ := NewInMemoryDatabase(withAdmin)
+	owner := common.HexToAddress("0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef")
+
+	// only the admins can deposit
+	other, _ := crypto.GenerateKey()
+	_, err := imdb.Deposit(ctx, owner, 100, sign(t, other, auth.GetDepositHash(auth.DefaultDomain, owner, 1, 100)))
+	require.Equal(t, ErrNotAuthorized, err)
+
+	usage, err := imdb.Deposit(ctx, owner, 100, sign(t, admin, auth.GetDepositHash(auth.DefaultDomain, owner, 1, 100)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), usage.Deposited)
+	require.Equal(t, uint64(1), usage.Deposits)
+
+	// deposits cannot be replayed
+	_, err = imdb.Deposit(ctx, owner, 100, sign(t, admin, auth.GetDepositHash(auth.DefaultDomain, owner, 1, 100)))
+	require.Equal(t, ErrNotAuthorized, err)
+
+	usage, err = imdb.Deposit(ctx, owner, 50, sign(t, admin, auth.GetDepositHash(auth.DefaultDomain, owner, 2, 50)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(150), usage.Balance())
+}
+
+func TestInMemoryDatabase_QuotaEnforced(t *testing.T) {
+	ctx := context.TODO()
+	admin, withAdmin := newTestAdmin()
+	imdb, _ := NewInMemoryDatabase(withAdmin, WithFeeModel(&LinearFeeModel{PerWrite: 10}), WithQuotaEnforced(true))
+	priv, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(priv.PublicKey)
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.Equal(t, ErrQuotaExceeded, err)
+	exists, err := imdb.Exists(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, err = imdb.Deposit(ctx, owner, 15, sign(t, admin, auth.GetDepositHash(auth.DefaultDomain, owner, 1, 15)))
+	require.NoError(t, err)
+	_, err = imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// a transaction is charged all-or-nothing
+	_, err = imdb.Transact(ctx, []TxOp{
+		{ObjectKey: ObjectKey{"testdata", "2"}, Op: TxPut, Data: testData1, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1))},
+	})
+	require.Equal(t, ErrQuotaExceeded, err)
+
+	usage, err := imdb.Usage(ctx, owner)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), usage.Writes)
+	require.Equal(t, uint64(5), usage.Balance())
+}
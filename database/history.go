@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"time"
+)
+
+// Revision is a snapshot of the object right after a write, with the signature of the write.
+// Revisions of deletions are snapshots of the tombstones.
+// The time of the write is LastUpdatedAt of the snapshot.
+type Revision struct {
+	Object
+
+	// Signer is the public key recovered from the signature.
+	Signer    auth.PublicKey
+	Signature []byte
+
+	// Patch is the patch document signed instead of the data, if the revision is written by a patch.
+	Patch *Patch `dynamo:",omitempty"`
+
+	// Transfer is the transfer of the ownership, if the revision is written by a transfer.
+	// The signer is the previous owner, and the owner of the snapshot is the new owner.
+	Transfer *Transfer `dynamo:",omitempty"`
+}
+
+// NewRevision returns a revision of given object written with given signature.
+func NewRevision(obj *Object, signer auth.PublicKey, signature []byte) *Revision {
+	return &Revision{
+		Object:    *obj,
+		Signer:    signer,
+		Signature: signature,
+	}
+}
+
+// GetRevision returns the object as of given version from its history.
+// ErrNotExists is returned if there's no such version, the object was deleted at the version,
+// or the data of the version has been erased.
+func GetRevision(ctx context.Context, db Database, typ, id string, version uint64) (*Object, error) {
+	revisions, err := db.History(ctx, typ, id)
+	if err != nil {
+		return nil, err
+	}
+	return snapshotOf(revisionOf(revisions, version))
+}
+
+// GetAt returns the object as of given time from its history.
+// ErrNotExists is returned if the object did not exist or had expired at the time,
+// or the data at the time has been erased.
+func GetAt(ctx context.Context, db Database, typ, id string, at time.Time) (*Object, error) {
+	revisions, err := db.History(ctx, typ, id)
+	if err != nil {
+		return nil, err
+	}
+	rev := revisionAt(revisions, at)
+	if rev != nil && rev.Expired(at) {
+		return nil, ErrNotExists
+	}
+	return snapshotOf(rev)
+}
+
+// EraseExpired returns the revisions with their data erased if the object has expired
+// but has not been swept yet, as if the sweeper had erased them. The revisions should be sorted by version.
+func EraseExpired(revisions []*Revision) []*Revision {
+	if len(revisions) == 0 {
+		return revisions
+	}
+	if latest := revisions[len(revisions)-1]; latest.Deleted || !latest.Expired(time.Now()) {
+		return revisions
+	}
+	erased := make([]*Revision, len(revisions))
+	for i, rev := range revisions {
+		r := *rev
+		r.Data = nil
+		r.Patch = nil
+		erased[i] = &r
+	}
+	return erased
+}
+
+// snapshotOf returns the object of the revision. Since revisions of live objects always have data,
+// revisions without data have been erased by a deletion or an expiry.
+func snapshotOf(rev *Revision) (*Object, error) {
+	if rev == nil || rev.Deleted || rev.Data == nil {
+		return nil, ErrNotExists
+	}
+	obj := rev.Object
+	return &obj, nil
+}
+
+// revisionOf returns the revision of given version, or nil if there's no such revision.
+// The revisions should be sorted by version.
+func revisionOf(revisions []*Revision, version uint64) *Revision {
+	for _, rev := range revisions {
+		if rev.Version == version {
+			return rev
+		}
+	}
+	return nil
+}
+
+// revisionAt returns the latest revision written at or before given time,
+// or nil if the object did not exist at the time. The revisions should be sorted by version.
+func revisionAt(revisions []*Revision, at time.Time) *Revision {
+	var latest *Revision
+	for _, rev := range revisions {
+		if rev.LastUpdatedAt.After(at) {
+			break
+		}
+		latest = rev
+	}
+	return latest
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestInMemoryDatabase_History(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := imdb.History(ctx, "testdata", "1")
+	require.Equal(t, ErrNotExists, err)
+
+	sig1 := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+	_, err = imdb.Put(ctx, "testdata", "1", testData1, sig1, PutOptions{})
+	require.NoError(t, err)
+	sig2 := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2))
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sig2, PutOptions{})
+	require.NoError(t, err)
+
+	revisions, err := imdb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, uint64(1), revisions[0].Version)
+	require.Equal(t, testData1, revisions[0].Data)
+	require.Equal(t, sig1, revisions[0].Signature)
+	require.Equal(t, crypto.CompressPubkey(&priv.PublicKey), revisions[0].Signer[:])
+	require.Equal(t, uint64(2), revisions[1].Version)
+	require.Equal(t, testData2, revisions[1].Data)
+	require.Equal(t, sig2, revisions[1].Signature)
+
+	// data of the revisions should be erased on deletion
+	require.NoError(t, imdb.Delete(ctx, "testdata", "1", sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 3))))
+	revisions, err = imdb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	for _, rev := range revisions {
+		require.Nil(t, rev.Data)
+	}
+	require.Equal(t, sig1, revisions[0].Signature)
+	require.True(t, revisions[2].Deleted)
+}
+
+func TestGetRevision(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), PutOptions{})
+	require.NoError(t, err)
+
+	obj, err := GetRevision(ctx, imdb, "testdata", "1", 1)
+	require.NoError(t, err)
+	require.Equal(t, testData1, obj.Data)
+	require.Equal(t, uint64(1), obj.Version)
+
+	obj, err = GetRevision(ctx, imdb, "testdata", "1", 2)
+	require.NoError(t, err)
+	require.Equal(t, testData2, obj.Data)
+
+	_, err = GetRevision(ctx, imdb, "testdata", "1", 3)
+	require.Equal(t, ErrNotExists, err)
+	_, err = GetRevision(ctx, imdb, "testdata", "2", 1)
+	require.Equal(t, ErrNotExists, err)
+}
+
+func TestGetAt(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	beforeCreation := time.Now()
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+	afterCreation := time.Now()
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), PutOptions{})
+	require.NoError(t, err)
+	afterUpdate := time.Now()
+
+	_, err = GetAt(ctx, imdb, "testdata", "1", beforeCreation)
+	require.Equal(t, ErrNotExists, err)
+
+	obj, err := GetAt(ctx, imdb, "testdata", "1", afterCreation)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), obj.Version)
+	require.Equal(t, testData1, obj.Data)
+
+	obj, err = GetAt(ctx, imdb, "testdata", "1", afterUpdate)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), obj.Version)
+
+	// data of the deleted object should not be readable from the past
+	require.NoError(t, imdb.Delete(ctx, "testdata", "1", sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 3))))
+	_, err = GetAt(ctx, imdb, "testdata", "1", afterCreation)
+	require.Equal(t, ErrNotExists, err)
+	_, err = GetAt(ctx, imdb, "testdata", "1", afterUpdate)
+	require.Equal(t, ErrNotExists, err)
+	_, err = GetRevision(ctx, imdb, "testdata", "1", 2)
+	require.Equal(t, ErrNotExists, err)
+
+	_, err = GetAt(ctx, imdb, "testdata", "1", time.Now())
+	require.Equal(t, ErrNotExists, err)
+}
@@ -0,0 +1,720 @@
+// package leveldb implements LevelDB backend interface,
+// which persists objects on the local disk.
+package leveldatabase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/airbloc/airframe/database"
+	"github.com/json-iterator/go"
+	"github.com/klaytn/klaytn/common"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	json = jsoniter.ConfigCompatibleWithStandardLibrary
+)
+
+type LevelDatabase struct {
+	db     *leveldb.DB
+	config *database.Config
+
+	// writeLock serializes read-modify-write cycles of Put and Delete.
+	writeLock sync.Mutex
+}
+
+// New opens (or creates) LevelDB database on given directory.
+func New(dataDir string, options ...database.Option) (*LevelDatabase, error) {
+	db, err := leveldb.OpenFile(dataDir, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open LevelDB on %s", dataDir)
+	}
+	return &LevelDatabase{db: db, config: database.NewConfig(options...)}, nil
+}
+
+// Ping checks whether the database is open by reading a key.
+func (ldb *LevelDatabase) Ping(ctx context.Context) error {
+	if _, err := ldb.db.Has([]byte(usagePrefix), nil); err != nil {
+		return errors.Wrap(err, "failed to read from LevelDB")
+	}
+	return nil
+}
+
+// objectKey returns a key of the object, with a format of "<type>/<id>".
+func objectKey(typ, id string) []byte {
+	return []byte(typ + "/" + id)
+}
+
+func typePrefix(typ string) []byte {
+	return []byte(typ + "/")
+}
+
+// schemaPrefix is prefixed to the keys of schemas with the null byte,
+// so that they are separated from objects, whose types cannot have the null byte.
+const schemaPrefix = "\x00schema/"
+
+// schemaKey returns a key of the schema, with a format of "\x00schema/<type>/<version>".
+func schemaKey(typ string, version uint64) []byte {
+	return []byte(fmt.Sprintf("%s%s/%020d", schemaPrefix, typ, version))
+}
+
+// usagePrefix is prefixed to the keys of the usages of owners like schemaPrefix.
+const usagePrefix = "\x00usage/"
+
+// usageKey returns a key of the usage of the owner, with a format of "\x00usage/<address>".
+func usageKey(owner common.Address) []byte {
+	return []byte(usagePrefix + owner.Hex())
+}
+
+// revisionPrefix is prefixed to the keys of revisions like schemaPrefix,
+// so that queries do not iterate over the revisions of the objects.
+const revisionPrefix = "\x00rev/"
+
+// revisionKey returns a key of the revision, with a format of "\x00rev/<type>/<id>/<version>".
+// Since types and IDs cannot contain slashes, the history of an object never has revisions of others.
+// The version is zero-padded to keep revisions in order.
+func revisionKey(typ, id string, version uint64) []byte {
+	return []byte(fmt.Sprintf("%s%s/%s/%020d", revisionPrefix, typ, id, version))
+}
+
+func historyPrefix(typ, id string) []byte {
+	return []byte(revisionPrefix + typ + "/" + id + "/")
+}
+
+// expiryPrefix is prefixed to the keys of the expiry index like schemaPrefix.
+const expiryPrefix = "\x00expiry/"
+
+// expiryKey returns a key of the expiry index, with a format of "\x00expiry/<unix time>/<type>/<id>".
+// The time is zero-padded, so that the index is iterated in the order of expiry.
+func expiryKey(expiresAt int64, typ, id string) []byte {
+	return []byte(fmt.Sprintf("%s%020d/%s/%s", expiryPrefix, expiresAt, typ, id))
+}
+
+func (ldb *LevelDatabase) Get(ctx context.Context, typ, id string) (*database.Object, error) {
+	obj, err := ldb.get(typ, id)
+	if err != nil {
+		return nil, err
+	}
+	if !database.IsLive(obj) {
+		return nil, database.ErrNotExists
+	}
+	return obj, nil
+}
+
+func (ldb *LevelDatabase) Exists(ctx context.Context, typ, id string) (bool, error) {
+	_, err := ldb.Get(ctx, typ, id)
+	if err == database.ErrNotExists {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (ldb *LevelDatabase) Version(ctx context.Context, typ, id string) (uint64, error) {
+	obj, err := ldb.get(typ, id)
+	if err == database.ErrNotExists {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return obj.Version, nil
+}
+
+// get returns the stored object including tombstones.
+func (ldb *LevelDatabase) get(typ, id string) (*database.Object, error) {
+	if !database.IsValidType(typ) {
+		return nil, database.ErrNotExists
+	}
+	value, err := ldb.db.Get(objectKey(typ, id), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, database.ErrNotExists
+		}
+		return nil, errors.Wrap(err, "failed to get item from LevelDB")
+	}
+	return decodeObject(value)
+}
+
+// current returns the stored object including tombstones, or nil if the object has never existed.
+func (ldb *LevelDatabase) current(typ, id string) (*database.Object, error) {
+	obj, err := ldb.get(typ, id)
+	if err == database.ErrNotExists {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "error while checking existence")
+	}
+	return obj, nil
+}
+
+// Query returns objects matching with given query.
+// Objects are ordered by ID by default, since LevelDB iterates keys in order.
+func (ldb *LevelDatabase) Query(ctx context.Context, typ string, q *database.Query, opts database.QueryOptions) (*database.QueryResult, error) {
+	evaluator := database.NewEvaluator(q, opts)
+	if !database.IsValidType(typ) {
+		return evaluator.Result(), nil
+	}
+	iter := ldb.db.NewIterator(util.BytesPrefix(typePrefix(typ)), nil)
+	defer iter.Release()
+
+	var ok bool
+	if opts.Sort == nil && opts.Cursor != nil {
+		// jump to the object right after the cursor
+		cursorKey := objectKey(typ, opts.Cursor.ID)
+		if ok = iter.Seek(cursorKey); ok && bytes.Equal(iter.Key(), cursorKey) {
+			ok = iter.Next()
+		}
+	} else {
+		ok = iter.Next()
+	}
+
+	prefixLen := len(typePrefix(typ))
+	for ; ok; ok = iter.Next() {
+		if bytes.IndexByte(iter.Key()[prefixLen:], '/') >= 0 {
+			// objects of another type with a slash, which were written before types were validated
+			continue
+		}
+		obj, err := decodeObject(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		if !evaluator.Feed(obj) {
+			break
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate items from LevelDB")
+	}
+	return evaluator.Result(), nil
+}
+
+func (ldb *LevelDatabase) Put(ctx context.Context, typ, id string, data database.Payload, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return ldb.update(ctx, typ, id, func(current *database.Object) (w *database.Write, err error) {
+		if opts.Schema, err = ldb.schemaOf(typ); err != nil {
+			return nil, err
+		}
+		return database.ApplyPut(ctx, ldb.config, current, typ, id, data, signature, opts)
+	})
+}
+
+// Patch applies given patch to the object atomically.
+func (ldb *LevelDatabase) Patch(ctx context.Context, typ, id string, patch *database.Patch, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return ldb.update(ctx, typ, id, func(current *database.Object) (w *database.Write, err error) {
+		if opts.Schema, err = ldb.schemaOf(typ); err != nil {
+			return nil, err
+		}
+		return database.ApplyPatch(ctx, ldb.config, current, patch, signature, opts)
+	})
+}
+
+// TransferOwnership moves the ownership of the object atomically.
+func (ldb *LevelDatabase) TransferOwnership(ctx context.Context, typ, id string, transfer *database.Transfer, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return ldb.update(ctx, typ, id, func(current *database.Object) (*database.Write, error) {
+		return database.ApplyTransfer(ctx, ldb.config, current, transfer, signature, opts)
+	})
+}
+
+// SetACL replaces the access control list of the object atomically.
+func (ldb *LevelDatabase) SetACL(ctx context.Context, typ, id string, acl *database.ACL, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return ldb.update(ctx, typ, id, func(current *database.Object) (*database.Write, error) {
+		return database.ApplyACL(ctx, ldb.config, current, acl, signature, opts)
+	})
+}
+
+// SetVisibility makes the object private or public atomically.
+func (ldb *LevelDatabase) SetVisibility(ctx context.Context, typ, id string, private bool, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	return ldb.update(ctx, typ, id, func(current *database.Object) (*database.Write, error) {
+		return database.ApplyVisibility(ctx, ldb.config, current, private, signature, opts)
+	})
+}
+
+// Delete removes the object if the signature is made by the owner.
+// Only a tombstone retaining the ID and the version is left, and the data of
+// the previous revisions are erased while their signatures are kept in the history.
+func (ldb *LevelDatabase) Delete(ctx context.Context, typ, id string, signature []byte) error {
+	_, err := ldb.update(ctx, typ, id, func(current *database.Object) (*database.Write, error) {
+		return database.ApplyDelete(ctx, ldb.config, current, signature)
+	})
+	return err
+}
+
+// update writes the object with the write which apply makes from the current object,
+// while holding the write lock so that the object is not changed in the meantime.
+// The current object is nil if the object has never existed.
+func (ldb *LevelDatabase) update(ctx context.Context, typ, id string, apply func(current *database.Object) (*database.Write, error)) (*database.PutResult, error) {
+	ldb.writeLock.Lock()
+	defer ldb.writeLock.Unlock()
+
+	current, err := ldb.current(typ, id)
+	if err != nil {
+		return nil, err
+	}
+	w, err := apply(current)
+	if err != nil {
+		return nil, err
+	}
+	if err := ldb.write(ctx, w); err != nil {
+		return nil, err
+	}
+	return w.Result(), nil
+}
+
+// erasedHistory returns the revisions of the object with their data erased,
+// which should be written on deletion or expiry.
+func (ldb *LevelDatabase) erasedHistory(ctx context.Context, typ, id string) ([]*database.Revision, error) {
+	revisions, err := ldb.History(ctx, typ, id)
+	if err != nil && err != database.ErrNotExists {
+		return nil, err
+	}
+	for _, rev := range revisions {
+		rev.Data = nil
+		rev.Patch = nil
+	}
+	return revisions, nil
+}
+
+func (ldb *LevelDatabase) History(ctx context.Context, typ, id string) ([]*database.Revision, error) {
+	if !database.IsValidType(typ) {
+		return nil, database.ErrNotExists
+	}
+	iter := ldb.db.NewIterator(util.BytesPrefix(historyPrefix(typ, id)), nil)
+	defer iter.Release()
+
+	revisions := []*database.Revision{}
+	for iter.Next() {
+		rev := new(database.Revision)
+		if err := json.Unmarshal(iter.Value(), rev); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal item from LevelDB")
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate items from LevelDB")
+	}
+	if len(revisions) == 0 {
+		return nil, database.ErrNotExists
+	}
+	return database.EraseExpired(revisions), nil
+}
+
+func (ldb *LevelDatabase) BatchGet(ctx context.Context, keys []database.ObjectKey, includeDeleted bool) ([]*database.BatchGetResult, error) {
+	if err := database.ValidateBatch(keys); err != nil {
+		return nil, err
+	}
+	// read from a snapshot, so that the objects are consistent with each other.
+	snapshot, err := ldb.db.GetSnapshot()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get snapshot of LevelDB")
+	}
+	defer snapshot.Release()
+
+	results := make([]*database.BatchGetResult, len(keys))
+	for i, key := range keys {
+		if !database.IsValidType(key.Type) {
+			results[i] = database.NewBatchGetResult(nil, includeDeleted)
+			continue
+		}
+		value, err := snapshot.Get(objectKey(key.Type, key.ID), nil)
+		if err == leveldb.ErrNotFound {
+			results[i] = database.NewBatchGetResult(nil, includeDeleted)
+			continue
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to get item from LevelDB")
+		}
+		obj, err := decodeObject(value)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = database.NewBatchGetResult(obj, includeDeleted)
+	}
+	return results, nil
+}
+
+// BatchPut verifies every write on the current objects, and stores the succeeded ones at once.
+func (ldb *LevelDatabase) BatchPut(ctx context.Context, items []database.PutItem) ([]*database.BatchPutResult, error) {
+	if err := database.ValidateBatch(database.KeysOf(items)); err != nil {
+		return nil, err
+	}
+	ldb.writeLock.Lock()
+	defer ldb.writeLock.Unlock()
+
+	batch := new(leveldb.Batch)
+	charged := make(map[common.Address]*database.Usage)
+	results := make([]*database.BatchPutResult, len(items))
+	for i, item := range items {
+		current, err := ldb.current(item.Type, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		if item.Options.Schema, err = ldb.schemaOf(item.Type); err != nil {
+			return nil, err
+		}
+		w, err := database.ApplyPut(ctx, ldb.config, current, item.Type, item.ID, item.Data, item.Signature, item.Options)
+		if err != nil {
+			results[i] = &database.BatchPutResult{Err: err}
+			continue
+		}
+		if err := ldb.charge(batch, charged, w); isRejected(err) {
+			results[i] = &database.BatchPutResult{Err: err}
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if err := ldb.addToBatch(ctx, batch, w); err != nil {
+			return nil, err
+		}
+		results[i] = &database.BatchPutResult{Result: w.Result()}
+	}
+	if err := ldb.db.Write(batch, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to write to LevelDB")
+	}
+	return results, nil
+}
+
+// Transact commits given operations in a single LevelDB batch while holding the write lock.
+func (ldb *LevelDatabase) Transact(ctx context.Context, ops []database.TxOp) ([]*database.PutResult, error) {
+	if err := database.ValidateTx(ops); err != nil {
+		return nil, err
+	}
+	ldb.writeLock.Lock()
+	defer ldb.writeLock.Unlock()
+
+	ops = append([]database.TxOp{}, ops...)
+	current := make([]*database.Object, len(ops))
+	for i, op := range ops {
+		var err error
+		if current[i], err = ldb.current(op.Type, op.ID); err != nil {
+			return nil, err
+		}
+		if ops[i].Options.Schema, err = ldb.schemaOf(op.Type); err != nil {
+			return nil, err
+		}
+	}
+	writes, err := database.ApplyTx(ctx, ldb.config, current, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := new(leveldb.Batch)
+	if err := ldb.charge(batch, make(map[common.Address]*database.Usage), writes...); err != nil {
+		return nil, err
+	}
+	results := make([]*database.PutResult, len(ops))
+	for i, w := range writes {
+		if w == nil {
+			continue
+		}
+		if err := ldb.addToBatch(ctx, batch, w); err != nil {
+			return nil, err
+		}
+		results[i] = w.Result()
+	}
+	if err := ldb.db.Write(batch, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to write to LevelDB")
+	}
+	return results, nil
+}
+
+func (ldb *LevelDatabase) SetSchema(ctx context.Context, typ, definition string, signature []byte, opts database.PutOptions) (*database.Schema, error) {
+	ldb.writeLock.Lock()
+	defer ldb.writeLock.Unlock()
+
+	current, err := ldb.schemaOf(typ)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := database.ApplySchema(ctx, ldb.config, current, typ, definition, signature, opts)
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(schema)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal schema")
+	}
+	if err := ldb.db.Put(schemaKey(typ, schema.Version), value, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to write to LevelDB")
+	}
+	return schema, nil
+}
+
+func (ldb *LevelDatabase) GetSchema(ctx context.Context, typ string, version uint64) (*database.Schema, error) {
+	if version == 0 {
+		schema, err := ldb.schemaOf(typ)
+		if err == nil && schema == nil {
+			return nil, database.ErrNotExists
+		}
+		return schema, err
+	}
+	value, err := ldb.db.Get(schemaKey(typ, version), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, database.ErrNotExists
+		}
+		return nil, errors.Wrap(err, "failed to get item from LevelDB")
+	}
+	return decodeSchema(value)
+}
+
+func (ldb *LevelDatabase) ListSchemas(ctx context.Context) ([]*database.Schema, error) {
+	iter := ldb.db.NewIterator(util.BytesPrefix([]byte(schemaPrefix)), nil)
+	defer iter.Release()
+
+	// versions of a schema are iterated in order, so the last one is the latest
+	latest := make(map[string]*database.Schema)
+	for iter.Next() {
+		schema, err := decodeSchema(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		latest[schema.Type] = schema
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate items from LevelDB")
+	}
+	schemas := make([]*database.Schema, 0, len(latest))
+	for _, schema := range latest {
+		schemas = append(schemas, schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Type < schemas[j].Type
+	})
+	return schemas, nil
+}
+
+// schemaOf returns the latest schema of the type, or nil if none has been set.
+func (ldb *LevelDatabase) schemaOf(typ string) (*database.Schema, error) {
+	iter := ldb.db.NewIterator(util.BytesPrefix([]byte(schemaPrefix+typ+"/")), nil)
+	defer iter.Release()
+
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		schema, err := decodeSchema(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		// schemas of another type with a slash can share the prefix
+		if schema.Type == typ {
+			return schema, nil
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate items from LevelDB")
+	}
+	return nil, nil
+}
+
+// Sweep replaces the expired objects with tombstones. The expired objects are found from
+// the expiry index, whose entries are left behind when the expiry of an object is changed,
+// so each entry is removed after checking the expiry of the current object.
+func (ldb *LevelDatabase) Sweep(ctx context.Context, now time.Time) (int, error) {
+	ldb.writeLock.Lock()
+	defer ldb.writeLock.Unlock()
+
+	expired := &util.Range{Start: []byte(expiryPrefix), Limit: expiryKey(now.Unix()+1, "", "")}
+	iter := ldb.db.NewIterator(expired, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+	swept := make(map[database.ObjectKey]bool)
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+
+		var key database.ObjectKey
+		if err := json.Unmarshal(iter.Value(), &key); err != nil {
+			return 0, errors.Wrap(err, "failed to unmarshal expiry from LevelDB")
+		}
+		obj, err := ldb.current(key.Type, key.ID)
+		if err != nil {
+			return 0, err
+		}
+		if obj == nil || obj.Deleted || !obj.Expired(now) || swept[key] {
+			continue
+		}
+		revisions, err := ldb.erasedHistory(ctx, key.Type, key.ID)
+		if err != nil {
+			return 0, err
+		}
+		if err := putObject(batch, database.Expire(obj), revisions...); err != nil {
+			return 0, err
+		}
+		swept[key] = true
+	}
+	if err := iter.Error(); err != nil {
+		return 0, errors.Wrap(err, "failed to iterate items from LevelDB")
+	}
+	if err := ldb.db.Write(batch, nil); err != nil {
+		return 0, errors.Wrap(err, "failed to write to LevelDB")
+	}
+	return len(swept), nil
+}
+
+func (ldb *LevelDatabase) Usage(ctx context.Context, owner common.Address) (*database.Usage, error) {
+	return ldb.usageOf(owner)
+}
+
+func (ldb *LevelDatabase) Deposit(ctx context.Context, owner common.Address, amount uint64, signature []byte) (*database.Usage, error) {
+	ldb.writeLock.Lock()
+	defer ldb.writeLock.Unlock()
+
+	current, err := ldb.usageOf(owner)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := database.ApplyDeposit(ctx, ldb.config, current, amount, signature)
+	if err != nil {
+		return nil, err
+	}
+	batch := new(leveldb.Batch)
+	if err := putUsage(batch, usage); err != nil {
+		return nil, err
+	}
+	if err := ldb.db.Write(batch, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to write to LevelDB")
+	}
+	return usage, nil
+}
+
+// usageOf returns the usage of the owner, which is empty if nothing has been charged or deposited.
+func (ldb *LevelDatabase) usageOf(owner common.Address) (*database.Usage, error) {
+	value, err := ldb.db.Get(usageKey(owner), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return &database.Usage{Owner: owner}, nil
+		}
+		return nil, errors.Wrap(err, "failed to get usage from LevelDB")
+	}
+	usage := new(database.Usage)
+	if err := json.Unmarshal(value, usage); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal usage from LevelDB")
+	}
+	return usage, nil
+}
+
+// charge adds the usages of the payers after charging the fees of given writes to the batch.
+// Usages charged earlier in the same batch are given with charged, since they have not been
+// written yet. If any signer has exceeded the rate limit or any payer cannot afford the fees,
+// an error is returned without adding anything.
+func (ldb *LevelDatabase) charge(batch *leveldb.Batch, charged map[common.Address]*database.Usage, writes ...*database.Write) error {
+	if err := database.LimitWrites(ldb.config, writes...); err != nil {
+		return err
+	}
+	charges, err := database.ChargesOf(ldb.config, writes...)
+	if err != nil {
+		return err
+	}
+	usages := make([]*database.Usage, len(charges))
+	for i, c := range charges {
+		usage, ok := charged[c.Payer]
+		if !ok {
+			if usage, err = ldb.usageOf(c.Payer); err != nil {
+				return err
+			}
+		}
+		if usages[i], err = usage.Charge(ldb.config, c); err != nil {
+			return err
+		}
+	}
+	for _, usage := range usages {
+		if err := putUsage(batch, usage); err != nil {
+			return err
+		}
+		charged[usage.Owner] = usage
+	}
+	return nil
+}
+
+// isRejected returns true if the write is rejected by the rate limit or the quota of the signer,
+// which fails only the item in a batch.
+func isRejected(err error) bool {
+	_, limited := err.(*database.RateLimitError)
+	return limited || err == database.ErrQuotaExceeded
+}
+
+func putUsage(batch *leveldb.Batch, usage *database.Usage) error {
+	value, err := json.Marshal(usage)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal usage")
+	}
+	batch.Put(usageKey(usage.Owner), value)
+	return nil
+}
+
+// write charges the fee of given write, and puts the object with its revisions atomically.
+func (ldb *LevelDatabase) write(ctx context.Context, w *database.Write) error {
+	batch := new(leveldb.Batch)
+	if err := ldb.charge(batch, make(map[common.Address]*database.Usage), w); err != nil {
+		return err
+	}
+	if err := ldb.addToBatch(ctx, batch, w); err != nil {
+		return err
+	}
+	if err := ldb.db.Write(batch, nil); err != nil {
+		return errors.Wrap(err, "failed to write to LevelDB")
+	}
+	return nil
+}
+
+// addToBatch adds the object of given write to the batch with its revision,
+// and the previous revisions with their data erased if the write erases the history.
+func (ldb *LevelDatabase) addToBatch(ctx context.Context, batch *leveldb.Batch, w *database.Write) error {
+	revisions := []*database.Revision{w.Revision}
+	if w.EraseHistory {
+		erased, err := ldb.erasedHistory(ctx, w.Object.Type, w.Object.ID)
+		if err != nil {
+			return err
+		}
+		revisions = append(erased, w.Revision)
+	}
+	return putObject(batch, w.Object, revisions...)
+}
+
+// putObject adds the object with given revisions to the batch,
+// with an entry of the expiry index if the object expires.
+func putObject(batch *leveldb.Batch, obj *database.Object, revisions ...*database.Revision) error {
+	value, err := json.Marshal(obj)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal data")
+	}
+	batch.Put(objectKey(obj.Type, obj.ID), value)
+
+	if !obj.ExpiresAt.IsZero() {
+		key, err := json.Marshal(database.ObjectKey{Type: obj.Type, ID: obj.ID})
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal expiry")
+		}
+		batch.Put(expiryKey(obj.ExpiresAt.Unix(), obj.Type, obj.ID), key)
+	}
+
+	for _, rev := range revisions {
+		value, err := json.Marshal(rev)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal revision")
+		}
+		batch.Put(revisionKey(rev.Type, rev.ID, rev.Version), value)
+	}
+	return nil
+}
+
+// Close closes the underlying LevelDB.
+func (ldb *LevelDatabase) Close() error {
+	return ldb.db.Close()
+}
+
+func decodeObject(value []byte) (*database.Object, error) {
+	obj := new(database.Object)
+	if err := json.Unmarshal(value, obj); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal item from LevelDB")
+	}
+	return obj, nil
+}
+
+func decodeSchema(value []byte) (*database.Schema, error) {
+	schema := new(database.Schema)
+	if err := json.Unmarshal(value, schema); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal schema from LevelDB")
+	}
+	return schema, nil
+}
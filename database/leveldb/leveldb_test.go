@@ -0,0 +1,684 @@
+package leveldatabase
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/database/databasetest"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+var sign = databasetest.Sign
+
+var (
+	testData1 = database.Payload{"foo": "bar"}
+	testData2 = database.Payload{"foo": "baz"}
+)
+
+func newTestDatabase(t *testing.T, options ...database.Option) (*LevelDatabase, func()) {
+	dataDir, err := ioutil.TempDir("", "airframe-leveldb")
+	require.NoError(t, err)
+
+	ldb, err := New(dataDir, options...)
+	require.NoError(t, err)
+	return ldb, func() {
+		ldb.Close()
+		os.RemoveAll(dataDir)
+	}
+}
+
+func TestLevelDatabase_Put(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	// test creation
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+	result, err := ldb.Put(ctx, "testdata", "1", testData1, sig, database.PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, true, result.Created)
+
+	// test update
+	newSig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2))
+	result, err = ldb.Put(ctx, "testdata", "1", testData2, newSig, database.PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, false, result.Created)
+
+	require.Equal(t, uint64(2), result.Version)
+
+	// test replay of the previous version
+	_, err = ldb.Put(ctx, "testdata", "1", testData1, sig, database.PutOptions{})
+	require.Equal(t, database.ErrNotAuthorized, err)
+
+	// test update from non-owner
+	otherPriv, _ := crypto.GenerateKey()
+	otherSig := sign(t, otherPriv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 3, testData1))
+	_, err = ldb.Put(ctx, "testdata", "1", testData1, otherSig, database.PutOptions{})
+	require.Equal(t, database.ErrNotAuthorized, err)
+}
+
+func TestLevelDatabase_Put_ExpectedVersion(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{ExpectedVersion: 1})
+	require.Equal(t, database.ErrConflict, err)
+
+	_, err = ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+	_, err = ldb.Put(ctx, "testdata", "1", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), database.PutOptions{ExpectedVersion: 1})
+	require.NoError(t, err)
+
+	_, err = ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData1)), database.PutOptions{ExpectedVersion: 1})
+	require.Equal(t, database.ErrConflict, err)
+}
+
+func TestLevelDatabase_Patch(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+
+	patch := &database.Patch{Type: database.JSONPatch, Document: []byte(`[{"op": "add", "path": "/count", "value": 1}]`)}
+	sig := sign(t, priv, auth.GetPatchHash(auth.DefaultDomain, "testdata", "1", 1, string(patch.Type), patch.Document))
+	result, err := ldb.Patch(ctx, "testdata", "1", patch, sig, database.PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result.Version)
+
+	obj, err := ldb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, database.Payload{"foo": "bar", "count": 1.0}, obj.Data)
+
+	revisions, err := ldb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, patch, revisions[1].Patch)
+}
+
+func TestLevelDatabase_Delete(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sig, database.PutOptions{})
+	require.NoError(t, err)
+
+	deleteSig := sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 2))
+	require.NoError(t, ldb.Delete(ctx, "testdata", "1", deleteSig))
+
+	_, err = ldb.Get(ctx, "testdata", "1")
+	require.Equal(t, database.ErrNotExists, err)
+	result, err := ldb.Query(ctx, "testdata", nil, database.QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Objects, 0)
+
+	// signatures made before the deletion should not re-create the object with the same owner
+	putResult, err := ldb.Put(ctx, "testdata", "1", testData1, sig, database.PutOptions{})
+	require.NoError(t, err)
+	require.True(t, putResult.Created)
+	require.Equal(t, uint64(3), putResult.Version)
+
+	obj, err := ldb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.NotEqual(t, crypto.CompressPubkey(&priv.PublicKey), obj.Owner[:])
+}
+
+func TestLevelDatabase_BatchPut(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+
+	results, err := ldb.BatchPut(ctx, []database.PutItem{
+		{
+			ObjectKey: database.ObjectKey{Type: "testdata", ID: "1"},
+			Data:      testData2,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)),
+		},
+		{
+			ObjectKey: database.ObjectKey{Type: "testdata", ID: "2"},
+			Data:      testData1,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1)),
+		},
+		{
+			ObjectKey: database.ObjectKey{Type: "testdata", ID: "3"},
+			Data:      testData1,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "3", 1, testData1)),
+			Options:   database.PutOptions{ExpectedVersion: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, uint64(2), results[0].Result.Version)
+	require.NoError(t, results[1].Err)
+	require.True(t, results[1].Result.Created)
+	require.Equal(t, database.ErrConflict, results[2].Err)
+
+	getResults, err := ldb.BatchGet(ctx, []database.ObjectKey{
+		{Type: "testdata", ID: "1"},
+		{Type: "testdata", ID: "2"},
+		{Type: "testdata", ID: "3"},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, testData2, getResults[0].Object.Data)
+	require.Equal(t, testData1, getResults[1].Object.Data)
+	require.Equal(t, database.ErrNotExists, getResults[2].Err)
+
+	// revisions should be written together
+	revisions, err := ldb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+}
+
+func TestLevelDatabase_Transact(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+
+	// a failed precondition should roll back the other operations
+	_, err = ldb.Transact(ctx, []database.TxOp{
+		{
+			ObjectKey: database.ObjectKey{Type: "testdata", ID: "2"},
+			Op:        database.TxPut,
+			Data:      testData1,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1)),
+		},
+		{
+			ObjectKey: database.ObjectKey{Type: "testdata", ID: "1"},
+			Op:        database.TxPut,
+			Data:      testData2,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)),
+			Options:   database.PutOptions{ExpectedVersion: 2},
+		},
+	})
+	require.Equal(t, database.ErrConflict, errors.Cause(err))
+	exists, err := ldb.Exists(ctx, "testdata", "2")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	deleteSig := sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 2))
+	results, err := ldb.Transact(ctx, []database.TxOp{
+		{
+			ObjectKey: database.ObjectKey{Type: "testdata", ID: "2"},
+			Op:        database.TxPut,
+			Data:      testData1,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1)),
+		},
+		{
+			ObjectKey: database.ObjectKey{Type: "testdata", ID: "1"},
+			Op:        database.TxDelete,
+			Signature: deleteSig,
+			Options:   database.PutOptions{ExpectedVersion: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, results[0].Created)
+	require.Equal(t, uint64(2), results[1].Version)
+
+	_, err = ldb.Get(ctx, "testdata", "1")
+	require.Equal(t, database.ErrNotExists, err)
+	obj, err := ldb.Get(ctx, "testdata", "2")
+	require.NoError(t, err)
+	require.Equal(t, testData1, obj.Data)
+
+	// history of the deleted object should be erased
+	revisions, err := ldb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Nil(t, revisions[0].Data)
+}
+
+func TestLevelDatabase_TransferOwnership(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+	recipient, _ := crypto.GenerateKey()
+	to := crypto.PubkeyToAddress(recipient.PublicKey)
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+
+	transfer := &database.Transfer{To: to}
+	copy(transfer.NewOwner[:], crypto.CompressPubkey(&recipient.PublicKey))
+	sig := sign(t, priv, auth.GetTransferHash(auth.DefaultDomain, "testdata", "1", 2, to))
+	result, err := ldb.TransferOwnership(ctx, "testdata", "1", transfer, sig, database.PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result.Version)
+
+	// the signature cannot be replayed
+	_, err = ldb.TransferOwnership(ctx, "testdata", "1", transfer, sig, database.PutOptions{})
+	require.Equal(t, database.ErrNotAuthorized, err)
+
+	obj, err := ldb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, transfer.NewOwner, obj.Owner)
+
+	revisions, err := ldb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.NotNil(t, revisions[1].Transfer)
+	require.Equal(t, to, revisions[1].Transfer.To)
+}
+
+func TestLevelDatabase_SetACL(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	owner, _ := crypto.GenerateKey()
+	writer, _ := crypto.GenerateKey()
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+
+	acl := &database.ACL{Writers: []common.Address{crypto.PubkeyToAddress(writer.PublicKey)}}
+	sig := sign(t, owner, auth.GetACLHash(auth.DefaultDomain, "testdata", "1", 2, acl.Writers, acl.Readers))
+	_, err = ldb.SetACL(ctx, "testdata", "1", acl, sig, database.PutOptions{ExpectedVersion: 1})
+	require.NoError(t, err)
+
+	obj, err := ldb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, acl.Writers, obj.ACL.Writers)
+
+	// writers can update the object on behalf of the owner
+	result, err := ldb.Put(ctx, "testdata", "1", testData2, sign(t, writer, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 3, testData2)), database.PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), result.Version)
+}
+
+func TestLevelDatabase_History(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+	_, err = ldb.Put(ctx, "testdata", "1", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), database.PutOptions{})
+	require.NoError(t, err)
+	_, err = ldb.Put(ctx, "testdata", "2", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+
+	revisions, err := ldb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, uint64(1), revisions[0].Version)
+	require.Equal(t, testData1, revisions[0].Data)
+	require.Equal(t, uint64(2), revisions[1].Version)
+	require.Equal(t, testData2, revisions[1].Data)
+
+	// revisions should not be returned as objects
+	result, err := ldb.Query(ctx, "testdata", nil, database.QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Objects, 2)
+
+	_, err = ldb.History(ctx, "testdata", "3")
+	require.Equal(t, database.ErrNotExists, err)
+}
+
+func TestLevelDatabase_InvalidType(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+
+	// types with a slash would collide with the keys of other objects
+	_, err = ldb.Put(ctx, "testdata/1", "2", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata/1", "2", 1, testData1)), database.PutOptions{})
+	require.Equal(t, database.ErrInvalidType, err)
+
+	// revisions and schemas should not be readable as objects of types with the null byte
+	for _, typ := range []string{"\x00rev", "\x00rev/testdata/1", "\x00schema"} {
+		result, err := ldb.Query(ctx, typ, nil, database.QueryOptions{})
+		require.NoError(t, err)
+		require.Empty(t, result.Objects, typ)
+	}
+	_, err = ldb.Get(ctx, "\x00rev/testdata", "1")
+	require.Equal(t, database.ErrNotExists, err)
+	_, err = ldb.History(ctx, "\x00rev/testdata", "1")
+	require.Equal(t, database.ErrNotExists, err)
+}
+
+func TestLevelDatabase_Get(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sig, database.PutOptions{})
+	require.NoError(t, err)
+
+	obj, err := ldb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, testData1, obj.Data)
+	require.Equal(t, "testdata", obj.Type)
+	require.Equal(t, "1", obj.ID)
+}
+
+func TestLevelDatabase_Get_ErrorOnMissingKey(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	obj, err := ldb.Get(ctx, "testdata", "1")
+
+	require.Equal(t, database.ErrNotExists, err)
+	require.Nil(t, obj)
+}
+
+func TestLevelDatabase_Reopen(t *testing.T) {
+	ctx := context.TODO()
+	dataDir, err := ioutil.TempDir("", "airframe-leveldb")
+	require.NoError(t, err)
+	defer os.RemoveAll(dataDir)
+
+	ldb, err := New(dataDir)
+	require.NoError(t, err)
+	priv, _ := crypto.GenerateKey()
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+	_, err = ldb.Put(ctx, "testdata", "1", testData1, sig, database.PutOptions{})
+	require.NoError(t, err)
+	require.NoError(t, ldb.Close())
+
+	// objects should survive restarts
+	ldb, err = New(dataDir)
+	require.NoError(t, err)
+	defer ldb.Close()
+
+	obj, err := ldb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, testData1, obj.Data)
+	require.Equal(t, crypto.CompressPubkey(&priv.PublicKey), obj.Owner[:])
+}
+
+func TestLevelDatabase_Query(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sig, database.PutOptions{})
+	require.NoError(t, err)
+	sig = sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData2))
+	_, err = ldb.Put(ctx, "testdata", "2", testData2, sig, database.PutOptions{})
+	require.NoError(t, err)
+	sig = sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata2", "1", 1, testData1))
+	_, err = ldb.Put(ctx, "testdata2", "1", testData1, sig, database.PutOptions{})
+	require.NoError(t, err)
+
+	// test equals
+	q, err := database.QueryFromJson(`{"foo": "bar"}`)
+	require.NoError(t, err)
+	result, err := ldb.Query(ctx, "testdata", q, database.QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+
+	// test contains
+	q, err = database.QueryFromJson(`{"foo": {"contains": "b"}}`)
+	require.NoError(t, err)
+	result, err = ldb.Query(ctx, "testdata", q, database.QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(result.Objects))
+
+	// test skip
+	result, err = ldb.Query(ctx, "testdata", q, database.QueryOptions{Skip: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+
+	// test limit
+	result, err = ldb.Query(ctx, "testdata", q, database.QueryOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+}
+
+func TestLevelDatabase_Exists(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sig, database.PutOptions{})
+	require.NoError(t, err)
+
+	exists, err := ldb.Exists(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, true, exists)
+
+	exists, err = ldb.Exists(ctx, "testdata", "2")
+	require.NoError(t, err)
+	require.Equal(t, false, exists)
+}
+
+func TestLevelDatabase_Query_Cursor(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	for i, id := range []string{"3", "1", "4", "2", "5"} {
+		data := database.Payload{"n": float64(i)}
+		_, err := ldb.Put(ctx, "testdata", id, data, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", id, 1, data)), database.PutOptions{})
+		require.NoError(t, err)
+	}
+	q, err := database.QueryFromJson(`{}`)
+	require.NoError(t, err)
+
+	for sort, expected := range map[*database.Sort][]string{
+		nil:                            {"1", "2", "3", "4", "5"},
+		{Field: "n", Descending: true}: {"5", "2", "4", "1", "3"},
+	} {
+		var ids []string
+		opts := database.QueryOptions{Sort: sort, Limit: 2}
+		for {
+			result, err := ldb.Query(ctx, "testdata", q, opts)
+			require.NoError(t, err)
+			for _, obj := range result.Objects {
+				ids = append(ids, obj.ID)
+			}
+			if result.NextCursor == nil {
+				break
+			}
+			opts.Cursor = result.NextCursor
+		}
+		require.Equal(t, expected, ids)
+	}
+}
+
+func TestLevelDatabase_SetVisibility(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	owner, _ := crypto.GenerateKey()
+	ownerAddr := crypto.PubkeyToAddress(owner.PublicKey)
+
+	_, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{Private: true})
+	require.NoError(t, err)
+
+	result, err := ldb.Query(ctx, "testdata", nil, database.QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(result.Objects))
+	result, err = ldb.Query(ctx, "testdata", nil, database.QueryOptions{Reader: &ownerAddr})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+
+	sig := sign(t, owner, auth.GetVisibilityHash(auth.DefaultDomain, "testdata", "1", 2, false))
+	_, err = ldb.SetVisibility(ctx, "testdata", "1", false, sig, database.PutOptions{ExpectedVersion: 1})
+	require.NoError(t, err)
+
+	obj, err := ldb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.False(t, obj.Private)
+	result, err = ldb.Query(ctx, "testdata", nil, database.QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+}
+
+func TestLevelDatabase_SetSchema(t *testing.T) {
+	ctx := context.TODO()
+	admin, _ := crypto.GenerateKey()
+	ldb, teardown := newTestDatabase(t, database.WithAdmins(crypto.PubkeyToAddress(admin.PublicKey)))
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	for version, definition := range []string{
+		`{"properties": {"foo": {"type": "integer"}}}`,
+		`{"properties": {"foo": {"type": "string"}}}`,
+	} {
+		sig := sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "testdata", uint64(version+1), definition))
+		_, err := ldb.SetSchema(ctx, "testdata", definition, sig, database.PutOptions{})
+		require.NoError(t, err)
+	}
+	// a type sharing the prefix of the keys
+	definition := `{"required": ["bar"]}`
+	sig := sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "testdata/2", 1, definition))
+	_, err := ldb.SetSchema(ctx, "testdata/2", definition, sig, database.PutOptions{})
+	require.NoError(t, err)
+
+	schema, err := ldb.GetSchema(ctx, "testdata", 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), schema.Version)
+	schema, err = ldb.GetSchema(ctx, "testdata", 1)
+	require.NoError(t, err)
+	require.Equal(t, `{"properties": {"foo": {"type": "integer"}}}`, schema.Definition)
+
+	schemas, err := ldb.ListSchemas(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(schemas))
+	require.Equal(t, "testdata", schemas[0].Type)
+	require.Equal(t, uint64(2), schemas[0].Version)
+
+	// writes are validated against the latest schema
+	invalid := database.Payload{"foo": float64(1)}
+	_, err = ldb.Put(ctx, "testdata", "1", invalid, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, invalid)), database.PutOptions{})
+	require.IsType(t, &database.SchemaError{}, err)
+	_, err = ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+
+	// schemas are not queried as objects
+	result, err := ldb.Query(ctx, "testdata", nil, database.QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+}
+
+func TestLevelDatabase_Sweep(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+
+	put := func(version uint64, data database.Payload, expiresAt time.Time) {
+		sig := sign(t, priv, auth.GetExpiringObjectHash(auth.DefaultDomain, "testdata", "1", version, data, expiresAt.Unix()))
+		_, err := ldb.Put(ctx, "testdata", "1", data, sig, database.PutOptions{ExpiresAt: expiresAt})
+		require.NoError(t, err)
+	}
+	now := time.Now()
+	put(1, testData1, now.Add(time.Hour))
+
+	// the index entry of the previous expiry is left, but it does not sweep the object
+	put(2, testData2, now.Add(3*time.Hour))
+	swept, err := ldb.Sweep(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 0, swept)
+	obj, err := ldb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, testData2, obj.Data)
+
+	swept, err = ldb.Sweep(ctx, now.Add(4*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, swept)
+	version, err := ldb.Version(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), version)
+	revisions, err := ldb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	for _, rev := range revisions {
+		require.Nil(t, rev.Data)
+	}
+
+	// the swept object is created again from the next version
+	result, err := ldb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 3, testData1)), database.PutOptions{})
+	require.NoError(t, err)
+	require.True(t, result.Created)
+	require.Equal(t, uint64(3), result.Version)
+
+	swept, err = ldb.Sweep(ctx, now.Add(4*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 0, swept)
+}
+
+func TestLevelDatabase_Usage(t *testing.T) {
+	ctx := context.TODO()
+	admin, _ := crypto.GenerateKey()
+	ldb, teardown := newTestDatabase(t,
+		database.WithAdmins(crypto.PubkeyToAddress(admin.PublicKey)),
+		database.WithFeeModel(&database.LinearFeeModel{PerWrite: 10}),
+		database.WithQuotaEnforced(true))
+	defer teardown()
+	priv, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(priv.PublicKey)
+
+	sig := sign(t, admin, auth.GetDepositHash(auth.DefaultDomain, owner, 1, 25))
+	usage, err := ldb.Deposit(ctx, owner, 25, sig)
+	require.NoError(t, err)
+	require.Equal(t, uint64(25), usage.Balance())
+
+	// the items after the quota is exhausted fail
+	results, err := ldb.BatchPut(ctx, []database.PutItem{
+		{ObjectKey: database.ObjectKey{Type: "testdata", ID: "1"}, Data: testData1, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))},
+		{ObjectKey: database.ObjectKey{Type: "testdata", ID: "2"}, Data: testData1, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1))},
+		{ObjectKey: database.ObjectKey{Type: "testdata", ID: "3"}, Data: testData1, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "3", 1, testData1))},
+	})
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, uint64(10), results[0].Result.FeeUsed)
+	require.NoError(t, results[1].Err)
+	require.Equal(t, database.ErrQuotaExceeded, results[2].Err)
+
+	usage, err = ldb.Usage(ctx, owner)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), usage.Writes)
+	require.Equal(t, uint64(20), usage.FeeUsed)
+	require.Equal(t, uint64(1), usage.Deposits)
+}
+
+func TestLevelDatabase_Ping(t *testing.T) {
+	ctx := context.TODO()
+	ldb, teardown := newTestDatabase(t)
+	defer teardown()
+
+	require.NoError(t, ldb.Ping(ctx))
+	exists, err := ldb.Exists(ctx, "testdata", "deadbeef")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, ldb.Close())
+	require.Error(t, ldb.Ping(ctx))
+}
@@ -1,142 +1,439 @@
 package database
 
 import (
-	"bytes"
 	"context"
-	"github.com/airbloc/airframe/auth"
-	"github.com/pkg/errors"
-	"strings"
+	"github.com/klaytn/klaytn/common"
+	"sort"
+	"sync"
 	"time"
 )
 
+// InMemoryDatabase is safe for concurrent use.
+// Stored objects are never mutated in place, but replaced on every write,
+// so objects returned from Get and Query can be read without any locks.
 type InMemoryDatabase struct {
 	objects map[string]map[string]*Object
+
+	// history has revisions of each object, keyed by "<type>/<id>".
+	history map[string][]*Revision
+
+	// schemas has every version of the schema of each type.
+	schemas map[string][]*Schema
+
+	// usages has the charged fees and the deposits of each owner.
+	usages map[common.Address]*Usage
+
+	config *Config
+	lock   sync.RWMutex
 }
 
-func NewInMemoryDatabase() (Database, error) {
+func NewInMemoryDatabase(options ...Option) (*InMemoryDatabase, error) {
 	return &InMemoryDatabase{
+		config:  NewConfig(options...),
 		objects: make(map[string]map[string]*Object),
+		history: make(map[string][]*Revision),
+		schemas: make(map[string][]*Schema),
+		usages:  make(map[common.Address]*Usage),
 	}, nil
 }
 
 func (imdb *InMemoryDatabase) Get(ctx context.Context, typ, id string) (*Object, error) {
-	if objects, ok := imdb.objects[typ]; ok {
-		if obj, ok := objects[id]; ok {
-			return obj, nil
-		}
+	imdb.lock.RLock()
+	defer imdb.lock.RUnlock()
+
+	obj := imdb.get(typ, id)
+	if !IsLive(obj) {
+		return nil, ErrNotExists
 	}
-	return nil, ErrNotExists
+	return obj, nil
 }
 
 func (imdb *InMemoryDatabase) Exists(ctx context.Context, typ, id string) (bool, error) {
+	imdb.lock.RLock()
+	defer imdb.lock.RUnlock()
+
+	return IsLive(imdb.get(typ, id)), nil
+}
+
+func (imdb *InMemoryDatabase) Version(ctx context.Context, typ, id string) (uint64, error) {
+	imdb.lock.RLock()
+	defer imdb.lock.RUnlock()
+
+	if obj := imdb.get(typ, id); obj != nil {
+		return obj.Version, nil
+	}
+	return 0, nil
+}
+
+func (imdb *InMemoryDatabase) History(ctx context.Context, typ, id string) ([]*Revision, error) {
+	imdb.lock.RLock()
+	defer imdb.lock.RUnlock()
+
+	revisions, ok := imdb.history[typ+"/"+id]
+	if !ok {
+		return nil, ErrNotExists
+	}
+	return EraseExpired(append([]*Revision{}, revisions...)), nil
+}
+
+// get returns the stored object including tombstones, or nil if it has never existed.
+// The caller should hold the lock.
+func (imdb *InMemoryDatabase) get(typ, id string) *Object {
 	if objects, ok := imdb.objects[typ]; ok {
-		if _, ok := objects[id]; ok {
-			return true, nil
-		}
+		return objects[id]
 	}
-	return false, nil
+	return nil
 }
 
-func (imdb *InMemoryDatabase) Query(ctx context.Context, typ string, q *Query, skip, limit int) (results []*Object, err error) {
-	results = []*Object{}
+func (imdb *InMemoryDatabase) Query(ctx context.Context, typ string, q *Query, opts QueryOptions) (*QueryResult, error) {
+	imdb.lock.RLock()
+	objects := make([]*Object, 0, len(imdb.objects[typ]))
+	for _, obj := range imdb.objects[typ] {
+		objects = append(objects, obj)
+	}
+	imdb.lock.RUnlock()
 
-	objects := imdb.objects[typ]
-	if objects == nil {
-		return
+	if opts.Sort == nil {
+		// map iteration order is random, so objects should be fed in the order of IDs.
+		SortObjects(objects, nil)
 	}
-	skipped := 0
+
+	evaluator := NewEvaluator(q, opts)
 	for _, obj := range objects {
-		for _, op := range q.Conditions {
-			if compare(obj, op) {
-				if skipped == skip {
-					results = append(results, obj)
-				} else {
-					skipped++
-				}
-				if limit > 0 && len(results) == limit {
-					return
-				}
-			}
+		if !evaluator.Feed(obj) {
+			break
+		}
+	}
+	return evaluator.Result(), nil
+}
+
+func (imdb *InMemoryDatabase) Put(ctx context.Context, typ, id string, data Payload, signature []byte, opts PutOptions) (*PutResult, error) {
+	return imdb.update(typ, id, func(current *Object, schema *Schema) (*Write, error) {
+		opts.Schema = schema
+		return ApplyPut(ctx, imdb.config, current, typ, id, data, signature, opts)
+	})
+}
+
+// Patch applies given patch to the object atomically.
+func (imdb *InMemoryDatabase) Patch(ctx context.Context, typ, id string, patch *Patch, signature []byte, opts PutOptions) (*PutResult, error) {
+	return imdb.update(typ, id, func(current *Object, schema *Schema) (*Write, error) {
+		opts.Schema = schema
+		return ApplyPatch(ctx, imdb.config, current, patch, signature, opts)
+	})
+}
+
+// TransferOwnership moves the ownership of the object atomically.
+func (imdb *InMemoryDatabase) TransferOwnership(ctx context.Context, typ, id string, transfer *Transfer, signature []byte, opts PutOptions) (*PutResult, error) {
+	return imdb.update(typ, id, func(current *Object, _ *Schema) (*Write, error) {
+		return ApplyTransfer(ctx, imdb.config, current, transfer, signature, opts)
+	})
+}
+
+// SetACL replaces the access control list of the object atomically.
+func (imdb *InMemoryDatabase) SetACL(ctx context.Context, typ, id string, acl *ACL, signature []byte, opts PutOptions) (*PutResult, error) {
+	return imdb.update(typ, id, func(current *Object, _ *Schema) (*Write, error) {
+		return ApplyACL(ctx, imdb.config, current, acl, signature, opts)
+	})
+}
+
+// SetVisibility makes the object private or public atomically.
+func (imdb *InMemoryDatabase) SetVisibility(ctx context.Context, typ, id string, private bool, signature []byte, opts PutOptions) (*PutResult, error) {
+	return imdb.update(typ, id, func(current *Object, _ *Schema) (*Write, error) {
+		return ApplyVisibility(ctx, imdb.config, current, private, signature, opts)
+	})
+}
+
+// Delete removes the object if the signature is made by the owner.
+// Only a tombstone retaining the ID and the version is left, and the data of
+// the previous revisions are erased while their signatures are kept in the history.
+func (imdb *InMemoryDatabase) Delete(ctx context.Context, typ, id string, signature []byte) error {
+	_, err := imdb.update(typ, id, func(current *Object, _ *Schema) (*Write, error) {
+		return ApplyDelete(ctx, imdb.config, current, signature)
+	})
+	return err
+}
+
+// update commits the write which apply makes from the current object and the schema of its type.
+// Since apply recovers the signer, it is called without holding the lock so that writes of other
+// objects are not blocked by it. The write is committed only if neither the object nor the schema
+// has been replaced in the meantime, and apply is called again with the latest ones otherwise.
+func (imdb *InMemoryDatabase) update(typ, id string, apply func(current *Object, schema *Schema) (*Write, error)) (*PutResult, error) {
+	for {
+		imdb.lock.RLock()
+		current, schema := imdb.get(typ, id), imdb.schemaOf(typ)
+		imdb.lock.RUnlock()
+
+		w, err := apply(current, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		imdb.lock.Lock()
+		if imdb.get(typ, id) != current || imdb.schemaOf(typ) != schema {
+			imdb.lock.Unlock()
+			continue
+		}
+		err = imdb.commit(w)
+		imdb.lock.Unlock()
+		if err != nil {
+			return nil, err
 		}
+		return w.Result(), nil
+	}
+}
+
+func (imdb *InMemoryDatabase) BatchGet(ctx context.Context, keys []ObjectKey, includeDeleted bool) ([]*BatchGetResult, error) {
+	if err := ValidateBatch(keys); err != nil {
+		return nil, err
+	}
+	imdb.lock.RLock()
+	defer imdb.lock.RUnlock()
+
+	results := make([]*BatchGetResult, len(keys))
+	for i, key := range keys {
+		results[i] = NewBatchGetResult(imdb.get(key.Type, key.ID), includeDeleted)
+	}
+	return results, nil
+}
+
+// BatchPut writes each object like Put, so that a failed write does not affect the others.
+func (imdb *InMemoryDatabase) BatchPut(ctx context.Context, items []PutItem) ([]*BatchPutResult, error) {
+	if err := ValidateBatch(KeysOf(items)); err != nil {
+		return nil, err
+	}
+	results := make([]*BatchPutResult, len(items))
+	for i, item := range items {
+		result, err := imdb.Put(ctx, item.Type, item.ID, item.Data, item.Signature, item.Options)
+		if err != nil {
+			results[i] = &BatchPutResult{Err: err}
+			continue
+		}
+		results[i] = &BatchPutResult{Result: result}
+	}
+	return results, nil
+}
+
+// Transact commits given operations like update, applying them without holding the lock
+// and committing them only if none of the objects and their schemas have been replaced.
+func (imdb *InMemoryDatabase) Transact(ctx context.Context, ops []TxOp) ([]*PutResult, error) {
+	if err := ValidateTx(ops); err != nil {
+		return nil, err
 	}
-	return
-}
-
-func compare(obj *Object, op Operator) bool {
-	fieldVal := obj.Data[op.Field]
-	switch op.Type {
-	case OpEquals:
-		return fieldVal == op.Operand
-	case OpGreaterThan:
-		return fieldVal.(int) > op.Operand.(int)
-	case OpGreaterThanOrEqual:
-		return fieldVal.(int) >= op.Operand.(int)
-	case OpLessThan:
-		return fieldVal.(int) < op.Operand.(int)
-	case OpLessThanOrEqual:
-		return fieldVal.(int) <= op.Operand.(int)
-	case OpContains:
-		if fieldValStr, ok := fieldVal.(string); ok {
-			return strings.Contains(fieldValStr, op.Operand.(string))
-		}
-		if fieldValBytes, ok := fieldVal.([]byte); ok {
-			return bytes.Contains(fieldValBytes, op.Operand.([]byte))
-		}
-		for _, elem := range fieldVal.([]interface{}) {
-			if elem == op.Operand {
-				return true
+	ops = append([]TxOp{}, ops...)
+	current := make([]*Object, len(ops))
+	for {
+		imdb.lock.RLock()
+		for i, op := range ops {
+			current[i] = imdb.get(op.Type, op.ID)
+			ops[i].Options.Schema = imdb.schemaOf(op.Type)
+		}
+		imdb.lock.RUnlock()
+
+		writes, err := ApplyTx(ctx, imdb.config, current, ops)
+		if err != nil {
+			return nil, err
+		}
+
+		imdb.lock.Lock()
+		if imdb.replaced(current, ops) {
+			imdb.lock.Unlock()
+			continue
+		}
+		err = imdb.commit(writes...)
+		imdb.lock.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		results := make([]*PutResult, len(ops))
+		for i, w := range writes {
+			if w != nil {
+				results[i] = w.Result()
 			}
 		}
+		return results, nil
 	}
-	return false
 }
 
-func (imdb *InMemoryDatabase) Put(ctx context.Context, typ, id string, data Payload, signature []byte) (*PutResult, error) {
-	if strings.Contains(id, "/") {
-		return nil, ErrInvalidID
+// replaced returns whether any of the objects of given operations or their schemas are not
+// the same with the ones which the operations have been applied to. The caller should hold the lock.
+func (imdb *InMemoryDatabase) replaced(current []*Object, ops []TxOp) bool {
+	for i, op := range ops {
+		if imdb.get(op.Type, op.ID) != current[i] || imdb.schemaOf(op.Type) != op.Options.Schema {
+			return true
+		}
 	}
-	obj, err := imdb.Get(ctx, typ, id)
-	if err == ErrNotExists {
-		// create new
-		obj = &Object{
-			ID:   id,
-			Type: typ,
-			Data: data,
+	return false
+}
 
-			CreatedAt:     time.Now(),
-			LastUpdatedAt: time.Now(),
+// SetSchema verifies the signature of the admin without holding the lock like update.
+func (imdb *InMemoryDatabase) SetSchema(ctx context.Context, typ, definition string, signature []byte, opts PutOptions) (*Schema, error) {
+	for {
+		imdb.lock.RLock()
+		current := imdb.schemaOf(typ)
+		imdb.lock.RUnlock()
+
+		schema, err := ApplySchema(ctx, imdb.config, current, typ, definition, signature, opts)
+		if err != nil {
+			return nil, err
 		}
-		if obj.Owner, err = auth.GetSigner(typ, id, data, signature); err != nil {
-			return nil, errors.Wrap(err, "invalid signature")
+
+		imdb.lock.Lock()
+		if imdb.schemaOf(typ) != current {
+			imdb.lock.Unlock()
+			continue
 		}
-		if _, collectionExists := imdb.objects[typ]; !collectionExists {
-			imdb.objects[typ] = make(map[string]*Object)
+		imdb.schemas[typ] = append(imdb.schemas[typ], schema)
+		imdb.lock.Unlock()
+		return schema, nil
+	}
+}
+
+func (imdb *InMemoryDatabase) GetSchema(ctx context.Context, typ string, version uint64) (*Schema, error) {
+	imdb.lock.RLock()
+	defer imdb.lock.RUnlock()
+
+	schemas := imdb.schemas[typ]
+	if version == 0 {
+		version = uint64(len(schemas))
+	}
+	if version == 0 || version > uint64(len(schemas)) {
+		return nil, ErrNotExists
+	}
+	return schemas[version-1], nil
+}
+
+func (imdb *InMemoryDatabase) ListSchemas(ctx context.Context) ([]*Schema, error) {
+	imdb.lock.RLock()
+	defer imdb.lock.RUnlock()
+
+	schemas := make([]*Schema, 0, len(imdb.schemas))
+	for typ := range imdb.schemas {
+		schemas = append(schemas, imdb.schemaOf(typ))
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Type < schemas[j].Type
+	})
+	return schemas, nil
+}
+
+// schemaOf returns the latest schema of the type, or nil if none has been set.
+// The caller should hold the lock.
+func (imdb *InMemoryDatabase) schemaOf(typ string) *Schema {
+	if schemas := imdb.schemas[typ]; len(schemas) > 0 {
+		return schemas[len(schemas)-1]
+	}
+	return nil
+}
+
+// eraseHistory erases the data of the revisions while keeping their signatures.
+// The caller should hold the lock.
+func (imdb *InMemoryDatabase) eraseHistory(typ, id string) {
+	key := typ + "/" + id
+	for i, rev := range imdb.history[key] {
+		erased := *rev
+		erased.Data = nil
+		erased.Patch = nil
+		imdb.history[key][i] = &erased
+	}
+}
+
+// Sweep replaces the expired objects with tombstones while holding the lock.
+func (imdb *InMemoryDatabase) Sweep(ctx context.Context, now time.Time) (int, error) {
+	imdb.lock.Lock()
+	defer imdb.lock.Unlock()
+
+	swept := 0
+	for _, objects := range imdb.objects {
+		for id, obj := range objects {
+			if obj.Deleted || !obj.Expired(now) {
+				continue
+			}
+			imdb.eraseHistory(obj.Type, obj.ID)
+			objects[id] = Expire(obj)
+			swept++
 		}
-		imdb.objects[typ][id] = obj
-		return &PutResult{
-			FeeUsed: 0,
-			Created: true,
-		}, nil
+	}
+	return swept, nil
+}
+
+func (imdb *InMemoryDatabase) Usage(ctx context.Context, owner common.Address) (*Usage, error) {
+	imdb.lock.RLock()
+	defer imdb.lock.RUnlock()
+
+	return imdb.usageOf(owner), nil
+}
 
-	} else if obj != nil {
-		// update object
-		signer, err := auth.GetSigner(typ, id, data, signature)
+// Deposit verifies the signature of the admin without holding the lock like update.
+func (imdb *InMemoryDatabase) Deposit(ctx context.Context, owner common.Address, amount uint64, signature []byte) (*Usage, error) {
+	for {
+		imdb.lock.RLock()
+		stored, current := imdb.usages[owner], imdb.usageOf(owner)
+		imdb.lock.RUnlock()
+
+		usage, err := ApplyDeposit(ctx, imdb.config, current, amount, signature)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to recover signature")
+			return nil, err
+		}
+
+		imdb.lock.Lock()
+		if imdb.usages[owner] != stored {
+			imdb.lock.Unlock()
+			continue
 		}
+		imdb.usages[owner] = usage
+		imdb.lock.Unlock()
+		return usage, nil
+	}
+}
 
-		// object owners can only update the existing object.
-		if !bytes.Equal(signer[:], obj.Owner[:]) {
-			return nil, ErrNotAuthorized
+// usageOf returns the usage of the owner, which is empty if nothing has been charged or deposited.
+// The caller should hold the lock.
+func (imdb *InMemoryDatabase) usageOf(owner common.Address) *Usage {
+	if usage, ok := imdb.usages[owner]; ok {
+		return usage
+	}
+	return &Usage{Owner: owner}
+}
+
+// commit charges the fees of given writes to the payers, and stores the writes.
+// If any signer has exceeded the rate limit or any payer cannot afford the fees,
+// an error is returned without storing anything. The caller should hold the lock.
+func (imdb *InMemoryDatabase) commit(writes ...*Write) error {
+	if err := LimitWrites(imdb.config, writes...); err != nil {
+		return err
+	}
+	charges, err := ChargesOf(imdb.config, writes...)
+	if err != nil {
+		return err
+	}
+	usages := make([]*Usage, len(charges))
+	for i, c := range charges {
+		if usages[i], err = imdb.usageOf(c.Payer).Charge(imdb.config, c); err != nil {
+			return err
 		}
-		obj.Data = data
-		obj.LastUpdatedAt = time.Now()
+	}
+	for _, usage := range usages {
+		imdb.usages[usage.Owner] = usage
+	}
+	for _, w := range writes {
+		if w != nil {
+			imdb.store(w)
+		}
+	}
+	return nil
+}
 
-		return &PutResult{
-			FeeUsed: 0,
-			Created: false,
-		}, nil
-	} else {
-		return nil, errors.Wrap(err, "error while checking existence")
+// store applies given write to the objects and the history. The caller should hold the lock.
+func (imdb *InMemoryDatabase) store(w *Write) {
+	obj := w.Object
+	if w.EraseHistory {
+		imdb.eraseHistory(obj.Type, obj.ID)
+	}
+	if _, collectionExists := imdb.objects[obj.Type]; !collectionExists {
+		imdb.objects[obj.Type] = make(map[string]*Object)
 	}
+	imdb.objects[obj.Type][obj.ID] = obj
+
+	key := obj.Type + "/" + obj.ID
+	imdb.history[key] = append(imdb.history[key], w.Revision)
 }
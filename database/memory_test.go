@@ -3,49 +3,268 @@ package database
 import (
 	"context"
 	"crypto/ecdsa"
+	"fmt"
 	"github.com/airbloc/airframe/auth"
+	"github.com/airbloc/airframe/database/databasetest"
 	"github.com/klaytn/klaytn/crypto"
 	"github.com/stretchr/testify/require"
+	"sync"
 	"testing"
 )
 
+var sign = databasetest.Sign
+
 var (
 	testData1 = Payload{"foo": "bar"}
 	testData2 = Payload{"foo": "baz"}
 )
 
-func getSignature(priv *ecdsa.PrivateKey, typ, id string, data Payload) []byte {
-	// generate signature
-	hash := auth.GetObjectHash(typ, id, data)
-	sig, _ := crypto.Sign(hash[:], priv)
-	return sig
-}
-
 func TestInMemoryDatabase_Put(t *testing.T) {
 	ctx := context.TODO()
 	imdb, _ := NewInMemoryDatabase()
 	priv, _ := crypto.GenerateKey()
 
 	// test creation
-	sig := getSignature(priv, "testdata", "1", testData1)
-	result, err := imdb.Put(ctx, "testdata", "1", testData1, sig)
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+	result, err := imdb.Put(ctx, "testdata", "1", testData1, sig, PutOptions{})
 	require.NoError(t, err)
 	require.Equal(t, true, result.Created)
 
 	// test update
-	newSig := getSignature(priv, "testdata", "1", testData2)
-	result, err = imdb.Put(ctx, "testdata", "1", testData2, newSig)
+	newSig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2))
+	result, err = imdb.Put(ctx, "testdata", "1", testData2, newSig, PutOptions{})
 	require.NoError(t, err)
 	require.Equal(t, false, result.Created)
+	require.Equal(t, uint64(2), result.Version)
+}
+
+func TestInMemoryDatabase_Put_RejectReplay(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sig, PutOptions{})
+	require.NoError(t, err)
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), PutOptions{})
+	require.NoError(t, err)
+
+	// signature of the previous version should not roll back the object
+	_, err = imdb.Put(ctx, "testdata", "1", testData1, sig, PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	// signature of the current version should not be reused
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	// signature of another signing domain should not be accepted
+	anotherSig := sign(t, priv, auth.GetObjectHash("another", "testdata", "1", 3, testData1))
+	_, err = imdb.Put(ctx, "testdata", "1", testData1, anotherSig, PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, testData2, obj.Data)
+	require.Equal(t, uint64(2), obj.Version)
+
+	// backends of another signing domain recover the signer from it
+	anotherDB, _ := NewInMemoryDatabase(WithDomain("another"))
+	anotherSig = sign(t, priv, auth.GetObjectHash("another", "testdata", "1", 1, testData1))
+	_, err = anotherDB.Put(ctx, "testdata", "1", testData1, anotherSig, PutOptions{})
+	require.NoError(t, err)
+	obj, err = anotherDB.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, crypto.CompressPubkey(&priv.PublicKey), obj.Owner[:])
+}
+
+func TestInMemoryDatabase_Put_ReservedKeys(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	for _, key := range ReservedKeys {
+		data := Payload{"foo": "bar", key: 1}
+		_, err := imdb.Put(ctx, "testdata", "2", data, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, data)), PutOptions{})
+		require.Equal(t, ErrReservedKey, err, key)
+
+		// reserved keys cannot be added by patches either
+		patch := &Patch{Type: MergePatch, Document: []byte(fmt.Sprintf(`{"%s": 1}`, key))}
+		_, err = imdb.Patch(ctx, "testdata", "1", patch, sign(t, priv, auth.GetPatchHash(auth.DefaultDomain, "testdata", "1", 1, string(patch.Type), patch.Document)), PutOptions{})
+		require.Equal(t, ErrReservedKey, err, key)
+	}
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), obj.Version)
+}
+
+func TestInMemoryDatabase_Put_ExpectedVersion(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// another writer with the same key updates the object first
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData2)), PutOptions{ExpectedVersion: 1})
+	require.NoError(t, err)
+
+	_, err = imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData1)), PutOptions{ExpectedVersion: 1})
+	require.Equal(t, ErrConflict, err)
+
+	result, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 3, testData1)), PutOptions{ExpectedVersion: 2})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), result.Version)
+}
+
+// TestInMemoryDatabase_Concurrency should be run with the race detector (go test -race).
+func TestInMemoryDatabase_Concurrency(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	const numWriters = 20
+
+	// signatures are made beforehand, as the test cannot be failed from other goroutines
+	keys := make([]*ecdsa.PrivateKey, numWriters)
+	raceSigs := make([][]byte, numWriters)
+	createSigs := make([][]byte, numWriters)
+	updateSigs := make([][]byte, numWriters)
+	for i := 0; i < numWriters; i++ {
+		keys[i], _ = crypto.GenerateKey()
+		id := fmt.Sprintf("obj-%d", i)
+		raceSigs[i] = sign(t, keys[i], auth.GetObjectHash(auth.DefaultDomain, "testdata", "race", 1, testData1))
+		createSigs[i] = sign(t, keys[i], auth.GetObjectHash(auth.DefaultDomain, "testdata", id, 1, testData1))
+		updateSigs[i] = sign(t, keys[i], auth.GetObjectHash(auth.DefaultDomain, "testdata", id, 2, testData2))
+	}
+
+	var wg sync.WaitGroup
+	// the goroutines of each writer report at most 4 errors in total,
+	// which should never block so that failures do not hang the test
+	errs := make(chan error, 4*numWriters)
+	winners := make(chan int, numWriters)
+	for i := 0; i < numWriters; i++ {
+		wg.Add(3)
+
+		// race on creating the same object
+		go func(i int) {
+			defer wg.Done()
+			result, err := imdb.Put(ctx, "testdata", "race", testData1, raceSigs[i], PutOptions{})
+			if err == nil && result.Created {
+				winners <- i
+			} else if err != ErrNotAuthorized {
+				errs <- fmt.Errorf("unexpected result of racing creation: %v", err)
+			}
+		}(i)
+
+		// create and update distinct objects
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("obj-%d", i)
+			if _, err := imdb.Put(ctx, "testdata", id, testData1, createSigs[i], PutOptions{}); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := imdb.Put(ctx, "testdata", id, testData2, updateSigs[i], PutOptions{}); err != nil {
+				errs <- err
+			}
+		}(i)
+
+		// read concurrently with the writes
+		go func(i int) {
+			defer wg.Done()
+			if obj, err := imdb.Get(ctx, "testdata", fmt.Sprintf("obj-%d", i)); err == nil {
+				_ = obj.Data["foo"]
+			}
+			if _, err := imdb.Exists(ctx, "testdata", "race"); err != nil {
+				errs <- err
+			}
+			if _, err := imdb.Query(ctx, "testdata", nil, QueryOptions{Sort: &Sort{Field: "foo"}}); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	close(winners)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Len(t, winners, 1, "only one of the racing creations should succeed")
+
+	obj, err := imdb.Get(ctx, "testdata", "race")
+	require.NoError(t, err)
+	require.Equal(t, crypto.CompressPubkey(&keys[<-winners].PublicKey), obj.Owner[:])
+	require.Equal(t, uint64(1), obj.Version)
+
+	result, err := imdb.Query(ctx, "testdata", nil, QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Objects, numWriters+1)
+	for _, obj := range result.Objects {
+		if obj.ID != "race" {
+			require.Equal(t, testData2, obj.Data)
+			require.Equal(t, uint64(2), obj.Version)
+		}
+	}
+}
+
+func TestInMemoryDatabase_Delete(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sig, PutOptions{})
+	require.NoError(t, err)
+
+	// signature for writing should not be accepted for deletion
+	err = imdb.Delete(ctx, "testdata", "1", sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 2, testData1)))
+	require.Equal(t, ErrNotAuthorized, err)
+
+	// test deletion from non-owner
+	otherPriv, _ := crypto.GenerateKey()
+	err = imdb.Delete(ctx, "testdata", "1", sign(t, otherPriv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 2)))
+	require.Equal(t, ErrNotAuthorized, err)
+
+	err = imdb.Delete(ctx, "testdata", "1", sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 2)))
+	require.NoError(t, err)
+
+	_, err = imdb.Get(ctx, "testdata", "1")
+	require.Equal(t, ErrNotExists, err)
+	exists, err := imdb.Exists(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.False(t, exists)
+	result, err := imdb.Query(ctx, "testdata", nil, QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Objects, 0)
+
+	err = imdb.Delete(ctx, "testdata", "1", sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 3)))
+	require.Equal(t, ErrNotExists, err)
+
+	version, err := imdb.Version(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), version)
+
+	// the object can be re-created with the next version
+	putResult, err := imdb.Put(ctx, "testdata", "1", testData2, sign(t, otherPriv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 3, testData2)), PutOptions{})
+	require.NoError(t, err)
+	require.True(t, putResult.Created)
+	require.Equal(t, uint64(3), putResult.Version)
+
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, crypto.CompressPubkey(&otherPriv.PublicKey), obj.Owner[:])
 }
 
 func TestInMemoryDatabase_Get(t *testing.T) {
 	ctx := context.TODO()
 	imdb, _ := NewInMemoryDatabase()
 	priv, _ := crypto.GenerateKey()
-	sig := getSignature(priv, "testdata", "1", testData1)
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
 
-	_, err := imdb.Put(ctx, "testdata", "1", testData1, sig[:])
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sig[:], PutOptions{})
 	require.NoError(t, err)
 
 	obj, err := imdb.Get(ctx, "testdata", "1")
@@ -67,52 +286,162 @@ func TestInMemoryDatabase_Query(t *testing.T) {
 	imdb, _ := NewInMemoryDatabase()
 	priv, _ := crypto.GenerateKey()
 
-	sig := getSignature(priv, "testdata", "1", testData1)
-	_, err := imdb.Put(ctx, "testdata", "1", testData1, sig)
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sig, PutOptions{})
 	require.NoError(t, err)
-	sig = getSignature(priv, "testdata", "2", testData2)
-	_, err = imdb.Put(ctx, "testdata", "2", testData2, sig)
+	sig = sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData2))
+	_, err = imdb.Put(ctx, "testdata", "2", testData2, sig, PutOptions{})
 	require.NoError(t, err)
 
 	// test equals
 	q, err := QueryFromJson(`{"foo": "bar"}`)
 	require.NoError(t, err)
-	results, err := imdb.Query(ctx, "testdata", q, 0, 0)
+	result, err := imdb.Query(ctx, "testdata", q, QueryOptions{})
 	require.NoError(t, err)
-	require.Equal(t, 1, len(results))
+	require.Equal(t, 1, len(result.Objects))
 
 	// test contains
 	q, err = QueryFromJson(`{"foo": {"contains": "b"}}`)
 	require.NoError(t, err)
-	results, err = imdb.Query(ctx, "testdata", q, 0, 0)
+	result, err = imdb.Query(ctx, "testdata", q, QueryOptions{})
 	require.NoError(t, err)
-	require.Equal(t, 2, len(results))
+	require.Equal(t, 2, len(result.Objects))
+
+	// test and
+	q, err = QueryFromJson(`{"foo": {"contains": "b"}, "$not": {"foo": "baz"}}`)
+	require.NoError(t, err)
+	result, err = imdb.Query(ctx, "testdata", q, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+	require.Equal(t, testData1, result.Objects[0].Data)
+
+	// test empty query
+	q, err = QueryFromJson(`{}`)
+	require.NoError(t, err)
+	result, err = imdb.Query(ctx, "testdata", q, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(result.Objects))
+
+	// test or
+	q, err = QueryFromJson(`{"$or": [{"foo": "bar"}, {"foo": "baz"}]}`)
+	require.NoError(t, err)
+	result, err = imdb.Query(ctx, "testdata", q, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(result.Objects))
+
+	// test not
+	q, err = QueryFromJson(`{"$not": {"foo": "bar"}}`)
+	require.NoError(t, err)
+	result, err = imdb.Query(ctx, "testdata", q, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+	require.Equal(t, testData2, result.Objects[0].Data)
+
+	// test range query
+	q, err = QueryFromJson(`{"foo": {"gt": "bar"}}`)
+	require.NoError(t, err)
+	result, err = imdb.Query(ctx, "testdata", q, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(result.Objects))
+	require.Equal(t, testData2, result.Objects[0].Data)
+
+	q, err = QueryFromJson(`{"foo": {"lte": 3}}`)
+	require.NoError(t, err)
+	result, err = imdb.Query(ctx, "testdata", q, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(result.Objects))
 
 	// test skip
 	q, err = QueryFromJson(`{"foo": {"contains": "b"}}`)
 	require.NoError(t, err)
-	results, err = imdb.Query(ctx, "testdata", q, 1, 0)
+	result, err = imdb.Query(ctx, "testdata", q, QueryOptions{Skip: 1})
 	require.NoError(t, err)
-	require.Equal(t, 1, len(results))
+	require.Equal(t, 1, len(result.Objects))
 
 	// test limit
 	q, err = QueryFromJson(`{"foo": {"contains": "b"}}`)
 	require.NoError(t, err)
-	results, err = imdb.Query(ctx, "testdata", q, 0, 1)
+	result, err = imdb.Query(ctx, "testdata", q, QueryOptions{Limit: 1})
 	require.NoError(t, err)
-	require.Equal(t, 1, len(results))
+	require.Equal(t, 1, len(result.Objects))
 }
 
 func TestInMemoryDatabase_Exists(t *testing.T) {
 	ctx := context.TODO()
 	imdb, _ := NewInMemoryDatabase()
 	priv, _ := crypto.GenerateKey()
-	sig := getSignature(priv, "testdata", "1", testData1)
+	sig := sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1))
 
-	_, err := imdb.Put(ctx, "testdata", "1", testData1, sig[:])
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sig[:], PutOptions{})
 	require.NoError(t, err)
 
 	exists, err := imdb.Exists(ctx, "testdata", "1")
 	require.NoError(t, err)
 	require.Equal(t, true, exists)
 }
+
+func TestInMemoryDatabase_Query_Sort(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	for i, id := range []string{"3", "1", "4", "2", "5"} {
+		data := Payload{"n": float64(i)}
+		_, err := imdb.Put(ctx, "testdata", id, data, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", id, 1, data)), PutOptions{})
+		require.NoError(t, err)
+	}
+	q, err := QueryFromJson(`{}`)
+	require.NoError(t, err)
+
+	// results should be ordered by ID by default, so that pages do not overlap
+	var ids []string
+	for skip := 0; skip < 5; skip += 2 {
+		result, err := imdb.Query(ctx, "testdata", q, QueryOptions{Skip: skip, Limit: 2})
+		require.NoError(t, err)
+		for _, obj := range result.Objects {
+			ids = append(ids, obj.ID)
+		}
+	}
+	require.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+
+	// test sort by data field
+	result, err := imdb.Query(ctx, "testdata", q, QueryOptions{
+		Sort:  &Sort{Field: "n", Descending: true},
+		Limit: 3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "5", result.Objects[0].ID)
+	require.Equal(t, "2", result.Objects[1].ID)
+	require.Equal(t, "4", result.Objects[2].ID)
+}
+
+func TestInMemoryDatabase_Query_Cursor(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	for i, id := range []string{"3", "1", "4", "2", "5"} {
+		data := Payload{"n": float64(i % 2)}
+		_, err := imdb.Put(ctx, "testdata", id, data, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", id, 1, data)), PutOptions{})
+		require.NoError(t, err)
+	}
+	q, err := QueryFromJson(`{}`)
+	require.NoError(t, err)
+
+	for _, sort := range []*Sort{nil, {Field: "n"}, {Field: SortFieldCreatedAt, Descending: true}} {
+		var ids []string
+		opts := QueryOptions{Sort: sort, Limit: 2}
+		for {
+			result, err := imdb.Query(ctx, "testdata", q, opts)
+			require.NoError(t, err)
+			for _, obj := range result.Objects {
+				ids = append(ids, obj.ID)
+			}
+			if result.NextCursor == nil {
+				break
+			}
+			opts.Cursor = result.NextCursor
+		}
+		require.ElementsMatch(t, []string{"1", "2", "3", "4", "5"}, ids)
+	}
+}
@@ -0,0 +1,365 @@
+// package metrics implements a decorator of database backends,
+// which reports the operations to Prometheus.
+package metricsdatabase
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/airbloc/airframe/database"
+	"github.com/klaytn/klaytn/common"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"sync"
+	"time"
+)
+
+var (
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "airframe",
+		Subsystem: "database",
+		Name:      "operation_duration_seconds",
+		Help:      "Latencies of database operations. The count is the number of operations.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	operationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "airframe",
+		Subsystem: "database",
+		Name:      "errors_total",
+		Help:      "Failed database operations by the class of the error.",
+	}, []string{"operation", "class"})
+
+	objectsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "airframe",
+		Subsystem: "database",
+		Name:      "objects_created_total",
+		Help:      "Creations of objects by type. Types without schemas are counted as \"other\".",
+	}, []string{"type"})
+
+	objectsDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "airframe",
+		Subsystem: "database",
+		Name:      "objects_deleted_total",
+		Help:      "Deletions of objects by type. Types without schemas are counted as \"other\", and expired objects swept by the backend are not counted.",
+	}, []string{"type"})
+)
+
+func init() {
+	prometheus.MustRegister(operationDuration, operationErrors, objectsCreated, objectsDeleted)
+}
+
+// otherType is the label of the types without schemas.
+const otherType = "other"
+
+// minLoadBackoff and maxLoadBackoff bound how long the writes skip loading the types after
+// a failure, so that a failing backend is not listed by every write while holding the lock.
+const (
+	minLoadBackoff = time.Second
+	maxLoadBackoff = time.Minute
+)
+
+// MetricsDatabase reports the latencies and the errors of every operation of the backend,
+// and counts the creations and the deletions of objects by type. The counts are not the number
+// of the stored objects, since the expiry of objects is not counted.
+type MetricsDatabase struct {
+	db database.Database
+
+	// schemaTypes are the types with schemas, which are loaded on the first write. Since the types
+	// are chosen by the clients, only them are labeled to keep the number of series bounded.
+	schemaTypes     map[string]bool
+	schemaTypesLock sync.Mutex
+
+	// loadBackoff is how long the types are not loaded after loadFailedAt, which doubles on every failure.
+	loadFailedAt time.Time
+	loadBackoff  time.Duration
+}
+
+// New wraps given backend. The optional interfaces of the backend such as database.Batcher are
+// always implemented, and ErrNotSupported is returned if the backend does not implement them.
+// database.Sweeper is not forwarded, so it should be asserted on the backend itself.
+func New(db database.Database) *MetricsDatabase {
+	return &MetricsDatabase{db: db}
+}
+
+// typeLabel returns the label of given type, which is otherType unless the type has a schema.
+// Schemas set after loading the types are added only if they are set through the wrapper.
+func (m *MetricsDatabase) typeLabel(ctx context.Context, typ string) string {
+	registry, ok := m.db.(database.SchemaRegistry)
+	if !ok {
+		return otherType
+	}
+	m.schemaTypesLock.Lock()
+	defer m.schemaTypesLock.Unlock()
+
+	if m.schemaTypes == nil {
+		if time.Since(m.loadFailedAt) < m.loadBackoff {
+			return otherType
+		}
+		schemas, err := registry.ListSchemas(ctx)
+		if err != nil {
+			// the types are loaded again on a write after the backoff
+			m.loadFailedAt = time.Now()
+			m.loadBackoff *= 2
+			if m.loadBackoff < minLoadBackoff {
+				m.loadBackoff = minLoadBackoff
+			} else if m.loadBackoff > maxLoadBackoff {
+				m.loadBackoff = maxLoadBackoff
+			}
+			return otherType
+		}
+		m.schemaTypes = make(map[string]bool, len(schemas))
+		for _, schema := range schemas {
+			m.schemaTypes[schema.Type] = true
+		}
+	}
+	if !m.schemaTypes[typ] {
+		return otherType
+	}
+	return typ
+}
+
+// observe records the latency of the operation started at given time, and the class of its error.
+func observe(operation string, start time.Time, err error) {
+	operationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		operationErrors.WithLabelValues(operation, ErrorClassOf(err)).Inc()
+	}
+}
+
+// ErrorClassOf classifies errors of the backend into a few classes, to keep the number of series small.
+func ErrorClassOf(err error) string {
+	cause := errors.Cause(err)
+	switch cause.(type) {
+	case *database.RateLimitError:
+		return "rate_limited"
+	case *database.SchemaError:
+		return "invalid"
+	}
+	switch cause {
+	case database.ErrNotExists:
+		return "not_found"
+	case database.ErrNotSupported:
+		return "not_supported"
+	case database.ErrNotAuthorized:
+		return "not_authorized"
+	case database.ErrConflict:
+		return "conflict"
+	case database.ErrQuotaExceeded:
+		return "quota_exceeded"
+	case database.ErrInvalidID, database.ErrInvalidType, database.ErrInvalidExpiry, database.ErrReservedKey, database.ErrInvalidPatch,
+		database.ErrInvalidSchema, database.ErrInvalidRecipient, database.ErrInvalidCursor,
+		database.ErrACLTooLarge, database.ErrBatchTooLarge, database.ErrDuplicatedKey,
+		database.ErrTxTooLarge, database.ErrInvalidTxOp, auth.ErrInvalidSignature:
+		return "invalid"
+	}
+	return "internal"
+}
+
+func (m *MetricsDatabase) Get(ctx context.Context, typ, id string) (*database.Object, error) {
+	start := time.Now()
+	obj, err := m.db.Get(ctx, typ, id)
+	observe("get", start, err)
+	return obj, err
+}
+
+func (m *MetricsDatabase) Exists(ctx context.Context, typ, id string) (bool, error) {
+	start := time.Now()
+	exists, err := m.db.Exists(ctx, typ, id)
+	observe("exists", start, err)
+	return exists, err
+}
+
+func (m *MetricsDatabase) Query(ctx context.Context, typ string, query *database.Query, opts database.QueryOptions) (*database.QueryResult, error) {
+	start := time.Now()
+	result, err := m.db.Query(ctx, typ, query, opts)
+	observe("query", start, err)
+	return result, err
+}
+
+func (m *MetricsDatabase) Put(ctx context.Context, typ, id string, data database.Payload, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	start := time.Now()
+	result, err := m.db.Put(ctx, typ, id, data, signature, opts)
+	observe("put", start, err)
+	if err == nil && result.Created {
+		objectsCreated.WithLabelValues(m.typeLabel(ctx, typ)).Inc()
+	}
+	return result, err
+}
+
+func (m *MetricsDatabase) Patch(ctx context.Context, typ, id string, patch *database.Patch, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	start := time.Now()
+	result, err := m.db.Patch(ctx, typ, id, patch, signature, opts)
+	observe("patch", start, err)
+	return result, err
+}
+
+func (m *MetricsDatabase) Delete(ctx context.Context, typ, id string, signature []byte) error {
+	start := time.Now()
+	err := m.db.Delete(ctx, typ, id, signature)
+	observe("delete", start, err)
+	if err == nil {
+		objectsDeleted.WithLabelValues(m.typeLabel(ctx, typ)).Inc()
+	}
+	return err
+}
+
+func (m *MetricsDatabase) TransferOwnership(ctx context.Context, typ, id string, transfer *database.Transfer, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	start := time.Now()
+	result, err := m.db.TransferOwnership(ctx, typ, id, transfer, signature, opts)
+	observe("transfer_ownership", start, err)
+	return result, err
+}
+
+func (m *MetricsDatabase) SetACL(ctx context.Context, typ, id string, acl *database.ACL, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	start := time.Now()
+	result, err := m.db.SetACL(ctx, typ, id, acl, signature, opts)
+	observe("set_acl", start, err)
+	return result, err
+}
+
+func (m *MetricsDatabase) SetVisibility(ctx context.Context, typ, id string, private bool, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	start := time.Now()
+	result, err := m.db.SetVisibility(ctx, typ, id, private, signature, opts)
+	observe("set_visibility", start, err)
+	return result, err
+}
+
+func (m *MetricsDatabase) Version(ctx context.Context, typ, id string) (uint64, error) {
+	start := time.Now()
+	version, err := m.db.Version(ctx, typ, id)
+	observe("version", start, err)
+	return version, err
+}
+
+func (m *MetricsDatabase) History(ctx context.Context, typ, id string) ([]*database.Revision, error) {
+	start := time.Now()
+	revisions, err := m.db.History(ctx, typ, id)
+	observe("history", start, err)
+	return revisions, err
+}
+
+func (m *MetricsDatabase) BatchGet(ctx context.Context, keys []database.ObjectKey, includeDeleted bool) ([]*database.BatchGetResult, error) {
+	batcher, ok := m.db.(database.Batcher)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	start := time.Now()
+	results, err := batcher.BatchGet(ctx, keys, includeDeleted)
+	observe("batch_get", start, err)
+	return results, err
+}
+
+// BatchPut also counts the errors of each item, since the returned error is only for the whole batch.
+func (m *MetricsDatabase) BatchPut(ctx context.Context, items []database.PutItem) ([]*database.BatchPutResult, error) {
+	batcher, ok := m.db.(database.Batcher)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	start := time.Now()
+	results, err := batcher.BatchPut(ctx, items)
+	observe("batch_put", start, err)
+	for i, result := range results {
+		if result.Err != nil {
+			operationErrors.WithLabelValues("batch_put", ErrorClassOf(result.Err)).Inc()
+		} else if result.Result != nil && result.Result.Created {
+			objectsCreated.WithLabelValues(m.typeLabel(ctx, items[i].Type)).Inc()
+		}
+	}
+	return results, err
+}
+
+func (m *MetricsDatabase) Transact(ctx context.Context, ops []database.TxOp) ([]*database.PutResult, error) {
+	transactor, ok := m.db.(database.Transactor)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	start := time.Now()
+	results, err := transactor.Transact(ctx, ops)
+	observe("transact", start, err)
+	if err != nil {
+		return results, err
+	}
+	for i, op := range ops {
+		switch {
+		case op.Op == database.TxDelete:
+			objectsDeleted.WithLabelValues(m.typeLabel(ctx, op.Type)).Inc()
+		case op.Op == database.TxPut && results[i] != nil && results[i].Created:
+			objectsCreated.WithLabelValues(m.typeLabel(ctx, op.Type)).Inc()
+		}
+	}
+	return results, err
+}
+
+func (m *MetricsDatabase) SetSchema(ctx context.Context, typ, definition string, signature []byte, opts database.PutOptions) (*database.Schema, error) {
+	registry, ok := m.db.(database.SchemaRegistry)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	start := time.Now()
+	schema, err := registry.SetSchema(ctx, typ, definition, signature, opts)
+	observe("set_schema", start, err)
+	if err == nil {
+		m.schemaTypesLock.Lock()
+		if m.schemaTypes != nil {
+			m.schemaTypes[typ] = true
+		}
+		m.schemaTypesLock.Unlock()
+	}
+	return schema, err
+}
+
+func (m *MetricsDatabase) GetSchema(ctx context.Context, typ string, version uint64) (*database.Schema, error) {
+	registry, ok := m.db.(database.SchemaRegistry)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	start := time.Now()
+	schema, err := registry.GetSchema(ctx, typ, version)
+	observe("get_schema", start, err)
+	return schema, err
+}
+
+func (m *MetricsDatabase) ListSchemas(ctx context.Context) ([]*database.Schema, error) {
+	registry, ok := m.db.(database.SchemaRegistry)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	start := time.Now()
+	schemas, err := registry.ListSchemas(ctx)
+	observe("list_schemas", start, err)
+	return schemas, err
+}
+
+func (m *MetricsDatabase) Usage(ctx context.Context, owner common.Address) (*database.Usage, error) {
+	meter, ok := m.db.(database.Meter)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	start := time.Now()
+	usage, err := meter.Usage(ctx, owner)
+	observe("usage", start, err)
+	return usage, err
+}
+
+func (m *MetricsDatabase) Deposit(ctx context.Context, owner common.Address, amount uint64, signature []byte) (*database.Usage, error) {
+	meter, ok := m.db.(database.Meter)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	start := time.Now()
+	usage, err := meter.Deposit(ctx, owner, amount, signature)
+	observe("deposit", start, err)
+	return usage, err
+}
+
+// Ping succeeds if the backend is not a database.Pinger, which is always ready.
+func (m *MetricsDatabase) Ping(ctx context.Context) error {
+	pinger, ok := m.db.(database.Pinger)
+	if !ok {
+		return nil
+	}
+	start := time.Now()
+	err := pinger.Ping(ctx)
+	observe("ping", start, err)
+	return err
+}
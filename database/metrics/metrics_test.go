@@ -0,0 +1,121 @@
+package metricsdatabase
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/database/databasetest"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var sign = databasetest.Sign
+
+var testData = database.Payload{"foo": "bar"}
+
+func valueOf(t *testing.T, counter prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, counter.Write(m))
+	return m.GetCounter().GetValue()
+}
+
+func TestErrorClassOf(t *testing.T) {
+	require.Equal(t, "not_found", ErrorClassOf(database.ErrNotExists))
+	require.Equal(t, "conflict", ErrorClassOf(errors.Wrap(database.ErrConflict, "failed to put")))
+	require.Equal(t, "invalid", ErrorClassOf(&database.TxError{Index: 1, Err: database.ErrInvalidTxOp}))
+	require.Equal(t, "invalid", ErrorClassOf(&database.SchemaError{Type: "testdata"}))
+	require.Equal(t, "rate_limited", ErrorClassOf(&database.RateLimitError{}))
+	require.Equal(t, "internal", ErrorClassOf(errors.New("disk is full")))
+}
+
+func TestMetricsDatabase(t *testing.T) {
+	ctx := context.TODO()
+	admin, _ := crypto.GenerateKey()
+	imdb, _ := database.NewInMemoryDatabase(database.WithAdmins(crypto.PubkeyToAddress(admin.PublicKey)))
+	mdb := New(imdb)
+	priv, _ := crypto.GenerateKey()
+
+	definition := `{"type": "object"}`
+	_, err := mdb.SetSchema(ctx, "metricsdata", definition, sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "metricsdata", 1, definition)), database.PutOptions{})
+	require.NoError(t, err)
+
+	created := valueOf(t, objectsCreated.WithLabelValues("metricsdata"))
+	deleted := valueOf(t, objectsDeleted.WithLabelValues("metricsdata"))
+	others := valueOf(t, objectsCreated.WithLabelValues(otherType))
+	notFound := valueOf(t, operationErrors.WithLabelValues("get", "not_found"))
+
+	_, err = mdb.Put(ctx, "metricsdata", "1", testData, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "metricsdata", "1", 1, testData)), database.PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, created+1, valueOf(t, objectsCreated.WithLabelValues("metricsdata")))
+
+	// updates are not counted as creations
+	_, err = mdb.Put(ctx, "metricsdata", "1", testData, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "metricsdata", "1", 2, testData)), database.PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, created+1, valueOf(t, objectsCreated.WithLabelValues("metricsdata")))
+
+	require.NoError(t, mdb.Delete(ctx, "metricsdata", "1", sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "metricsdata", "1", 3))))
+	require.Equal(t, deleted+1, valueOf(t, objectsDeleted.WithLabelValues("metricsdata")))
+
+	_, err = mdb.Get(ctx, "metricsdata", "1")
+	require.Equal(t, database.ErrNotExists, err)
+	require.Equal(t, notFound+1, valueOf(t, operationErrors.WithLabelValues("get", "not_found")))
+
+	// types without schemas are not labeled, since they are chosen by the clients
+	_, err = mdb.Put(ctx, "randomdata", "1", testData, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "randomdata", "1", 1, testData)), database.PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, others+1, valueOf(t, objectsCreated.WithLabelValues(otherType)))
+
+	// schemas set after loading the types are labeled
+	_, err = mdb.SetSchema(ctx, "randomdata", definition, sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "randomdata", 1, definition)), database.PutOptions{})
+	require.NoError(t, err)
+	_, err = mdb.Put(ctx, "randomdata", "2", testData, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "randomdata", "2", 1, testData)), database.PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, float64(1), valueOf(t, objectsCreated.WithLabelValues("randomdata")))
+}
+
+// failingRegistry is a backend whose schemas cannot be listed.
+type failingRegistry struct {
+	*database.InMemoryDatabase
+	lists int
+}
+
+func (r *failingRegistry) ListSchemas(ctx context.Context) ([]*database.Schema, error) {
+	r.lists++
+	return nil, errors.New("connection refused")
+}
+
+func TestMetricsDatabase_LoadBackoff(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := database.NewInMemoryDatabase()
+	registry := &failingRegistry{InMemoryDatabase: imdb}
+	mdb := New(registry)
+	priv, _ := crypto.GenerateKey()
+
+	put := func(id string) {
+		_, err := mdb.Put(ctx, "backoffdata", id, testData, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "backoffdata", id, 1, testData)), database.PutOptions{})
+		require.NoError(t, err)
+	}
+
+	// the failure is cached, rather than listing the schemas on every write
+	put("1")
+	put("2")
+	require.Equal(t, 1, registry.lists)
+	require.Equal(t, minLoadBackoff, mdb.loadBackoff)
+
+	// the types are loaded again after the backoff, which doubles on every failure
+	mdb.loadFailedAt = mdb.loadFailedAt.Add(-minLoadBackoff)
+	put("3")
+	require.Equal(t, 2, registry.lists)
+	require.Equal(t, 2*minLoadBackoff, mdb.loadBackoff)
+
+	mdb.loadFailedAt = time.Now().Add(-time.Hour)
+	mdb.loadBackoff = maxLoadBackoff
+	put("4")
+	require.Equal(t, 3, registry.lists)
+	require.Equal(t, maxLoadBackoff, mdb.loadBackoff)
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+)
+
+// PatchType is a format of patch documents.
+type PatchType string
+
+const (
+	// MergePatch is a JSON Merge Patch document defined in RFC 7396.
+	MergePatch PatchType = "merge"
+
+	// JSONPatch is a JSON Patch document defined in RFC 6902.
+	JSONPatch PatchType = "json"
+)
+
+// ErrInvalidPatch is raised when given patch is malformed, or cannot be applied to the object.
+var ErrInvalidPatch = errors.New("invalid patch.")
+
+// Patch is a partial update of the object data.
+type Patch struct {
+	Type     PatchType
+	Document []byte
+}
+
+// Apply returns the data patched by the patch document.
+// The data is not modified, and the patched data should be still a JSON object.
+func (p *Patch) Apply(data Payload) (Payload, error) {
+	doc, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal data")
+	}
+
+	var patched []byte
+	switch p.Type {
+	case MergePatch:
+		patched, err = jsonpatch.MergePatch(doc, p.Document)
+	case JSONPatch:
+		ops, decodeErr := jsonpatch.DecodePatch(p.Document)
+		if decodeErr != nil {
+			return nil, errors.Wrap(ErrInvalidPatch, decodeErr.Error())
+		}
+		patched, err = ops.Apply(doc)
+	default:
+		return nil, errors.Wrapf(ErrInvalidPatch, "unknown patch type %s", p.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPatch, err.Error())
+	}
+
+	var result Payload
+	if err := json.Unmarshal(patched, &result); err != nil || result == nil {
+		return nil, errors.Wrap(ErrInvalidPatch, "data should remain a JSON object")
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPatch_Apply(t *testing.T) {
+	data := Payload{"foo": "bar", "nested": map[string]interface{}{"a": 1.0, "b": 2.0}}
+
+	patched, err := (&Patch{Type: MergePatch, Document: []byte(`{"foo": null, "nested": {"b": 3}, "new": true}`)}).Apply(data)
+	require.NoError(t, err)
+	require.Equal(t, Payload{"nested": map[string]interface{}{"a": 1.0, "b": 3.0}, "new": true}, patched)
+
+	patched, err = (&Patch{Type: JSONPatch, Document: []byte(`[
+		{"op": "replace", "path": "/foo", "value": "baz"},
+		{"op": "remove", "path": "/nested/a"}
+	]`)}).Apply(data)
+	require.NoError(t, err)
+	require.Equal(t, Payload{"foo": "baz", "nested": map[string]interface{}{"b": 2.0}}, patched)
+
+	// the original data should not be modified
+	require.Equal(t, "bar", data["foo"])
+}
+
+func TestPatch_Apply_Invalid(t *testing.T) {
+	data := Payload{"foo": "bar"}
+	invalidPatches := []*Patch{
+		{Type: "unknown", Document: []byte(`{}`)},
+		{Type: MergePatch, Document: []byte(`{`)},
+		{Type: MergePatch, Document: []byte(`"not an object"`)},
+		{Type: JSONPatch, Document: []byte(`{"op": "add"}`)},
+		{Type: JSONPatch, Document: []byte(`[{"op": "remove", "path": "/missing"}]`)},
+		{Type: JSONPatch, Document: []byte(`[{"op": "replace", "path": "", "value": 1}]`)},
+	}
+	for _, patch := range invalidPatches {
+		_, err := patch.Apply(data)
+		require.Equal(t, ErrInvalidPatch, errors.Cause(err), string(patch.Document))
+	}
+}
+
+func TestInMemoryDatabase_Patch(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	patch := &Patch{Type: MergePatch, Document: []byte(`{"foo": "baz", "count": 1}`)}
+	_, err := imdb.Patch(ctx, "testdata", "1", patch, sign(t, priv, auth.GetPatchHash(auth.DefaultDomain, "testdata", "1", 1, string(patch.Type), patch.Document)), PutOptions{})
+	require.Equal(t, ErrNotExists, err)
+
+	_, err = imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// test patch from non-owner
+	otherPriv, _ := crypto.GenerateKey()
+	_, err = imdb.Patch(ctx, "testdata", "1", patch, sign(t, otherPriv, auth.GetPatchHash(auth.DefaultDomain, "testdata", "1", 1, string(patch.Type), patch.Document)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	sig := sign(t, priv, auth.GetPatchHash(auth.DefaultDomain, "testdata", "1", 1, string(patch.Type), patch.Document))
+	result, err := imdb.Patch(ctx, "testdata", "1", patch, sig, PutOptions{ExpectedVersion: 1})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result.Version)
+
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, Payload{"foo": "baz", "count": 1.0}, obj.Data)
+
+	// the patch cannot be replayed on the next version
+	_, err = imdb.Patch(ctx, "testdata", "1", patch, sig, PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+	_, err = imdb.Patch(ctx, "testdata", "1", patch, sig, PutOptions{ExpectedVersion: 1})
+	require.Equal(t, ErrConflict, err)
+
+	revisions, err := imdb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, patch, revisions[1].Patch)
+	require.Equal(t, sig, revisions[1].Signature)
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"github.com/json-iterator/go"
 	"github.com/pkg/errors"
+	"strings"
 )
 
 type OperatorType int
@@ -42,23 +43,44 @@ const (
 	QueryOr
 )
 
+// Query is a tree of conditions. Conditions and Subqueries are combined
+// according to Type: all of them should match for QueryAnd, any of them for QueryOr,
+// and QueryNot matches only if the conjunction of them does not match.
 type Query struct {
 	Type       QueryType
 	Conditions []Operator
+	Subqueries []*Query
 }
 
 // queryFromJson parses JSON query into Query object.
+// Besides field conditions, logical operators can be used to compose queries:
+//
+//	{"$or": [{"gender": "Male"}, {"age": {"gt": 30}}]}
+//	{"$and": [{"age": {"gte": 20}}, {"age": {"lt": 30}}]}
+//	{"$not": {"name": {"contains": "Kim"}}}
 func QueryFromJson(rawQuery string) (*Query, error) {
 	q := make(map[string]interface{})
-	json.UnmarshalFromString(rawQuery, &q)
+	if err := json.UnmarshalFromString(rawQuery, &q); err != nil {
+		return nil, errors.Wrap(err, "query should be a JSON object")
+	}
+	return parseQuery(QueryAnd, q)
+}
 
+func parseQuery(typ QueryType, q map[string]interface{}) (*Query, error) {
 	query := &Query{
-		Type:       QueryAnd,
+		Type:       typ,
 		Conditions: []Operator{},
+		Subqueries: []*Query{},
 	}
-
-	// TODO: support or / not condition
 	for field, operator := range q {
+		if strings.HasPrefix(field, "$") {
+			subquery, err := parseLogicalOperator(field, operator)
+			if err != nil {
+				return nil, err
+			}
+			query.Subqueries = append(query.Subqueries, subquery)
+			continue
+		}
 		op := Operator{
 			Field: field,
 		}
@@ -74,11 +96,54 @@ func QueryFromJson(rawQuery string) (*Query, error) {
 			op.Type = OpEquals
 			op.Operand = operator
 		}
+		if err := validateOperand(op); err != nil {
+			return nil, err
+		}
 		query.Conditions = append(query.Conditions, op)
 	}
 	return query, nil
 }
 
+// parseLogicalOperator parses the operand of "$and", "$or" or "$not" into a subquery.
+func parseLogicalOperator(name string, operand interface{}) (*Query, error) {
+	switch name {
+	case "$and", "$or":
+		rawSubqueries, ok := operand.([]interface{})
+		if !ok || len(rawSubqueries) == 0 {
+			return nil, errors.Errorf("%s should be a non-empty array of queries", name)
+		}
+		typ := QueryType(QueryAnd)
+		if name == "$or" {
+			typ = QueryOr
+		}
+		query := &Query{
+			Type:       typ,
+			Conditions: []Operator{},
+			Subqueries: make([]*Query, len(rawSubqueries)),
+		}
+		for i, rawSubquery := range rawSubqueries {
+			q, ok := rawSubquery.(map[string]interface{})
+			if !ok || len(q) == 0 {
+				return nil, errors.Errorf("%s should be a non-empty array of queries", name)
+			}
+			subquery, err := parseQuery(QueryAnd, q)
+			if err != nil {
+				return nil, err
+			}
+			query.Subqueries[i] = subquery
+		}
+		return query, nil
+
+	case "$not":
+		q, ok := operand.(map[string]interface{})
+		if !ok || len(q) == 0 {
+			return nil, errors.New("$not should be a non-empty query")
+		}
+		return parseQuery(QueryNot, q)
+	}
+	return nil, errors.Errorf("unknown logical operator: %s", name)
+}
+
 func getFirstItem(m map[string]interface{}) (string, interface{}) {
 	for k, v := range m {
 		return k, v
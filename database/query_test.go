@@ -31,3 +31,54 @@ func TestQueryFromJson_Gte(t *testing.T) {
 	require.Equal(t, "age", q.Conditions[0].Field)
 	require.Equal(t, 20.0, q.Conditions[0].Operand)
 }
+
+func TestQueryFromJson_Or(t *testing.T) {
+	q, err := QueryFromJson(`{"$or": [{"gender": "Male"}, {"age": {"gt": 30}}]}`)
+	require.NoError(t, err)
+	require.Equal(t, QueryType(QueryAnd), q.Type)
+	require.Equal(t, 0, len(q.Conditions))
+	require.Equal(t, 1, len(q.Subqueries))
+
+	or := q.Subqueries[0]
+	require.Equal(t, QueryType(QueryOr), or.Type)
+	require.Equal(t, 2, len(or.Subqueries))
+	require.Equal(t, "gender", or.Subqueries[0].Conditions[0].Field)
+	require.Equal(t, OperatorType(OpGreaterThan), or.Subqueries[1].Conditions[0].Type)
+}
+
+func TestQueryFromJson_Not(t *testing.T) {
+	q, err := QueryFromJson(`{"age": {"gte": 20}, "$not": {"name": {"contains": "Kim"}}}`)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(q.Conditions))
+	require.Equal(t, 1, len(q.Subqueries))
+
+	not := q.Subqueries[0]
+	require.Equal(t, QueryType(QueryNot), not.Type)
+	require.Equal(t, 1, len(not.Conditions))
+	require.Equal(t, OperatorType(OpContains), not.Conditions[0].Type)
+}
+
+func TestQueryFromJson_Nested(t *testing.T) {
+	q, err := QueryFromJson(`{"$and": [{"$or": [{"a": 1}, {"b": 2}]}, {"$not": {"c": 3}}]}`)
+	require.NoError(t, err)
+
+	and := q.Subqueries[0]
+	require.Equal(t, QueryType(QueryAnd), and.Type)
+	require.Equal(t, 2, len(and.Subqueries))
+	require.Equal(t, QueryType(QueryOr), and.Subqueries[0].Subqueries[0].Type)
+	require.Equal(t, QueryType(QueryNot), and.Subqueries[1].Subqueries[0].Type)
+}
+
+func TestQueryFromJson_InvalidLogicalOperator(t *testing.T) {
+	for _, rawQuery := range []string{
+		`{"$or": {"a": 1}}`,
+		`{"$or": []}`,
+		`{"$and": [1, 2]}`,
+		`{"$not": [{"a": 1}]}`,
+		`{"$xor": [{"a": 1}]}`,
+		`[1, 2, 3]`,
+	} {
+		_, err := QueryFromJson(rawQuery)
+		require.Error(t, err, rawQuery)
+	}
+}
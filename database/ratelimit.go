@@ -0,0 +1,59 @@
+package database
+
+import (
+	"fmt"
+	"github.com/airbloc/airframe/auth"
+	"github.com/pkg/errors"
+	"math"
+	"time"
+)
+
+// RateLimiter limits the rate of requests by key. Take returns 0 if the request is allowed,
+// or how long the caller should wait for the next request otherwise.
+type RateLimiter interface {
+	Take(key string) time.Duration
+}
+
+// RateLimitError is raised when the signer of a write has exceeded the rate limit of writes.
+type RateLimitError struct {
+	// RetryAfter is how long the signer should wait for the next write.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("too many writes. retry after %d seconds.", RetryAfterSeconds(e.RetryAfter))
+}
+
+// RetryAfterSeconds rounds up given duration to seconds, which is at least 1.
+func RetryAfterSeconds(d time.Duration) int64 {
+	return int64(math.Max(1, math.Ceil(d.Seconds())))
+}
+
+// LimitWrites takes a request from the write limiter of the configuration for each signer of given writes,
+// which are committed at once. A *RateLimitError is returned if any signer has exceeded the limit.
+// Nil writes are skipped.
+func LimitWrites(config *Config, writes ...*Write) error {
+	limiter := config.WriteLimiter
+	if limiter == nil {
+		return nil
+	}
+
+	taken := make(map[string]bool)
+	for _, w := range writes {
+		if w == nil {
+			continue
+		}
+		signer, err := auth.AddressOf(w.Revision.Signer)
+		if err != nil {
+			return errors.Wrap(err, "invalid signer")
+		}
+		if taken[signer.Hex()] {
+			continue
+		}
+		taken[signer.Hex()] = true
+		if retryAfter := limiter.Take(signer.Hex()); retryAfter > 0 {
+			return &RateLimitError{RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
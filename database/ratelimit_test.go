@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// countingLimiter allows a number of requests for each key.
+type countingLimiter struct {
+	allowed int
+	taken   map[string]int
+}
+
+func (l *countingLimiter) Take(key string) time.Duration {
+	if l.taken[key] >= l.allowed {
+		return 1500 * time.Millisecond
+	}
+	l.taken[key]++
+	return 0
+}
+
+func TestInMemoryDatabase_WriteLimit(t *testing.T) {
+	ctx := context.TODO()
+	limiter := &countingLimiter{allowed: 2, taken: make(map[string]int)}
+	imdb, _ := NewInMemoryDatabase(WithWriteLimiter(limiter))
+	priv, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// a transaction takes a request for each signer
+	_, err = imdb.Transact(ctx, []TxOp{
+		{ObjectKey: ObjectKey{"testdata", "2"}, Op: TxPut, Data: testData1, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1))},
+		{ObjectKey: ObjectKey{"testdata", "3"}, Op: TxPut, Data: testData1, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "3", 1, testData1))},
+	})
+	require.NoError(t, err)
+
+	_, err = imdb.Put(ctx, "testdata", "4", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "4", 1, testData1)), PutOptions{})
+	limitErr, ok := err.(*RateLimitError)
+	require.True(t, ok)
+	require.Equal(t, 1500*time.Millisecond, limitErr.RetryAfter)
+	require.Equal(t, int64(2), RetryAfterSeconds(limitErr.RetryAfter))
+	exists, err := imdb.Exists(ctx, "testdata", "4")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// other signers are not limited
+	_, err = imdb.Put(ctx, "testdata", "4", testData1, sign(t, other, auth.GetObjectHash(auth.DefaultDomain, "testdata", "4", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+}
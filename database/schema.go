@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/common"
+	"github.com/pkg/errors"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidSchema is raised when a schema is not a valid JSON Schema, or refers to other documents.
+var ErrInvalidSchema = errors.New("invalid JSON Schema.")
+
+// Schema is a JSON Schema which the data of every object of the type should conform to.
+// Schemas are set by the admins, and versioned from 1 like objects.
+// Only writes after setting a schema are validated, and existing objects are left as they are.
+type Schema struct {
+	Type    string
+	Version uint64
+
+	// Definition is the draft-07 JSON Schema document. It can refer to the definitions in itself,
+	// but schemas referring to other documents are rejected.
+	Definition string
+
+	// Signer is the address of the admin who set the schema.
+	Signer    common.Address
+	CreatedAt time.Time
+}
+
+// SchemaError is raised when the data of a write violates the schema of the type.
+type SchemaError struct {
+	Type       string
+	Version    uint64
+	Violations []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("the data violates the schema of %s (version %d): %s",
+		e.Type, e.Version, strings.Join(e.Violations, "; "))
+}
+
+// schemaURL is the URL of the schema being compiled. References to other documents are not loaded.
+const schemaURL = "schema.json"
+
+// compileSchema compiles given draft-07 JSON Schema document.
+func compileSchema(definition string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft7
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, errors.Errorf("references to %s are not allowed", url)
+	}
+	if err := compiler.AddResource(schemaURL, strings.NewReader(definition)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaURL)
+}
+
+// schemaKey identifies a version of the schema of a type.
+type schemaKey struct {
+	Type    string
+	Version uint64
+}
+
+type compiledSchema struct {
+	definition string
+	schema     *jsonschema.Schema
+}
+
+// compiledSchemas caches the schemas compiled on validation by their type and version,
+// since backends load schemas on every write. Only schemas accepted by the backends are
+// validated with, so it grows only with the versions set by the admins.
+var compiledSchemas sync.Map
+
+// compiled returns the compiled schema, which is cached if it was compiled before.
+func (s *Schema) compiled() (*jsonschema.Schema, error) {
+	key := schemaKey{Type: s.Type, Version: s.Version}
+	if cached, ok := compiledSchemas.Load(key); ok && cached.(*compiledSchema).definition == s.Definition {
+		return cached.(*compiledSchema).schema, nil
+	}
+	compiled, err := compileSchema(s.Definition)
+	if err != nil {
+		return nil, err
+	}
+	compiledSchemas.Store(key, &compiledSchema{definition: s.Definition, schema: compiled})
+	return compiled, nil
+}
+
+// Validate returns a SchemaError if given data violates the schema.
+// A nil schema accepts any data.
+func (s *Schema) Validate(data Payload) error {
+	if s == nil {
+		return nil
+	}
+	compiled, err := s.compiled()
+	if err != nil {
+		return errors.Wrapf(err, "failed to compile schema of %s", s.Type)
+	}
+	err = compiled.Validate(map[string]interface{}(data))
+	if validationErr, ok := err.(*jsonschema.ValidationError); ok {
+		return &SchemaError{Type: s.Type, Version: s.Version, Violations: violationsOf(validationErr)}
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to validate data with schema of %s", s.Type)
+	}
+	return nil
+}
+
+// violationsOf returns the messages of the innermost causes of the validation error.
+func violationsOf(err *jsonschema.ValidationError) (violations []string) {
+	if len(err.Causes) == 0 {
+		location := err.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		return []string{location + ": " + err.Message}
+	}
+	for _, cause := range err.Causes {
+		violations = append(violations, violationsOf(cause)...)
+	}
+	return violations
+}
+
+// ApplySchema verifies that the schema is signed by one of the admins for the next version
+// of the schema, and returns the next version. The current schema is nil if none has been set.
+func ApplySchema(ctx context.Context, config *Config, current *Schema, typ, definition string, signature []byte, opts PutOptions) (*Schema, error) {
+	var currentVersion uint64
+	if current != nil {
+		currentVersion = current.Version
+	}
+	if err := opts.CheckVersion(currentVersion); err != nil {
+		return nil, err
+	}
+	version := currentVersion + 1
+	signer, err := auth.GetSchemaSigner(ctx, config.Domain, typ, version, definition, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signature")
+	}
+	addr, err := auth.AddressOf(signer)
+	if err != nil || !config.IsAdmin(addr) {
+		return nil, ErrNotAuthorized
+	}
+	// the schema is compiled only after the admin is verified, and is not cached until it is used
+	if _, err := compileSchema(definition); err != nil {
+		return nil, errors.Wrap(ErrInvalidSchema, err.Error())
+	}
+	return &Schema{
+		Type:       typ,
+		Version:    version,
+		Definition: definition,
+		Signer:     addr,
+		CreatedAt:  time.Now(),
+	}, nil
+}
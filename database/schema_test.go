@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// newTestAdmin returns the key of an admin, and the option to make it the admin of the backend.
+func newTestAdmin() (*ecdsa.PrivateKey, Option) {
+	admin, _ := crypto.GenerateKey()
+	return admin, WithAdmins(crypto.PubkeyToAddress(admin.PublicKey))
+}
+
+func TestInMemoryDatabase_SetSchema(t *testing.T) {
+	ctx := context.TODO()
+	admin, withAdmin := newTestAdmin()
+	imdb, _ := NewInMemoryDatabase(withAdmin)
+	stranger, _ := crypto.GenerateKey()
+
+	definition := `{"properties": {"foo": {"type": "string"}}, "required": ["foo"]}`
+
+	// only admins can set schemas, and schemas of others are not even compiled
+	_, err := imdb.SetSchema(ctx, "testdata", definition, sign(t, stranger, auth.GetSchemaHash(auth.DefaultDomain, "testdata", 1, definition)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+	_, err = imdb.SetSchema(ctx, "testdata", `{"type": 1}`, sign(t, stranger, auth.GetSchemaHash(auth.DefaultDomain, "testdata", 1, `{"type": 1}`)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	_, err = imdb.SetSchema(ctx, "testdata", `{"type": 1}`, sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "testdata", 1, `{"type": 1}`)), PutOptions{})
+	require.Equal(t, ErrInvalidSchema, errors.Cause(err))
+
+	schema, err := imdb.SetSchema(ctx, "testdata", definition, sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "testdata", 1, definition)), PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), schema.Version)
+	require.Equal(t, crypto.PubkeyToAddress(admin.PublicKey), schema.Signer)
+
+	// signatures for the previous version cannot be replayed
+	_, err = imdb.SetSchema(ctx, "testdata", definition, sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "testdata", 1, definition)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	updated := `{"properties": {"foo": {"type": "string", "maxLength": 3}}}`
+	_, err = imdb.SetSchema(ctx, "testdata", updated, sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "testdata", 2, updated)), PutOptions{ExpectedVersion: 2})
+	require.Equal(t, ErrConflict, err)
+	schema, err = imdb.SetSchema(ctx, "testdata", updated, sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "testdata", 2, updated)), PutOptions{ExpectedVersion: 1})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), schema.Version)
+
+	// every version is kept
+	schema, err = imdb.GetSchema(ctx, "testdata", 0)
+	require.NoError(t, err)
+	require.Equal(t, updated, schema.Definition)
+	schema, err = imdb.GetSchema(ctx, "testdata", 1)
+	require.NoError(t, err)
+	require.Equal(t, definition, schema.Definition)
+	_, err = imdb.GetSchema(ctx, "testdata", 3)
+	require.Equal(t, ErrNotExists, err)
+	_, err = imdb.GetSchema(ctx, "testdata2", 0)
+	require.Equal(t, ErrNotExists, err)
+
+	schemas, err := imdb.ListSchemas(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(schemas))
+	require.Equal(t, uint64(2), schemas[0].Version)
+}
+
+func TestInMemoryDatabase_Put_Schema(t *testing.T) {
+	ctx := context.TODO()
+	admin, withAdmin := newTestAdmin()
+	imdb, _ := NewInMemoryDatabase(withAdmin)
+	priv, _ := crypto.GenerateKey()
+
+	definition := `{"properties": {"foo": {"type": "string", "enum": ["bar", "baz"]}}, "additionalProperties": false}`
+	_, err := imdb.SetSchema(ctx, "testdata", definition, sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "testdata", 1, definition)), PutOptions{})
+	require.NoError(t, err)
+
+	invalid := Payload{"foo": "qux", "fooo": "bar"}
+	_, err = imdb.Put(ctx, "testdata", "1", invalid, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, invalid)), PutOptions{})
+	schemaErr, ok := err.(*SchemaError)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), schemaErr.Version)
+	require.Equal(t, 2, len(schemaErr.Violations))
+
+	// the data is validated before the signature
+	_, err = imdb.Put(ctx, "testdata", "1", invalid, nil, PutOptions{})
+	require.IsType(t, &SchemaError{}, err)
+
+	_, err = imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// other types are not validated
+	_, err = imdb.Put(ctx, "testdata2", "1", invalid, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata2", "1", 1, invalid)), PutOptions{})
+	require.NoError(t, err)
+
+	// patched data is also validated
+	patch := &Patch{Type: MergePatch, Document: []byte(`{"foo": 1}`)}
+	_, err = imdb.Patch(ctx, "testdata", "1", patch, sign(t, priv, auth.GetPatchHash(auth.DefaultDomain, "testdata", "1", 1, string(patch.Type), patch.Document)), PutOptions{})
+	require.IsType(t, &SchemaError{}, err)
+
+	results, err := imdb.BatchPut(ctx, []PutItem{
+		{ObjectKey: ObjectKey{Type: "testdata", ID: "2"}, Data: testData2, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData2))},
+		{ObjectKey: ObjectKey{Type: "testdata", ID: "3"}, Data: invalid, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "3", 1, invalid))},
+	})
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	require.IsType(t, &SchemaError{}, results[1].Err)
+
+	_, err = imdb.Transact(ctx, []TxOp{
+		{ObjectKey: ObjectKey{Type: "testdata", ID: "4"}, Op: TxPut, Data: invalid, Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "4", 1, invalid))},
+	})
+	require.IsType(t, &SchemaError{}, errors.Cause(err))
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema := &Schema{Type: "testdata", Version: 1, Definition: `{
+		"type": "object",
+		"definitions": {"name": {"type": "string", "minLength": 1}},
+		"properties": {
+			"name": {"$ref": "#/definitions/name"},
+			"age": {"type": "integer", "minimum": 0},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}
+		},
+		"required": ["name"],
+		"additionalProperties": false
+	}`}
+	require.NoError(t, schema.Validate(Payload{"name": "Kim", "age": float64(20), "tags": []interface{}{"a", "b"}}))
+
+	err := schema.Validate(Payload{"name": "", "age": 1.5, "tags": []interface{}{"a", "a"}, "nmae": "Kim"})
+	schemaErr, ok := err.(*SchemaError)
+	require.True(t, ok)
+	require.Equal(t, 4, len(schemaErr.Violations))
+
+	err = schema.Validate(Payload{})
+	require.IsType(t, &SchemaError{}, err)
+	require.Equal(t, 1, len(err.(*SchemaError).Violations))
+
+	// another definition of the same version, which is set in another backend
+	another := &Schema{Type: "testdata", Version: 1, Definition: `{"required": ["foo"]}`}
+	require.NoError(t, another.Validate(testData1))
+	require.IsType(t, &SchemaError{}, another.Validate(Payload{"name": "Kim"}))
+	require.NoError(t, schema.Validate(Payload{"name": "Kim"}))
+}
+
+func TestInMemoryDatabase_SetSchema_Invalid(t *testing.T) {
+	ctx := context.TODO()
+	admin, withAdmin := newTestAdmin()
+	imdb, _ := NewInMemoryDatabase(withAdmin)
+
+	for _, definition := range []string{
+		`{`,
+		`"object"`,
+		`{"type": "map"}`,
+		`{"required": "name"}`,
+		`{"pattern": "("}`,
+		`{"minLength": -1}`,
+		`{"properties": {"name": {"$ref": "#/definitions/name"}}}`,
+		`{"properties": {"name": {"$ref": "http://example.com/name.json"}}}`,
+	} {
+		_, err := imdb.SetSchema(ctx, "testdata", definition, sign(t, admin, auth.GetSchemaHash(auth.DefaultDomain, "testdata", 1, definition)), PutOptions{})
+		require.Equal(t, ErrInvalidSchema, errors.Cause(err), definition)
+	}
+}
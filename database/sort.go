@@ -0,0 +1,114 @@
+package database
+
+import (
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// SortFieldID, SortFieldCreatedAt and SortFieldLastUpdatedAt are reserved sort fields
+	// which refer to the ID and timestamps of objects, instead of fields in data.
+	SortFieldID            = "id"
+	SortFieldCreatedAt     = "createdAt"
+	SortFieldLastUpdatedAt = "lastUpdatedAt"
+)
+
+// Sort specifies the order of query results.
+// Objects with the same sort value are ordered by their IDs.
+type Sort struct {
+	Field      string
+	Descending bool
+}
+
+// SortFromString parses sort field and order ("asc" or "desc") into Sort.
+// A nil Sort is returned if the field is empty, which means the default order of the backend.
+func SortFromString(field, order string) (*Sort, error) {
+	if field == "" {
+		return nil, nil
+	}
+	switch strings.ToLower(order) {
+	case "", "asc":
+		return &Sort{Field: field}, nil
+	case "desc":
+		return &Sort{Field: field, Descending: true}, nil
+	}
+	return nil, errors.Errorf("unknown sort order: %s", order)
+}
+
+// Less returns true if object a should be placed before object b.
+func (s *Sort) Less(a, b *Object) bool {
+	c := compareSortValues(s.value(a), s.value(b))
+	if s.Descending {
+		c = -c
+	}
+	if c == 0 {
+		return a.ID < b.ID
+	}
+	return c < 0
+}
+
+func (s *Sort) value(obj *Object) interface{} {
+	switch s.Field {
+	case SortFieldID:
+		return obj.ID
+	case SortFieldCreatedAt:
+		return obj.CreatedAt
+	case SortFieldLastUpdatedAt:
+		return obj.LastUpdatedAt
+	}
+	return obj.Data[s.Field]
+}
+
+// SortObjects sorts given objects in place. Objects are sorted by ID if the sort is nil.
+func SortObjects(objects []*Object, s *Sort) {
+	if s == nil {
+		s = &Sort{Field: SortFieldID}
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return s.Less(objects[i], objects[j])
+	})
+}
+
+// compareSortValues defines a total order between values of any type.
+// Values of different kinds are ordered as: null < numbers < timestamps < strings < others.
+func compareSortValues(a, b interface{}) int {
+	rankA, rankB := sortRank(a), sortRank(b)
+	if rankA != rankB {
+		if rankA < rankB {
+			return -1
+		}
+		return 1
+	}
+	if x, ok := a.(time.Time); ok {
+		y := b.(time.Time)
+		switch {
+		case x.Before(y):
+			return -1
+		case x.After(y):
+			return 1
+		}
+		return 0
+	}
+	if c, ok := order(a, b); ok {
+		return c
+	}
+	return 0
+}
+
+func sortRank(v interface{}) int {
+	if v == nil {
+		return 0
+	}
+	if _, isNumber := toNumber(v); isNumber {
+		return 1
+	}
+	switch v.(type) {
+	case time.Time:
+		return 2
+	case string:
+		return 3
+	}
+	return 4
+}
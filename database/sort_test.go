@@ -0,0 +1,70 @@
+package database
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSortFromString(t *testing.T) {
+	s, err := SortFromString("", "")
+	require.NoError(t, err)
+	require.Nil(t, s)
+
+	s, err = SortFromString("age", "")
+	require.NoError(t, err)
+	require.Equal(t, &Sort{Field: "age"}, s)
+
+	s, err = SortFromString("age", "DESC")
+	require.NoError(t, err)
+	require.Equal(t, &Sort{Field: "age", Descending: true}, s)
+
+	_, err = SortFromString("age", "random")
+	require.Error(t, err)
+}
+
+func TestSortObjects(t *testing.T) {
+	now := time.Now()
+	objects := []*Object{
+		{ID: "a", Data: Payload{"age": 30.0}, CreatedAt: now.Add(2 * time.Second)},
+		{ID: "b", Data: Payload{"age": 20}, CreatedAt: now},
+		{ID: "c", Data: Payload{"age": "unknown"}, CreatedAt: now.Add(time.Second)},
+		{ID: "d", Data: Payload{}, CreatedAt: now},
+		{ID: "e", Data: Payload{"age": 20.0}, CreatedAt: now},
+	}
+	ids := func() (ids []string) {
+		for _, obj := range objects {
+			ids = append(ids, obj.ID)
+		}
+		return
+	}
+
+	SortObjects(objects, &Sort{Field: "age"})
+	require.Equal(t, []string{"d", "b", "e", "a", "c"}, ids())
+
+	SortObjects(objects, &Sort{Field: "age", Descending: true})
+	require.Equal(t, []string{"c", "a", "b", "e", "d"}, ids())
+
+	SortObjects(objects, &Sort{Field: SortFieldCreatedAt, Descending: true})
+	require.Equal(t, []string{"a", "c", "b", "d", "e"}, ids())
+
+	SortObjects(objects, nil)
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, ids())
+}
+
+func TestEvaluator_Sort(t *testing.T) {
+	evaluator := NewEvaluator(nil, QueryOptions{
+		Sort:  &Sort{Field: "n", Descending: true},
+		Skip:  1,
+		Limit: 2,
+	})
+	for i, id := range []string{"1", "2", "3", "4"} {
+		require.True(t, evaluator.Feed(&Object{ID: id, Data: Payload{"n": i}}))
+	}
+
+	result := evaluator.Result()
+	require.Equal(t, 2, len(result.Objects))
+	require.Equal(t, "3", result.Objects[0].ID)
+	require.Equal(t, "2", result.Objects[1].ID)
+	require.Equal(t, 1, result.NextCursor.SortValue)
+}
@@ -0,0 +1,224 @@
+// package tracing implements a decorator of database backends,
+// which starts a span of every operation.
+package tracingdatabase
+
+import (
+	"context"
+	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/tracing"
+	"github.com/klaytn/klaytn/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("database")
+
+// TracingDatabase starts a span of every operation of the backend as a child of the span in the context,
+// and passes the context of the span to the backend so that its own spans are nested.
+type TracingDatabase struct {
+	db database.Database
+}
+
+// New wraps given backend. The optional interfaces of the backend such as database.Batcher are
+// always implemented, and ErrNotSupported is returned if the backend does not implement them.
+// database.Sweeper is not forwarded, so it should be asserted on the backend itself.
+func New(db database.Database) *TracingDatabase {
+	return &TracingDatabase{db: db}
+}
+
+// start starts a span of the operation on the object.
+func start(ctx context.Context, operation, typ, id string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{attribute.String("db.operation.name", operation)}
+	if typ != "" {
+		attrs = append(attrs, attribute.String("airframe.object.type", typ))
+	}
+	if id != "" {
+		attrs = append(attrs, attribute.String("airframe.object.id", id))
+	}
+	return tracer.Start(ctx, "database."+operation, trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(attrs...))
+}
+
+func (t *TracingDatabase) Get(ctx context.Context, typ, id string) (*database.Object, error) {
+	ctx, span := start(ctx, "Get", typ, id)
+	obj, err := t.db.Get(ctx, typ, id)
+	tracing.EndSpan(span, err)
+	return obj, err
+}
+
+func (t *TracingDatabase) Exists(ctx context.Context, typ, id string) (bool, error) {
+	ctx, span := start(ctx, "Exists", typ, id)
+	exists, err := t.db.Exists(ctx, typ, id)
+	tracing.EndSpan(span, err)
+	return exists, err
+}
+
+func (t *TracingDatabase) Query(ctx context.Context, typ string, query *database.Query, opts database.QueryOptions) (*database.QueryResult, error) {
+	ctx, span := start(ctx, "Query", typ, "")
+	result, err := t.db.Query(ctx, typ, query, opts)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (t *TracingDatabase) Put(ctx context.Context, typ, id string, data database.Payload, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	ctx, span := start(ctx, "Put", typ, id)
+	result, err := t.db.Put(ctx, typ, id, data, signature, opts)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (t *TracingDatabase) Patch(ctx context.Context, typ, id string, patch *database.Patch, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	ctx, span := start(ctx, "Patch", typ, id)
+	result, err := t.db.Patch(ctx, typ, id, patch, signature, opts)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (t *TracingDatabase) Delete(ctx context.Context, typ, id string, signature []byte) error {
+	ctx, span := start(ctx, "Delete", typ, id)
+	err := t.db.Delete(ctx, typ, id, signature)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+func (t *TracingDatabase) TransferOwnership(ctx context.Context, typ, id string, transfer *database.Transfer, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	ctx, span := start(ctx, "TransferOwnership", typ, id)
+	result, err := t.db.TransferOwnership(ctx, typ, id, transfer, signature, opts)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (t *TracingDatabase) SetACL(ctx context.Context, typ, id string, acl *database.ACL, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	ctx, span := start(ctx, "SetACL", typ, id)
+	result, err := t.db.SetACL(ctx, typ, id, acl, signature, opts)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (t *TracingDatabase) SetVisibility(ctx context.Context, typ, id string, private bool, signature []byte, opts database.PutOptions) (*database.PutResult, error) {
+	ctx, span := start(ctx, "SetVisibility", typ, id)
+	result, err := t.db.SetVisibility(ctx, typ, id, private, signature, opts)
+	tracing.EndSpan(span, err)
+	return result, err
+}
+
+func (t *TracingDatabase) Version(ctx context.Context, typ, id string) (uint64, error) {
+	ctx, span := start(ctx, "Version", typ, id)
+	version, err := t.db.Version(ctx, typ, id)
+	tracing.EndSpan(span, err)
+	return version, err
+}
+
+func (t *TracingDatabase) History(ctx context.Context, typ, id string) ([]*database.Revision, error) {
+	ctx, span := start(ctx, "History", typ, id)
+	revisions, err := t.db.History(ctx, typ, id)
+	tracing.EndSpan(span, err)
+	return revisions, err
+}
+
+func (t *TracingDatabase) BatchGet(ctx context.Context, keys []database.ObjectKey, includeDeleted bool) ([]*database.BatchGetResult, error) {
+	batcher, ok := t.db.(database.Batcher)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	ctx, span := start(ctx, "BatchGet", "", "")
+	results, err := batcher.BatchGet(ctx, keys, includeDeleted)
+	span.SetAttributes(attribute.Int("airframe.batch.size", len(keys)))
+	tracing.EndSpan(span, err)
+	return results, err
+}
+
+func (t *TracingDatabase) BatchPut(ctx context.Context, items []database.PutItem) ([]*database.BatchPutResult, error) {
+	batcher, ok := t.db.(database.Batcher)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	ctx, span := start(ctx, "BatchPut", "", "")
+	results, err := batcher.BatchPut(ctx, items)
+	span.SetAttributes(attribute.Int("airframe.batch.size", len(items)))
+	tracing.EndSpan(span, err)
+	return results, err
+}
+
+func (t *TracingDatabase) Transact(ctx context.Context, ops []database.TxOp) ([]*database.PutResult, error) {
+	transactor, ok := t.db.(database.Transactor)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	ctx, span := start(ctx, "Transact", "", "")
+	results, err := transactor.Transact(ctx, ops)
+	span.SetAttributes(attribute.Int("airframe.tx.size", len(ops)))
+	tracing.EndSpan(span, err)
+	return results, err
+}
+
+func (t *TracingDatabase) SetSchema(ctx context.Context, typ, definition string, signature []byte, opts database.PutOptions) (*database.Schema, error) {
+	registry, ok := t.db.(database.SchemaRegistry)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	ctx, span := start(ctx, "SetSchema", typ, "")
+	schema, err := registry.SetSchema(ctx, typ, definition, signature, opts)
+	tracing.EndSpan(span, err)
+	return schema, err
+}
+
+func (t *TracingDatabase) GetSchema(ctx context.Context, typ string, version uint64) (*database.Schema, error) {
+	registry, ok := t.db.(database.SchemaRegistry)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	ctx, span := start(ctx, "GetSchema", typ, "")
+	schema, err := registry.GetSchema(ctx, typ, version)
+	tracing.EndSpan(span, err)
+	return schema, err
+}
+
+func (t *TracingDatabase) ListSchemas(ctx context.Context) ([]*database.Schema, error) {
+	registry, ok := t.db.(database.SchemaRegistry)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	ctx, span := start(ctx, "ListSchemas", "", "")
+	schemas, err := registry.ListSchemas(ctx)
+	tracing.EndSpan(span, err)
+	return schemas, err
+}
+
+func (t *TracingDatabase) Usage(ctx context.Context, owner common.Address) (*database.Usage, error) {
+	meter, ok := t.db.(database.Meter)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	ctx, span := start(ctx, "Usage", "", "")
+	usage, err := meter.Usage(ctx, owner)
+	tracing.EndSpan(span, err)
+	return usage, err
+}
+
+func (t *TracingDatabase) Deposit(ctx context.Context, owner common.Address, amount uint64, signature []byte) (*database.Usage, error) {
+	meter, ok := t.db.(database.Meter)
+	if !ok {
+		return nil, database.ErrNotSupported
+	}
+	ctx, span := start(ctx, "Deposit", "", "")
+	usage, err := meter.Deposit(ctx, owner, amount, signature)
+	tracing.EndSpan(span, err)
+	return usage, err
+}
+
+// Ping is traced only in the span of a request such as a readiness probe,
+// since the backend is pinged periodically by the health watcher.
+// It succeeds if the backend is not a database.Pinger, which is always ready.
+func (t *TracingDatabase) Ping(ctx context.Context) error {
+	pinger, ok := t.db.(database.Pinger)
+	if !ok {
+		return nil
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return pinger.Ping(ctx)
+	}
+	ctx, span := start(ctx, "Ping", "", "")
+	err := pinger.Ping(ctx)
+	tracing.EndSpan(span, err)
+	return err
+}
@@ -0,0 +1,70 @@
+package tracingdatabase
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/database/databasetest"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+var sign = databasetest.Sign
+
+var testData = database.Payload{"foo": "bar"}
+
+// pingedDatabase is an in-memory database which can be pinged like the backends of external services.
+type pingedDatabase struct {
+	*database.InMemoryDatabase
+}
+
+func (pingedDatabase) Ping(ctx context.Context) error {
+	return nil
+}
+
+func TestTracingDatabase_Put(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	imdb, _ := database.NewInMemoryDatabase()
+	tdb := New(pingedDatabase{imdb})
+	priv, _ := crypto.GenerateKey()
+
+	ctx, request := otel.Tracer("test").Start(context.TODO(), "request")
+	_, err := tdb.Put(ctx, "testdata", "1", testData, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData)), database.PutOptions{})
+	require.NoError(t, err)
+	request.End()
+
+	// the signature is recovered in the span of the operation, which is in the span of the request
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	require.Contains(t, spans, "database.Put")
+	require.Contains(t, spans, "auth.recoverSigner")
+	require.Equal(t, spans["request"].SpanContext().SpanID(), spans["database.Put"].Parent().SpanID())
+	require.Equal(t, spans["database.Put"].SpanContext().SpanID(), spans["auth.recoverSigner"].Parent().SpanID())
+
+	// the health watcher does not make traces
+	require.NoError(t, tdb.Ping(context.TODO()))
+	require.Len(t, recorder.Ended(), 3)
+}
+
+func TestTracingDatabase_NotSupported(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := database.NewInMemoryDatabase()
+
+	// the backend only implements database.Database
+	tdb := New(struct{ database.Database }{imdb})
+	_, err := tdb.BatchGet(ctx, []database.ObjectKey{{Type: "testdata", ID: "1"}}, false)
+	require.Equal(t, database.ErrNotSupported, err)
+	_, err = tdb.ListSchemas(ctx)
+	require.Equal(t, database.ErrNotSupported, err)
+
+	// backends which cannot be pinged are always ready
+	require.NoError(t, tdb.Ping(ctx))
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/common"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// ErrInvalidRecipient is raised when the new owner of a transfer mismatches with the signed address,
+// or the new owner's public key is not given without the recipient's signature.
+var ErrInvalidRecipient = errors.New("the new owner mismatches with the signed recipient.")
+
+// Transfer moves the ownership of an object to a new owner. It is signed by the current owner
+// for the address of the new owner, and optionally counter-signed by the new owner.
+type Transfer struct {
+	// To is the address of the new owner.
+	To common.Address
+
+	// NewOwner is the public key of the new owner. It can be omitted if RecipientSignature
+	// is given, since the key is recovered from the signature.
+	NewOwner auth.PublicKey
+
+	// RecipientSignature is the signature of the new owner accepting the transfer.
+	RecipientSignature []byte `dynamo:",omitempty"`
+}
+
+// ApplyTransfer verifies that the transfer is signed by the owner for the next version of
+// the object, and returns the write of the object owned by the new owner.
+// The new owner of the transfer is filled with the recovered public key.
+func ApplyTransfer(ctx context.Context, config *Config, current *Object, transfer *Transfer, signature []byte, opts PutOptions) (*Write, error) {
+	if !IsLive(current) {
+		return nil, ErrNotExists
+	}
+	if err := opts.CheckVersion(current.Version); err != nil {
+		return nil, err
+	}
+	version := current.Version + 1
+	signer, err := auth.GetTransferSigner(ctx, config.Domain, current.Type, current.ID, version, transfer.To, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signature")
+	}
+	if !bytes.Equal(signer[:], current.Owner[:]) {
+		return nil, ErrNotAuthorized
+	}
+
+	t := *transfer
+	if len(t.RecipientSignature) > 0 {
+		recipient, err := auth.GetAcceptTransferSigner(ctx, config.Domain, current.Type, current.ID, version, t.To, t.RecipientSignature)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to recover recipient signature")
+		}
+		if t.NewOwner != (auth.PublicKey{}) && t.NewOwner != recipient {
+			return nil, ErrInvalidRecipient
+		}
+		t.NewOwner = recipient
+	}
+	if addr, err := auth.AddressOf(t.NewOwner); err != nil || addr != t.To {
+		return nil, ErrInvalidRecipient
+	}
+
+	// permissions granted by the previous owner are revoked
+	transferred := *current
+	transferred.Owner = t.NewOwner
+	transferred.ACL = nil
+	transferred.Version = version
+	transferred.LastUpdatedAt = time.Now()
+
+	rev := NewRevision(&transferred, signer, signature)
+	rev.Transfer = &t
+	return &Write{
+		Object:   &transferred,
+		Revision: rev,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func publicKeyOf(priv *ecdsa.PrivateKey) (key auth.PublicKey) {
+	copy(key[:], crypto.CompressPubkey(&priv.PublicKey))
+	return
+}
+
+func TestInMemoryDatabase_TransferOwnership(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+	recipient, _ := crypto.GenerateKey()
+	to := crypto.PubkeyToAddress(recipient.PublicKey)
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// only the owner can transfer the object
+	transfer := &Transfer{To: to, NewOwner: publicKeyOf(recipient)}
+	_, err = imdb.TransferOwnership(ctx, "testdata", "1", transfer, sign(t, recipient, auth.GetTransferHash(auth.DefaultDomain, "testdata", "1", 2, to)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+
+	// the public key should match with the signed address
+	other, _ := crypto.GenerateKey()
+	wrongTransfer := &Transfer{To: to, NewOwner: publicKeyOf(other)}
+	_, err = imdb.TransferOwnership(ctx, "testdata", "1", wrongTransfer, sign(t, priv, auth.GetTransferHash(auth.DefaultDomain, "testdata", "1", 2, to)), PutOptions{})
+	require.Equal(t, ErrInvalidRecipient, err)
+
+	sig := sign(t, priv, auth.GetTransferHash(auth.DefaultDomain, "testdata", "1", 2, to))
+	result, err := imdb.TransferOwnership(ctx, "testdata", "1", transfer, sig, PutOptions{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result.Version)
+
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, publicKeyOf(recipient), obj.Owner)
+	require.Equal(t, testData1, obj.Data)
+
+	// the previous owner cannot update the object anymore
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 3, testData2)), PutOptions{})
+	require.Equal(t, ErrNotAuthorized, err)
+	_, err = imdb.Put(ctx, "testdata", "1", testData2, sign(t, recipient, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 3, testData2)), PutOptions{})
+	require.NoError(t, err)
+
+	// the transfer is recorded in the history
+	revisions, err := imdb.History(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	require.NotNil(t, revisions[1].Transfer)
+	require.Equal(t, publicKeyOf(priv), revisions[1].Signer)
+	require.Equal(t, publicKeyOf(recipient), revisions[1].Owner)
+}
+
+func TestInMemoryDatabase_TransferOwnership_RecipientSignature(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+	recipient, _ := crypto.GenerateKey()
+	to := crypto.PubkeyToAddress(recipient.PublicKey)
+
+	_, err := imdb.Put(ctx, "testdata", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// the public key is required without the recipient's signature
+	_, err = imdb.TransferOwnership(ctx, "testdata", "1", &Transfer{To: to}, sign(t, priv, auth.GetTransferHash(auth.DefaultDomain, "testdata", "1", 2, to)), PutOptions{})
+	require.Equal(t, ErrInvalidRecipient, err)
+
+	// the owner's signature cannot be used as the acceptance
+	transfer := &Transfer{To: to, RecipientSignature: sign(t, recipient, auth.GetTransferHash(auth.DefaultDomain, "testdata", "1", 2, to))}
+	_, err = imdb.TransferOwnership(ctx, "testdata", "1", transfer, sign(t, priv, auth.GetTransferHash(auth.DefaultDomain, "testdata", "1", 2, to)), PutOptions{})
+	require.Equal(t, ErrInvalidRecipient, err)
+
+	acceptSig := sign(t, recipient, auth.GetAcceptTransferHash(auth.DefaultDomain, "testdata", "1", 2, to))
+	transfer = &Transfer{To: to, RecipientSignature: acceptSig}
+	_, err = imdb.TransferOwnership(ctx, "testdata", "1", transfer, sign(t, priv, auth.GetTransferHash(auth.DefaultDomain, "testdata", "1", 2, to)), PutOptions{
+		ExpectedVersion: 1,
+	})
+	require.NoError(t, err)
+
+	obj, err := imdb.Get(ctx, "testdata", "1")
+	require.NoError(t, err)
+	require.Equal(t, publicKeyOf(recipient), obj.Owner)
+}
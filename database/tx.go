@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+)
+
+// MaxTxSize is the maximum number of operations in a transaction. Since a write takes
+// two items of DynamoDB TransactWriteItems with its revision, it is limited to 12
+// to fit in the limit of 25 items.
+const MaxTxSize = 12
+
+var (
+	ErrTxTooLarge  = errors.Errorf("a transaction cannot have more than %d operations.", MaxTxSize)
+	ErrInvalidTxOp = errors.New("invalid transaction operation.")
+)
+
+// TxOpType is a type of an operation in a transaction.
+type TxOpType string
+
+const (
+	// TxPut writes the object like Put.
+	TxPut TxOpType = "put"
+
+	// TxDelete deletes the object like Delete.
+	TxDelete TxOpType = "delete"
+
+	// TxCheck only checks that the current version of the object including deleted ones
+	// is the same with ExpectedVersion, where 0 means the object has never existed.
+	TxCheck TxOpType = "check"
+)
+
+// TxOp is an operation in a transaction, which is signed like a single write.
+// ExpectedVersion of the options is a precondition of the transaction.
+type TxOp struct {
+	ObjectKey
+	Op        TxOpType
+	Data      Payload
+	Signature []byte
+	Options   PutOptions
+}
+
+// TxError is an error failing the whole transaction, caused by an operation in it.
+type TxError struct {
+	// Index is the index of the failed operation.
+	Index int
+	Err   error
+}
+
+func (e *TxError) Error() string {
+	return fmt.Sprintf("operation %d: %s", e.Index, e.Err.Error())
+}
+
+// Cause returns the error of the operation, so that it can be checked with errors.Cause.
+func (e *TxError) Cause() error {
+	return e.Err
+}
+
+// ValidateTx checks the number of operations in a transaction and their types,
+// and the keys and the uniqueness of the objects.
+func ValidateTx(ops []TxOp) error {
+	if len(ops) > MaxTxSize {
+		return ErrTxTooLarge
+	}
+	for i, op := range ops {
+		if op.Op != TxPut && op.Op != TxDelete && op.Op != TxCheck {
+			return &TxError{Index: i, Err: ErrInvalidTxOp}
+		}
+		if !IsValidType(op.Type) {
+			return &TxError{Index: i, Err: ErrInvalidType}
+		}
+		if !IsValidID(op.ID) {
+			return &TxError{Index: i, Err: ErrInvalidID}
+		}
+	}
+	return ValidateBatch(TxKeysOf(ops))
+}
+
+// ApplyTx verifies every operation of a transaction on the current objects, which are
+// in the same order with the operations and nil for the objects never existed.
+// It returns the writes of the operations, which are nil for TxCheck.
+// If any of the operations fails, a TxError is returned and nothing should be written.
+func ApplyTx(ctx context.Context, config *Config, current []*Object, ops []TxOp) ([]*Write, error) {
+	writes := make([]*Write, len(ops))
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case TxPut:
+			writes[i], err = ApplyPut(ctx, config, current[i], op.Type, op.ID, op.Data, op.Signature, op.Options)
+		case TxDelete:
+			if err = op.Options.CheckVersion(versionOf(current[i])); err == nil {
+				writes[i], err = ApplyDelete(ctx, config, current[i], op.Signature)
+			}
+		case TxCheck:
+			if versionOf(current[i]) != op.Options.ExpectedVersion {
+				err = ErrConflict
+			}
+		default:
+			err = ErrInvalidTxOp
+		}
+		if err != nil {
+			return nil, &TxError{Index: i, Err: err}
+		}
+	}
+	return writes, nil
+}
+
+// versionOf returns the version of given object, or 0 if the object has never existed.
+func versionOf(obj *Object) uint64 {
+	if obj == nil {
+		return 0
+	}
+	return obj.Version
+}
+
+// TxKeysOf returns the keys of the objects in given operations.
+func TxKeysOf(ops []TxOp) []ObjectKey {
+	keys := make([]ObjectKey, len(ops))
+	for i, op := range ops {
+		keys[i] = op.ObjectKey
+	}
+	return keys
+}
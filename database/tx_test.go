@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestValidateTx(t *testing.T) {
+	require.NoError(t, ValidateTx([]TxOp{
+		{ObjectKey: ObjectKey{"testdata", "1"}, Op: TxPut},
+		{ObjectKey: ObjectKey{"testdata", "2"}, Op: TxDelete},
+		{ObjectKey: ObjectKey{"testdata", "3"}, Op: TxCheck},
+	}))
+	require.Equal(t, ErrDuplicatedKey, ValidateTx([]TxOp{
+		{ObjectKey: ObjectKey{"testdata", "1"}, Op: TxPut},
+		{ObjectKey: ObjectKey{"testdata", "1"}, Op: TxCheck},
+	}))
+
+	err := ValidateTx([]TxOp{{ObjectKey: ObjectKey{"testdata", "1"}, Op: "update"}})
+	require.Equal(t, ErrInvalidTxOp, errors.Cause(err))
+	err = ValidateTx([]TxOp{{ObjectKey: ObjectKey{"testdata", "1/1"}, Op: TxCheck}})
+	require.Equal(t, ErrInvalidID, errors.Cause(err))
+	require.Equal(t, ErrTxTooLarge, ValidateTx(make([]TxOp, MaxTxSize+1)))
+}
+
+func TestInMemoryDatabase_Transact(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	counter := Payload{"count": float64(1)}
+	_, err := imdb.Put(ctx, "counter", "1", counter, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "counter", "1", 1, counter)), PutOptions{})
+	require.NoError(t, err)
+	_, err = imdb.Put(ctx, "testdata", "2", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	newCounter := Payload{"count": float64(2)}
+	results, err := imdb.Transact(ctx, []TxOp{
+		{
+			ObjectKey: ObjectKey{"action", "1"},
+			Op:        TxPut,
+			Data:      testData1,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "action", "1", 1, testData1)),
+		},
+		{
+			ObjectKey: ObjectKey{"counter", "1"},
+			Op:        TxPut,
+			Data:      newCounter,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "counter", "1", 2, newCounter)),
+			Options:   PutOptions{ExpectedVersion: 1},
+		},
+		{
+			ObjectKey: ObjectKey{"testdata", "2"},
+			Op:        TxDelete,
+			Signature: sign(t, priv, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "2", 2)),
+		},
+		{ObjectKey: ObjectKey{"testdata", "3"}, Op: TxCheck},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	require.True(t, results[0].Created)
+	require.Equal(t, uint64(2), results[1].Version)
+	require.Equal(t, uint64(2), results[2].Version)
+	require.Nil(t, results[3])
+
+	obj, err := imdb.Get(ctx, "counter", "1")
+	require.NoError(t, err)
+	require.Equal(t, newCounter, obj.Data)
+	_, err = imdb.Get(ctx, "testdata", "2")
+	require.Equal(t, ErrNotExists, err)
+}
+
+func TestInMemoryDatabase_Transact_AllOrNothing(t *testing.T) {
+	ctx := context.TODO()
+	imdb, _ := NewInMemoryDatabase()
+	priv, _ := crypto.GenerateKey()
+
+	_, err := imdb.Put(ctx, "counter", "1", testData1, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "counter", "1", 1, testData1)), PutOptions{})
+	require.NoError(t, err)
+
+	// a failed precondition should roll back the other operations
+	_, err = imdb.Transact(ctx, []TxOp{
+		{
+			ObjectKey: ObjectKey{"action", "1"},
+			Op:        TxPut,
+			Data:      testData1,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "action", "1", 1, testData1)),
+		},
+		{ObjectKey: ObjectKey{"counter", "1"}, Op: TxCheck, Options: PutOptions{ExpectedVersion: 2}},
+	})
+	require.Equal(t, ErrConflict, errors.Cause(err))
+	require.Equal(t, 1, err.(*TxError).Index)
+
+	exists, err := imdb.Exists(ctx, "action", "1")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// so does a write by others
+	other, _ := crypto.GenerateKey()
+	_, err = imdb.Transact(ctx, []TxOp{
+		{
+			ObjectKey: ObjectKey{"action", "1"},
+			Op:        TxPut,
+			Data:      testData1,
+			Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "action", "1", 1, testData1)),
+		},
+		{
+			ObjectKey: ObjectKey{"counter", "1"},
+			Op:        TxPut,
+			Data:      testData2,
+			Signature: sign(t, other, auth.GetObjectHash(auth.DefaultDomain, "counter", "1", 2, testData2)),
+		},
+	})
+	require.Equal(t, ErrNotAuthorized, errors.Cause(err))
+
+	exists, err = imdb.Exists(ctx, "action", "1")
+	require.NoError(t, err)
+	require.False(t, exists)
+	history, err := imdb.History(ctx, "counter", "1")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+}
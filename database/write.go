@@ -0,0 +1,200 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// Write is a verified write to an object. Backends should store it only if
+// the object has not been changed since it is read, which can be checked with PrevVersion.
+type Write struct {
+	// Object is the object after the write, which is a tombstone for deletions.
+	Object   *Object
+	Revision *Revision
+	Created  bool
+
+	// EraseHistory is true if the data of the previous revisions should be erased,
+	// which is the case for deletions and re-creations of expired objects.
+	EraseHistory bool
+
+	// Fee is the fee charged to the payer, which is set by ChargesOf.
+	Fee uint64
+}
+
+// PrevVersion returns the version of the object which the write is based on.
+func (w *Write) PrevVersion() uint64 {
+	return w.Object.Version - 1
+}
+
+// Result returns the result of the write.
+func (w *Write) Result() *PutResult {
+	return &PutResult{
+		FeeUsed: w.Fee,
+		Created: w.Created,
+		Version: w.Object.Version,
+	}
+}
+
+// ApplyPut verifies a write of given data with the signature, and returns the write.
+// The current object is nil if the object has never existed.
+func ApplyPut(ctx context.Context, config *Config, current *Object, typ, id string, data Payload, signature []byte, opts PutOptions) (*Write, error) {
+	if !IsValidType(typ) {
+		return nil, ErrInvalidType
+	}
+	if !IsValidID(id) {
+		return nil, ErrInvalidID
+	}
+	var expiresAt time.Time
+	if !opts.ExpiresAt.IsZero() {
+		if expiresAt = time.Unix(opts.ExpiresAt.Unix(), 0); !expiresAt.After(time.Now()) {
+			return nil, ErrInvalidExpiry
+		}
+	}
+	// validate the data first, since recovering the signature is expensive
+	if err := checkReservedKeys(data); err != nil {
+		return nil, err
+	}
+	if err := opts.Schema.Validate(data); err != nil {
+		return nil, err
+	}
+	var currentVersion uint64
+	if current != nil {
+		currentVersion = current.Version
+	}
+	if err := opts.CheckVersion(currentVersion); err != nil {
+		return nil, err
+	}
+
+	if !IsLive(current) {
+		// create new. versions of deleted or expired objects continue from the tombstone,
+		// so that signatures made before the deletion cannot be replayed.
+		now := time.Now()
+		obj := &Object{
+			ID:        id,
+			Type:      typ,
+			Data:      data,
+			Version:   currentVersion + 1,
+			Private:   opts.Private,
+			ExpiresAt: expiresAt,
+
+			CreatedAt:     now,
+			LastUpdatedAt: now,
+		}
+		owner, err := putSigner(ctx, config.Domain, typ, id, obj.Version, data, expiresAt, signature)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid signature")
+		}
+		obj.Owner = owner
+		return &Write{
+			Object:   obj,
+			Revision: NewRevision(obj, owner, signature),
+			Created:  true,
+
+			// the expired object may not have been swept yet
+			EraseHistory: current != nil && !current.Deleted,
+		}, nil
+	}
+
+	// update object. the signature should be made for the next version,
+	// so that signatures for the previous versions cannot be replayed.
+	signer, err := putSigner(ctx, config.Domain, typ, id, current.Version+1, data, expiresAt, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signature")
+	}
+
+	// only object owners and writers granted by them can update the object
+	if !canWrite(current, signer) {
+		return nil, ErrNotAuthorized
+	}
+	updated := *current
+	updated.Data = data
+	updated.ExpiresAt = expiresAt
+	updated.Version++
+	updated.LastUpdatedAt = time.Now()
+	return &Write{
+		Object:   &updated,
+		Revision: NewRevision(&updated, signer, signature),
+	}, nil
+}
+
+// checkReservedKeys returns ErrReservedKey if the data has any of ReservedKeys.
+func checkReservedKeys(data Payload) error {
+	for _, key := range ReservedKeys {
+		if _, ok := data[key]; ok {
+			return ErrReservedKey
+		}
+	}
+	return nil
+}
+
+// putSigner recovers the signer of a write, whose preimage has the expiry only if it is set.
+func putSigner(ctx context.Context, domain, typ, id string, version uint64, data Payload, expiresAt time.Time, signature []byte) (auth.PublicKey, error) {
+	if expiresAt.IsZero() {
+		return auth.GetSigner(ctx, domain, typ, id, version, data, signature)
+	}
+	return auth.GetExpiringSigner(ctx, domain, typ, id, version, data, expiresAt.Unix(), signature)
+}
+
+// ApplyPatch verifies that the patch is signed by the owner or a writer for the current version
+// of the object, and returns the write of the patched object.
+func ApplyPatch(ctx context.Context, config *Config, current *Object, patch *Patch, signature []byte, opts PutOptions) (*Write, error) {
+	if !IsLive(current) {
+		return nil, ErrNotExists
+	}
+	if err := opts.CheckVersion(current.Version); err != nil {
+		return nil, err
+	}
+	signer, err := auth.GetPatchSigner(ctx, config.Domain, current.Type, current.ID, current.Version, string(patch.Type), patch.Document, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signature")
+	}
+	if !canWrite(current, signer) {
+		return nil, ErrNotAuthorized
+	}
+
+	data, err := patch.Apply(current.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReservedKeys(data); err != nil {
+		return nil, err
+	}
+	if err := opts.Schema.Validate(data); err != nil {
+		return nil, err
+	}
+	patched := *current
+	patched.Data = data
+	patched.Version++
+	patched.LastUpdatedAt = time.Now()
+
+	rev := NewRevision(&patched, signer, signature)
+	rev.Patch = patch
+	return &Write{
+		Object:   &patched,
+		Revision: rev,
+	}, nil
+}
+
+// ApplyDelete verifies that the deletion is signed by the owner,
+// and returns the write of the tombstone.
+func ApplyDelete(ctx context.Context, config *Config, current *Object, signature []byte) (*Write, error) {
+	if !IsLive(current) {
+		return nil, ErrNotExists
+	}
+	signer, err := auth.GetDeleteSigner(ctx, config.Domain, current.Type, current.ID, current.Version+1, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover signature")
+	}
+	if !bytes.Equal(signer[:], current.Owner[:]) {
+		return nil, ErrNotAuthorized
+	}
+	tombstone := Tombstone(current)
+	return &Write{
+		Object:       tombstone,
+		Revision:     NewRevision(tombstone, signer, signature),
+		EraseHistory: true,
+	}, nil
+}
@@ -1,14 +1,27 @@
 package main
 
 import (
+	"context"
 	"github.com/airbloc/airframe/apiserver"
 	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/database/dynamodb"
+	"github.com/airbloc/airframe/database/leveldb"
+	"github.com/airbloc/airframe/database/metrics"
+	"github.com/airbloc/airframe/database/tracing"
+	"github.com/airbloc/airframe/ratelimit"
 	"github.com/airbloc/airframe/rpcserver"
+	"github.com/airbloc/airframe/tracing"
 	"github.com/airbloc/logger"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/klaytn/klaytn/common"
 	"github.com/pkg/errors"
+	"io"
 	"os"
 	"os/signal"
 	"runtime"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -24,16 +37,88 @@ func main() {
 	log := logger.New("main")
 	log.Info("Using {} configuration", config.Profile)
 
-	db, err := initDatabase(config.Backend)
+	admins := make([]common.Address, len(config.Admins))
+	for i, admin := range config.Admins {
+		if !common.IsHexAddress(admin) {
+			log.Error("error: invalid admin address", errors.Errorf("%s is not a hex address", admin))
+			os.Exit(1)
+		}
+		admins[i] = common.HexToAddress(admin)
+	}
+
+	multipliers := make(map[string]float64, len(config.FeeMultipliers))
+	for typ, multiplier := range config.FeeMultipliers {
+		m, err := strconv.ParseFloat(multiplier, 64)
+		if err != nil || m < 0 {
+			log.Error("error: invalid fee multiplier", errors.Errorf("%s is not a valid multiplier of %s", multiplier, typ))
+			os.Exit(1)
+		}
+		multipliers[typ] = m
+	}
+	apiKeys := make(map[string]bool, len(config.APIKeys))
+	for _, key := range config.APIKeys {
+		apiKeys[key] = true
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:    config.TraceExporter,
+		Endpoint:    config.OtlpEndpoint,
+		SampleRatio: config.TraceSampleRatio,
+	})
+	if err != nil {
+		log.Error("error: failed to setup tracing", err)
+		os.Exit(1)
+	}
+
+	// the servers share the configuration of the backend to verify reads
+	options := []database.Option{
+		database.WithDomain(config.Domain),
+		database.WithAdmins(admins...),
+		database.WithFeeModel(&database.LinearFeeModel{
+			PerWrite:    config.FeePerWrite,
+			PerByte:     config.FeePerByte,
+			Multipliers: multipliers,
+		}),
+		database.WithQuotaEnforced(config.EnforceQuota),
+	}
+	if writeLimiter := newRateLimiter(config.WriteRate, config.WriteBurst); writeLimiter != nil {
+		options = append(options, database.WithWriteLimiter(writeLimiter))
+	}
+	db, err := initDatabase(config, options...)
 	if err != nil {
 		log.Error("error: failed to initialize database", err)
 		os.Exit(1)
 	}
+	// sweep expired objects in the background
+	sweepCtx, stopSweeper := context.WithCancel(context.Background())
+	var sweeping sync.WaitGroup
+	if sweeper, ok := db.(database.Sweeper); ok {
+		sweeping.Add(1)
+		go func() {
+			defer sweeping.Done()
+			runSweeper(sweepCtx, sweeper, config.SweepInterval, log)
+		}()
+	}
 
-	// start API and RPC server
+	// start API and RPC server, which report the operations of the database to /metrics
+	// and trace them in the spans of the requests
+	instrumented := tracingdatabase.New(metricsdatabase.New(db))
+	dbConfig := database.NewConfig(options...)
+	readLimiter := newRateLimiter(config.ReadRate, config.ReadBurst)
+	ipWriteLimiter := newRateLimiter(config.IPWriteRate, config.IPWriteBurst)
 	servers := map[string]Server{
-		"API": apiserver.New(db, config.Port, config.Profile == "dev"),
-		"RPC": rpcserver.New(db, config.RpcPort, config.Profile == "dev"),
+		"API": apiserver.New(instrumented, config.Port, config.Profile == "dev", apiserver.Options{
+			Config:       dbConfig,
+			ReadLimiter:  readLimiter,
+			WriteLimiter: ipWriteLimiter,
+			APIKeys:      apiKeys,
+		}),
+		"RPC": rpcserver.New(instrumented, config.RpcPort, config.Profile == "dev", rpcserver.Options{
+			Config:       dbConfig,
+			ReadLimiter:  readLimiter,
+			WriteLimiter: ipWriteLimiter,
+			APIKeys:      apiKeys,
+		}),
 	}
 	for name, server := range servers {
 		go func() {
@@ -46,19 +131,95 @@ func main() {
 		log.Info("{} server started", name)
 	}
 
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 
 	for _, server := range servers {
 		server.Stop()
 	}
+	stopSweeper()
+	sweeping.Wait()
+	if closer, ok := db.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Error("failed to close database", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("failed to flush spans", err)
+	}
+	cancel()
 	log.Info("bye")
 }
 
-func initDatabase(backendType string) (database.Database, error) {
-	if backendType == "memory" {
-		return database.NewInMemoryDatabase()
+// newRateLimiter returns a limiter allowing rate requests per second for each key with given burst,
+// or a nil interface if the rate is not positive, so that the servers skip limiting.
+func newRateLimiter(rate float64, burst int) database.RateLimiter {
+	if limiter := ratelimit.New(rate, burst); limiter != nil {
+		return limiter
+	}
+	return nil
+}
+
+// runSweeper sweeps expired objects every interval until the context is canceled.
+func runSweeper(ctx context.Context, sweeper database.Sweeper, interval time.Duration, log *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			swept, err := sweeper.Sweep(ctx, now)
+			if err != nil {
+				log.Error("failed to sweep expired objects", err)
+				continue
+			}
+			if swept > 0 {
+				log.Info("swept {} expired objects", swept)
+			}
+		}
+	}
+}
+
+func initDatabase(config *Config, options ...database.Option) (database.Database, error) {
+	switch config.Backend {
+	case "memory":
+		return database.NewInMemoryDatabase(options...)
+	case "leveldb":
+		return leveldatabase.New(config.DataDir, options...)
+	case "dynamodb":
+		return initDynamoDatabase(config, options...)
+	}
+	return nil, errors.Errorf("unknown backend: %s", config.Backend)
+}
+
+func initDynamoDatabase(config *Config, options ...database.Option) (database.Database, error) {
+	awsConfig := aws.NewConfig()
+	if config.DynamoRegion != "" {
+		awsConfig = awsConfig.WithRegion(config.DynamoRegion)
+	}
+	if config.DynamoEndpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(config.DynamoEndpoint)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS session")
+	}
+	if _, err := sess.Config.Credentials.Get(); err != nil {
+		return nil, errors.Wrap(err, "failed to load AWS credentials")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db := dynamodatabase.New(sess, config.DynamoTablePrefix, options...)
+	if err := db.Init(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize DynamoDB")
 	}
-	return nil, errors.Errorf("unknown backend: %s", backendType)
+	return db, nil
 }
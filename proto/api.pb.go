@@ -1,5 +1,5 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
-// source: rpcserver/api.proto
+// source: proto/api.proto
 
 package proto
 
@@ -23,8 +23,15 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type GetRequest struct {
-	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id   string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// includeDeleted returns the version of a deleted object with deleted = true,
+	// instead of NOT_FOUND. It is used for signing the next version of the object.
+	IncludeDeleted bool `protobuf:"varint,3,opt,name=includeDeleted,proto3" json:"includeDeleted,omitempty"`
+	// revision reads the object as of given version, and at reads the object as of
+	// given time in Unix nanoseconds. revision takes precedence if both are given.
+	Revision             uint64   `protobuf:"varint,4,opt,name=revision,proto3" json:"revision,omitempty"`
+	At                   uint64   `protobuf:"varint,5,opt,name=at,proto3" json:"at,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -34,7 +41,7 @@ func (m *GetRequest) Reset()         { *m = GetRequest{} }
 func (m *GetRequest) String() string { return proto.CompactTextString(m) }
 func (*GetRequest) ProtoMessage()    {}
 func (*GetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_eb4e1df46406b2cd, []int{0}
+	return fileDescriptor_ecf0878b123623e2, []int{0}
 }
 
 func (m *GetRequest) XXX_Unmarshal(b []byte) error {
@@ -69,11 +76,40 @@ func (m *GetRequest) GetId() string {
 	return ""
 }
 
+func (m *GetRequest) GetIncludeDeleted() bool {
+	if m != nil {
+		return m.IncludeDeleted
+	}
+	return false
+}
+
+func (m *GetRequest) GetRevision() uint64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *GetRequest) GetAt() uint64 {
+	if m != nil {
+		return m.At
+	}
+	return 0
+}
+
 type GetResponse struct {
-	Data                 string   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
-	CreatedAt            uint64   `protobuf:"varint,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
-	LastUpdatedAt        uint64   `protobuf:"varint,4,opt,name=lastUpdatedAt,proto3" json:"lastUpdatedAt,omitempty"`
+	Data          string `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Owner         string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	CreatedAt     uint64 `protobuf:"varint,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	LastUpdatedAt uint64 `protobuf:"varint,4,opt,name=lastUpdatedAt,proto3" json:"lastUpdatedAt,omitempty"`
+	Version       uint64 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	Deleted       bool   `protobuf:"varint,6,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// writers and readers are hex addresses granted by the owner.
+	Writers []string `protobuf:"bytes,7,rep,name=writers,proto3" json:"writers,omitempty"`
+	Readers []string `protobuf:"bytes,8,rep,name=readers,proto3" json:"readers,omitempty"`
+	Private bool     `protobuf:"varint,9,opt,name=private,proto3" json:"private,omitempty"`
+	// expiresAt is the expiry in unix nanoseconds, which is 0 if the object never expires.
+	ExpiresAt            uint64   `protobuf:"varint,10,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -83,7 +119,7 @@ func (m *GetResponse) Reset()         { *m = GetResponse{} }
 func (m *GetResponse) String() string { return proto.CompactTextString(m) }
 func (*GetResponse) ProtoMessage()    {}
 func (*GetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_eb4e1df46406b2cd, []int{1}
+	return fileDescriptor_ecf0878b123623e2, []int{1}
 }
 
 func (m *GetResponse) XXX_Unmarshal(b []byte) error {
@@ -132,11 +168,59 @@ func (m *GetResponse) GetLastUpdatedAt() uint64 {
 	return 0
 }
 
+func (m *GetResponse) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetResponse) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+func (m *GetResponse) GetWriters() []string {
+	if m != nil {
+		return m.Writers
+	}
+	return nil
+}
+
+func (m *GetResponse) GetReaders() []string {
+	if m != nil {
+		return m.Readers
+	}
+	return nil
+}
+
+func (m *GetResponse) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *GetResponse) GetExpiresAt() uint64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
 type QueryRequest struct {
-	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Query                string   `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
-	Skip                 uint64   `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`
-	Limit                uint64   `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Query string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	Skip  uint64 `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`
+	Limit uint64 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// sort is a field to sort the results by. "id", "createdAt" and "lastUpdatedAt" are
+	// reserved for the object's ID and timestamps. order is either "asc" (default) or "desc".
+	Sort  string `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"`
+	Order string `protobuf:"bytes,6,opt,name=order,proto3" json:"order,omitempty"`
+	// cursor is nextCursor of the previous page, to continue the query after it.
+	Cursor               string   `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -146,7 +230,7 @@ func (m *QueryRequest) Reset()         { *m = QueryRequest{} }
 func (m *QueryRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryRequest) ProtoMessage()    {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_eb4e1df46406b2cd, []int{2}
+	return fileDescriptor_ecf0878b123623e2, []int{2}
 }
 
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
@@ -195,18 +279,41 @@ func (m *QueryRequest) GetLimit() uint64 {
 	return 0
 }
 
+func (m *QueryRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetOrder() string {
+	if m != nil {
+		return m.Order
+	}
+	return ""
+}
+
+func (m *QueryRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
 type QueryResponse struct {
-	Results              []*GetResponse `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Results []*GetResponse `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// nextCursor is an opaque token to fetch the next page. It is empty on the last page.
+	NextCursor           string   `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
 func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()    {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_eb4e1df46406b2cd, []int{3}
+	return fileDescriptor_ecf0878b123623e2, []int{3}
 }
 
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
@@ -234,11 +341,26 @@ func (m *QueryResponse) GetResults() []*GetResponse {
 	return nil
 }
 
+func (m *QueryResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
 type PutRequest struct {
-	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	Data                 string   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
-	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	Type      string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id        string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Data      string `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	// expectedVersion makes the write fail with ABORTED unless the current version
+	// of the object is the same with it. 0 means no expectation.
+	ExpectedVersion uint64 `protobuf:"varint,5,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// private creates the object as a private object. It is ignored on updates.
+	Private bool `protobuf:"varint,6,opt,name=private,proto3" json:"private,omitempty"`
+	// expiresAt makes the object expire at given unix nanoseconds, which is truncated to seconds.
+	// The signature should be made with the expiry in unix seconds. 0 means the object never expires.
+	ExpiresAt            uint64   `protobuf:"varint,7,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -248,7 +370,7 @@ func (m *PutRequest) Reset()         { *m = PutRequest{} }
 func (m *PutRequest) String() string { return proto.CompactTextString(m) }
 func (*PutRequest) ProtoMessage()    {}
 func (*PutRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_eb4e1df46406b2cd, []int{4}
+	return fileDescriptor_ecf0878b123623e2, []int{4}
 }
 
 func (m *PutRequest) XXX_Unmarshal(b []byte) error {
@@ -297,9 +419,31 @@ func (m *PutRequest) GetSignature() []byte {
 	return nil
 }
 
+func (m *PutRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *PutRequest) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *PutRequest) GetExpiresAt() uint64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
 type PutResponse struct {
 	Created              bool     `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
 	FeeUsed              uint64   `protobuf:"varint,2,opt,name=feeUsed,proto3" json:"feeUsed,omitempty"`
+	Version              uint64   `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -309,7 +453,7 @@ func (m *PutResponse) Reset()         { *m = PutResponse{} }
 func (m *PutResponse) String() string { return proto.CompactTextString(m) }
 func (*PutResponse) ProtoMessage()    {}
 func (*PutResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_eb4e1df46406b2cd, []int{5}
+	return fileDescriptor_ecf0878b123623e2, []int{5}
 }
 
 func (m *PutResponse) XXX_Unmarshal(b []byte) error {
@@ -344,178 +488,2339 @@ func (m *PutResponse) GetFeeUsed() uint64 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*GetRequest)(nil), "GetRequest")
-	proto.RegisterType((*GetResponse)(nil), "GetResponse")
-	proto.RegisterType((*QueryRequest)(nil), "QueryRequest")
-	proto.RegisterType((*QueryResponse)(nil), "QueryResponse")
-	proto.RegisterType((*PutRequest)(nil), "PutRequest")
-	proto.RegisterType((*PutResponse)(nil), "PutResponse")
+func (m *PutResponse) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
 }
 
-func init() { proto.RegisterFile("rpcserver/api.proto", fileDescriptor_eb4e1df46406b2cd) }
-
-var fileDescriptor_eb4e1df46406b2cd = []byte{
-	// 356 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0xb1, 0x4e, 0xc3, 0x30,
-	0x10, 0x6d, 0x9a, 0x94, 0x92, 0x4b, 0xda, 0xc1, 0x74, 0x88, 0xaa, 0x0e, 0x95, 0x85, 0x50, 0xa6,
-	0x80, 0xca, 0xc0, 0x5c, 0x96, 0x8a, 0x89, 0x12, 0xa9, 0x0b, 0x5b, 0xd2, 0x1c, 0xc8, 0x50, 0x1a,
-	0xd7, 0x76, 0x40, 0x15, 0x5f, 0xc0, 0x5f, 0x23, 0xbb, 0x4e, 0x93, 0x2e, 0x48, 0x6c, 0xf7, 0xce,
-	0xef, 0xfc, 0x9e, 0x9f, 0x0f, 0x2e, 0x04, 0x5f, 0x4b, 0x14, 0x9f, 0x28, 0xae, 0x33, 0xce, 0x12,
-	0x2e, 0x4a, 0x55, 0xd2, 0x1b, 0x80, 0x05, 0xaa, 0x14, 0x77, 0x15, 0x4a, 0x45, 0x08, 0x78, 0x6a,
-	0xcf, 0x31, 0x72, 0xa6, 0x4e, 0xec, 0xa7, 0xa6, 0x26, 0x43, 0xe8, 0xb2, 0x22, 0xea, 0x9a, 0x4e,
-	0x97, 0x15, 0xf4, 0x1b, 0x02, 0x33, 0x21, 0x79, 0xb9, 0x95, 0xa8, 0x47, 0x8a, 0x4c, 0x65, 0xf5,
-	0x88, 0xae, 0xc9, 0x08, 0x7a, 0xe5, 0xd7, 0x16, 0x85, 0x9d, 0x3a, 0x00, 0x32, 0x01, 0x7f, 0x2d,
-	0x30, 0x53, 0x58, 0xcc, 0x55, 0xe4, 0x4e, 0x9d, 0xd8, 0x4b, 0x9b, 0x06, 0xb9, 0x84, 0xc1, 0x26,
-	0x93, 0x6a, 0xc5, 0x0b, 0xcb, 0xf0, 0x0c, 0xe3, 0xb4, 0x49, 0x73, 0x08, 0x9f, 0x2a, 0x14, 0xfb,
-	0xbf, 0x0c, 0x8f, 0xa0, 0xb7, 0xd3, 0x9c, 0x5a, 0xdd, 0x00, 0xcd, 0x94, 0xef, 0x8c, 0x5b, 0x61,
-	0x53, 0x6b, 0xe6, 0x86, 0x7d, 0xb0, 0x5a, 0xeb, 0x00, 0xe8, 0x1d, 0x0c, 0xac, 0x86, 0x7d, 0xe2,
-	0x15, 0xf4, 0x05, 0xca, 0x6a, 0xa3, 0x64, 0xe4, 0x4c, 0xdd, 0x38, 0x98, 0x85, 0x49, 0x2b, 0x81,
-	0xb4, 0x3e, 0xa4, 0x39, 0xc0, 0xb2, 0xfa, 0x4f, 0x96, 0xc7, 0xf0, 0xdc, 0x56, 0x78, 0x13, 0xf0,
-	0x25, 0x7b, 0xdd, 0x66, 0xaa, 0x12, 0x68, 0x8c, 0x85, 0x69, 0xd3, 0xa0, 0x73, 0x08, 0x8c, 0x86,
-	0xb5, 0x16, 0x41, 0xdf, 0x46, 0x68, 0x74, 0xce, 0xd3, 0x1a, 0xea, 0x93, 0x17, 0xc4, 0x95, 0xc4,
-	0x83, 0x9e, 0x97, 0xd6, 0x70, 0xf6, 0xe3, 0x80, 0x3b, 0x5f, 0x3e, 0x90, 0x18, 0xfc, 0x05, 0xaa,
-	0xc7, 0xfc, 0x0d, 0xd7, 0x8a, 0x04, 0x49, 0xb3, 0x06, 0xe3, 0x93, 0xf7, 0xd1, 0x0e, 0x49, 0x20,
-	0x30, 0x89, 0x58, 0xee, 0x20, 0x69, 0xff, 0xc1, 0x78, 0x98, 0x9c, 0xc4, 0x45, 0x3b, 0xfa, 0xe6,
-	0x65, 0xd5, 0xdc, 0xdc, 0x84, 0x32, 0x0e, 0x93, 0x96, 0x7b, 0xda, 0xb9, 0x0f, 0x9e, 0xfd, 0xe3,
-	0x56, 0xe6, 0x67, 0x66, 0x25, 0x6f, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xd6, 0xbd, 0x78, 0xb4,
-	0xa9, 0x02, 0x00, 0x00,
+type PatchRequest struct {
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id   string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// patchType is either "merge" for JSON Merge Patch (RFC 7396),
+	// or "json" for JSON Patch (RFC 6902). The patch is signed as it is.
+	PatchType            string   `protobuf:"bytes,3,opt,name=patchType,proto3" json:"patchType,omitempty"`
+	Patch                string   `protobuf:"bytes,4,opt,name=patch,proto3" json:"patch,omitempty"`
+	Signature            []byte   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,6,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *PatchRequest) Reset()         { *m = PatchRequest{} }
+func (m *PatchRequest) String() string { return proto.CompactTextString(m) }
+func (*PatchRequest) ProtoMessage()    {}
+func (*PatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{6}
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *PatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PatchRequest.Unmarshal(m, b)
+}
+func (m *PatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PatchRequest.Marshal(b, m, deterministic)
+}
+func (m *PatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PatchRequest.Merge(m, src)
+}
+func (m *PatchRequest) XXX_Size() int {
+	return xxx_messageInfo_PatchRequest.Size(m)
+}
+func (m *PatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PatchRequest.DiscardUnknown(m)
+}
 
-// APIClient is the client API for API service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type APIClient interface {
-	GetObject(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
-	QueryObject(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
-	PutObject(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
+var xxx_messageInfo_PatchRequest proto.InternalMessageInfo
+
+func (m *PatchRequest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
 }
 
-type aPIClient struct {
-	cc *grpc.ClientConn
+func (m *PatchRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
 }
 
-func NewAPIClient(cc *grpc.ClientConn) APIClient {
-	return &aPIClient{cc}
+func (m *PatchRequest) GetPatchType() string {
+	if m != nil {
+		return m.PatchType
+	}
+	return ""
 }
 
-func (c *aPIClient) GetObject(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
-	out := new(GetResponse)
-	err := c.cc.Invoke(ctx, "/API/GetObject", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PatchRequest) GetPatch() string {
+	if m != nil {
+		return m.Patch
 	}
-	return out, nil
+	return ""
 }
 
-func (c *aPIClient) QueryObject(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
-	out := new(QueryResponse)
-	err := c.cc.Invoke(ctx, "/API/QueryObject", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PatchRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
 	}
-	return out, nil
+	return nil
 }
 
-func (c *aPIClient) PutObject(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error) {
-	out := new(PutResponse)
-	err := c.cc.Invoke(ctx, "/API/PutObject", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PatchRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
 	}
-	return out, nil
+	return 0
 }
 
-// APIServer is the server API for API service.
-type APIServer interface {
-	GetObject(context.Context, *GetRequest) (*GetResponse, error)
-	QueryObject(context.Context, *QueryRequest) (*QueryResponse, error)
-	PutObject(context.Context, *PutRequest) (*PutResponse, error)
+type DeleteRequest struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func RegisterAPIServer(s *grpc.Server, srv APIServer) {
-	s.RegisterService(&_API_serviceDesc, srv)
+func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()    {}
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{7}
 }
 
-func _API_GetObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(APIServer).GetObject(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/API/GetObject",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).GetObject(ctx, req.(*GetRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
+}
+func (m *DeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRequest.Merge(m, src)
+}
+func (m *DeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteRequest.Size(m)
+}
+func (m *DeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRequest.DiscardUnknown(m)
 }
 
-func _API_QueryObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QueryRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(APIServer).QueryObject(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/API/QueryObject",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).QueryObject(ctx, req.(*QueryRequest))
+var xxx_messageInfo_DeleteRequest proto.InternalMessageInfo
+
+func (m *DeleteRequest) GetType() string {
+	if m != nil {
+		return m.Type
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _API_PutObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PutRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(APIServer).PutObject(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/API/PutObject",
+func (m *DeleteRequest) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).PutObject(ctx, req.(*PutRequest))
+	return ""
+}
+
+func (m *DeleteRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _API_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "API",
-	HandlerType: (*APIServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetObject",
-			Handler:    _API_GetObject_Handler,
-		},
-		{
-			MethodName: "QueryObject",
-			Handler:    _API_QueryObject_Handler,
-		},
-		{
-			MethodName: "PutObject",
-			Handler:    _API_PutObject_Handler,
+type DeleteResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
+func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteResponse) ProtoMessage()    {}
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{8}
+}
+
+func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteResponse.Unmarshal(m, b)
+}
+func (m *DeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteResponse.Merge(m, src)
+}
+func (m *DeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteResponse.Size(m)
+}
+func (m *DeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteResponse proto.InternalMessageInfo
+
+type TransferRequest struct {
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id   string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// newOwner is the hex address of the new owner, which is signed by the current owner.
+	// newOwnerKey is the 33-byte compressed public key of the new owner, which can be
+	// omitted if recipientSignature is given, since the key is recovered from it.
+	NewOwner             string   `protobuf:"bytes,3,opt,name=newOwner,proto3" json:"newOwner,omitempty"`
+	NewOwnerKey          []byte   `protobuf:"bytes,4,opt,name=newOwnerKey,proto3" json:"newOwnerKey,omitempty"`
+	Signature            []byte   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	RecipientSignature   []byte   `protobuf:"bytes,6,opt,name=recipientSignature,proto3" json:"recipientSignature,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,7,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferRequest) Reset()         { *m = TransferRequest{} }
+func (m *TransferRequest) String() string { return proto.CompactTextString(m) }
+func (*TransferRequest) ProtoMessage()    {}
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{9}
+}
+
+func (m *TransferRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferRequest.Unmarshal(m, b)
+}
+func (m *TransferRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferRequest.Marshal(b, m, deterministic)
+}
+func (m *TransferRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferRequest.Merge(m, src)
+}
+func (m *TransferRequest) XXX_Size() int {
+	return xxx_messageInfo_TransferRequest.Size(m)
+}
+func (m *TransferRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferRequest proto.InternalMessageInfo
+
+func (m *TransferRequest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetNewOwner() string {
+	if m != nil {
+		return m.NewOwner
+	}
+	return ""
+}
+
+func (m *TransferRequest) GetNewOwnerKey() []byte {
+	if m != nil {
+		return m.NewOwnerKey
+	}
+	return nil
+}
+
+func (m *TransferRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *TransferRequest) GetRecipientSignature() []byte {
+	if m != nil {
+		return m.RecipientSignature
+	}
+	return nil
+}
+
+func (m *TransferRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+type SetACLRequest struct {
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id   string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// writers can update the object on behalf of the owner, and readers can read it.
+	// Both are hex addresses, and are signed by the owner in the given order.
+	Writers              []string `protobuf:"bytes,3,rep,name=writers,proto3" json:"writers,omitempty"`
+	Readers              []string `protobuf:"bytes,4,rep,name=readers,proto3" json:"readers,omitempty"`
+	Signature            []byte   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,6,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetACLRequest) Reset()         { *m = SetACLRequest{} }
+func (m *SetACLRequest) String() string { return proto.CompactTextString(m) }
+func (*SetACLRequest) ProtoMessage()    {}
+func (*SetACLRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{10}
+}
+
+func (m *SetACLRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetACLRequest.Unmarshal(m, b)
+}
+func (m *SetACLRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetACLRequest.Marshal(b, m, deterministic)
+}
+func (m *SetACLRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetACLRequest.Merge(m, src)
+}
+func (m *SetACLRequest) XXX_Size() int {
+	return xxx_messageInfo_SetACLRequest.Size(m)
+}
+func (m *SetACLRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetACLRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetACLRequest proto.InternalMessageInfo
+
+func (m *SetACLRequest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *SetACLRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SetACLRequest) GetWriters() []string {
+	if m != nil {
+		return m.Writers
+	}
+	return nil
+}
+
+func (m *SetACLRequest) GetReaders() []string {
+	if m != nil {
+		return m.Readers
+	}
+	return nil
+}
+
+func (m *SetACLRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SetACLRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+type SetVisibilityRequest struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Private              bool     `protobuf:"varint,3,opt,name=private,proto3" json:"private,omitempty"`
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,5,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetVisibilityRequest) Reset()         { *m = SetVisibilityRequest{} }
+func (m *SetVisibilityRequest) String() string { return proto.CompactTextString(m) }
+func (*SetVisibilityRequest) ProtoMessage()    {}
+func (*SetVisibilityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{11}
+}
+
+func (m *SetVisibilityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetVisibilityRequest.Unmarshal(m, b)
+}
+func (m *SetVisibilityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetVisibilityRequest.Marshal(b, m, deterministic)
+}
+func (m *SetVisibilityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetVisibilityRequest.Merge(m, src)
+}
+func (m *SetVisibilityRequest) XXX_Size() int {
+	return xxx_messageInfo_SetVisibilityRequest.Size(m)
+}
+func (m *SetVisibilityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetVisibilityRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetVisibilityRequest proto.InternalMessageInfo
+
+func (m *SetVisibilityRequest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *SetVisibilityRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SetVisibilityRequest) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *SetVisibilityRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SetVisibilityRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+type HistoryRequest struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryRequest) Reset()         { *m = HistoryRequest{} }
+func (m *HistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*HistoryRequest) ProtoMessage()    {}
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{12}
+}
+
+func (m *HistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryRequest.Unmarshal(m, b)
+}
+func (m *HistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *HistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryRequest.Merge(m, src)
+}
+func (m *HistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_HistoryRequest.Size(m)
+}
+func (m *HistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryRequest proto.InternalMessageInfo
+
+func (m *HistoryRequest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *HistoryRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type Revision struct {
+	Version   uint64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Data      string `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Owner     string `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	Signer    string `protobuf:"bytes,4,opt,name=signer,proto3" json:"signer,omitempty"`
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	Timestamp uint64 `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Deleted   bool   `protobuf:"varint,7,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// transferred is true if the revision is a transfer from the signer to the owner.
+	Transferred        bool   `protobuf:"varint,8,opt,name=transferred,proto3" json:"transferred,omitempty"`
+	RecipientSignature []byte `protobuf:"bytes,9,opt,name=recipientSignature,proto3" json:"recipientSignature,omitempty"`
+	// expiresAt is the expiry of the object after the revision in unix nanoseconds, or 0 if none.
+	ExpiresAt            uint64   `protobuf:"varint,10,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Revision) Reset()         { *m = Revision{} }
+func (m *Revision) String() string { return proto.CompactTextString(m) }
+func (*Revision) ProtoMessage()    {}
+func (*Revision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{13}
+}
+
+func (m *Revision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Revision.Unmarshal(m, b)
+}
+func (m *Revision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Revision.Marshal(b, m, deterministic)
+}
+func (m *Revision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Revision.Merge(m, src)
+}
+func (m *Revision) XXX_Size() int {
+	return xxx_messageInfo_Revision.Size(m)
+}
+func (m *Revision) XXX_DiscardUnknown() {
+	xxx_messageInfo_Revision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Revision proto.InternalMessageInfo
+
+func (m *Revision) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Revision) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+func (m *Revision) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *Revision) GetSigner() string {
+	if m != nil {
+		return m.Signer
+	}
+	return ""
+}
+
+func (m *Revision) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Revision) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Revision) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+func (m *Revision) GetTransferred() bool {
+	if m != nil {
+		return m.Transferred
+	}
+	return false
+}
+
+func (m *Revision) GetRecipientSignature() []byte {
+	if m != nil {
+		return m.RecipientSignature
+	}
+	return nil
+}
+
+func (m *Revision) GetExpiresAt() uint64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+type HistoryResponse struct {
+	Revisions            []*Revision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *HistoryResponse) Reset()         { *m = HistoryResponse{} }
+func (m *HistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*HistoryResponse) ProtoMessage()    {}
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{14}
+}
+
+func (m *HistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryResponse.Unmarshal(m, b)
+}
+func (m *HistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *HistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryResponse.Merge(m, src)
+}
+func (m *HistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_HistoryResponse.Size(m)
+}
+func (m *HistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryResponse proto.InternalMessageInfo
+
+func (m *HistoryResponse) GetRevisions() []*Revision {
+	if m != nil {
+		return m.Revisions
+	}
+	return nil
+}
+
+type ObjectKey struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ObjectKey) Reset()         { *m = ObjectKey{} }
+func (m *ObjectKey) String() string { return proto.CompactTextString(m) }
+func (*ObjectKey) ProtoMessage()    {}
+func (*ObjectKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{15}
+}
+
+func (m *ObjectKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ObjectKey.Unmarshal(m, b)
+}
+func (m *ObjectKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ObjectKey.Marshal(b, m, deterministic)
+}
+func (m *ObjectKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObjectKey.Merge(m, src)
+}
+func (m *ObjectKey) XXX_Size() int {
+	return xxx_messageInfo_ObjectKey.Size(m)
+}
+func (m *ObjectKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObjectKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObjectKey proto.InternalMessageInfo
+
+func (m *ObjectKey) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ObjectKey) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// ItemError is an error of an item in a batch, with a gRPC status code.
+type ItemError struct {
+	Code    uint32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// retryAfter is the seconds to wait before retrying the item, if it has been rate-limited.
+	RetryAfter           uint64   `protobuf:"varint,3,opt,name=retryAfter,proto3" json:"retryAfter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ItemError) Reset()         { *m = ItemError{} }
+func (m *ItemError) String() string { return proto.CompactTextString(m) }
+func (*ItemError) ProtoMessage()    {}
+func (*ItemError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{16}
+}
+
+func (m *ItemError) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ItemError.Unmarshal(m, b)
+}
+func (m *ItemError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ItemError.Marshal(b, m, deterministic)
+}
+func (m *ItemError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ItemError.Merge(m, src)
+}
+func (m *ItemError) XXX_Size() int {
+	return xxx_messageInfo_ItemError.Size(m)
+}
+func (m *ItemError) XXX_DiscardUnknown() {
+	xxx_messageInfo_ItemError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ItemError proto.InternalMessageInfo
+
+func (m *ItemError) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *ItemError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *ItemError) GetRetryAfter() uint64 {
+	if m != nil {
+		return m.RetryAfter
+	}
+	return 0
+}
+
+type BatchGetRequest struct {
+	// keys can have up to 100 objects, without duplicates.
+	Keys                 []*ObjectKey `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	IncludeDeleted       bool         `protobuf:"varint,2,opt,name=includeDeleted,proto3" json:"includeDeleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BatchGetRequest) Reset()         { *m = BatchGetRequest{} }
+func (m *BatchGetRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchGetRequest) ProtoMessage()    {}
+func (*BatchGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{17}
+}
+
+func (m *BatchGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchGetRequest.Unmarshal(m, b)
+}
+func (m *BatchGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchGetRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchGetRequest.Merge(m, src)
+}
+func (m *BatchGetRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchGetRequest.Size(m)
+}
+func (m *BatchGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchGetRequest proto.InternalMessageInfo
+
+func (m *BatchGetRequest) GetKeys() []*ObjectKey {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *BatchGetRequest) GetIncludeDeleted() bool {
+	if m != nil {
+		return m.IncludeDeleted
+	}
+	return false
+}
+
+type BatchGetResult struct {
+	// either object or error is given.
+	Object               *GetResponse `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Error                *ItemError   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BatchGetResult) Reset()         { *m = BatchGetResult{} }
+func (m *BatchGetResult) String() string { return proto.CompactTextString(m) }
+func (*BatchGetResult) ProtoMessage()    {}
+func (*BatchGetResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{18}
+}
+
+func (m *BatchGetResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchGetResult.Unmarshal(m, b)
+}
+func (m *BatchGetResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchGetResult.Marshal(b, m, deterministic)
+}
+func (m *BatchGetResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchGetResult.Merge(m, src)
+}
+func (m *BatchGetResult) XXX_Size() int {
+	return xxx_messageInfo_BatchGetResult.Size(m)
+}
+func (m *BatchGetResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchGetResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchGetResult proto.InternalMessageInfo
+
+func (m *BatchGetResult) GetObject() *GetResponse {
+	if m != nil {
+		return m.Object
+	}
+	return nil
+}
+
+func (m *BatchGetResult) GetError() *ItemError {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type BatchGetResponse struct {
+	// results are in the same order with the keys.
+	Results              []*BatchGetResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *BatchGetResponse) Reset()         { *m = BatchGetResponse{} }
+func (m *BatchGetResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchGetResponse) ProtoMessage()    {}
+func (*BatchGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{19}
+}
+
+func (m *BatchGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchGetResponse.Unmarshal(m, b)
+}
+func (m *BatchGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchGetResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchGetResponse.Merge(m, src)
+}
+func (m *BatchGetResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchGetResponse.Size(m)
+}
+func (m *BatchGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchGetResponse proto.InternalMessageInfo
+
+func (m *BatchGetResponse) GetResults() []*BatchGetResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type BatchPutRequest struct {
+	// items can have up to 100 objects, without duplicates.
+	// Each item is signed like PutObject, and fails independently.
+	Items                []*PutRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *BatchPutRequest) Reset()         { *m = BatchPutRequest{} }
+func (m *BatchPutRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchPutRequest) ProtoMessage()    {}
+func (*BatchPutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{20}
+}
+
+func (m *BatchPutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchPutRequest.Unmarshal(m, b)
+}
+func (m *BatchPutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchPutRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchPutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchPutRequest.Merge(m, src)
+}
+func (m *BatchPutRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchPutRequest.Size(m)
+}
+func (m *BatchPutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchPutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchPutRequest proto.InternalMessageInfo
+
+func (m *BatchPutRequest) GetItems() []*PutRequest {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+type BatchPutResult struct {
+	// either result or error is given.
+	Result               *PutResponse `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Error                *ItemError   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BatchPutResult) Reset()         { *m = BatchPutResult{} }
+func (m *BatchPutResult) String() string { return proto.CompactTextString(m) }
+func (*BatchPutResult) ProtoMessage()    {}
+func (*BatchPutResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{21}
+}
+
+func (m *BatchPutResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchPutResult.Unmarshal(m, b)
+}
+func (m *BatchPutResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchPutResult.Marshal(b, m, deterministic)
+}
+func (m *BatchPutResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchPutResult.Merge(m, src)
+}
+func (m *BatchPutResult) XXX_Size() int {
+	return xxx_messageInfo_BatchPutResult.Size(m)
+}
+func (m *BatchPutResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchPutResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchPutResult proto.InternalMessageInfo
+
+func (m *BatchPutResult) GetResult() *PutResponse {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *BatchPutResult) GetError() *ItemError {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type BatchPutResponse struct {
+	// results are in the same order with the items.
+	Results              []*BatchPutResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *BatchPutResponse) Reset()         { *m = BatchPutResponse{} }
+func (m *BatchPutResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchPutResponse) ProtoMessage()    {}
+func (*BatchPutResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{22}
+}
+
+func (m *BatchPutResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchPutResponse.Unmarshal(m, b)
+}
+func (m *BatchPutResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchPutResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchPutResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchPutResponse.Merge(m, src)
+}
+func (m *BatchPutResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchPutResponse.Size(m)
+}
+func (m *BatchPutResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchPutResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchPutResponse proto.InternalMessageInfo
+
+func (m *BatchPutResponse) GetResults() []*BatchPutResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type TxOp struct {
+	// op is either "put", "delete" or "check". A check only checks that the current version
+	// of the object including deleted ones is expectedVersion, where 0 means it has never existed.
+	Op   string `protobuf:"bytes,1,opt,name=op,proto3" json:"op,omitempty"`
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Id   string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	// data is only for puts, and signature is for puts and deletes
+	// which are signed like PutObject and DeleteObject.
+	Data                 string   `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Signature            []byte   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,6,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	Private              bool     `protobuf:"varint,7,opt,name=private,proto3" json:"private,omitempty"`
+	ExpiresAt            uint64   `protobuf:"varint,8,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxOp) Reset()         { *m = TxOp{} }
+func (m *TxOp) String() string { return proto.CompactTextString(m) }
+func (*TxOp) ProtoMessage()    {}
+func (*TxOp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{23}
+}
+
+func (m *TxOp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxOp.Unmarshal(m, b)
+}
+func (m *TxOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxOp.Marshal(b, m, deterministic)
+}
+func (m *TxOp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxOp.Merge(m, src)
+}
+func (m *TxOp) XXX_Size() int {
+	return xxx_messageInfo_TxOp.Size(m)
+}
+func (m *TxOp) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxOp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxOp proto.InternalMessageInfo
+
+func (m *TxOp) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
+}
+
+func (m *TxOp) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *TxOp) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TxOp) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+func (m *TxOp) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *TxOp) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+func (m *TxOp) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *TxOp) GetExpiresAt() uint64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+type TransactRequest struct {
+	// ops can have up to 12 operations, without duplicated objects.
+	Ops                  []*TxOp  `protobuf:"bytes,1,rep,name=ops,proto3" json:"ops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactRequest) Reset()         { *m = TransactRequest{} }
+func (m *TransactRequest) String() string { return proto.CompactTextString(m) }
+func (*TransactRequest) ProtoMessage()    {}
+func (*TransactRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{24}
+}
+
+func (m *TransactRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactRequest.Unmarshal(m, b)
+}
+func (m *TransactRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactRequest.Marshal(b, m, deterministic)
+}
+func (m *TransactRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactRequest.Merge(m, src)
+}
+func (m *TransactRequest) XXX_Size() int {
+	return xxx_messageInfo_TransactRequest.Size(m)
+}
+func (m *TransactRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactRequest proto.InternalMessageInfo
+
+func (m *TransactRequest) GetOps() []*TxOp {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
+}
+
+type TransactResponse struct {
+	// results are in the same order with the ops. Results of checks are empty.
+	Results              []*PutResponse `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *TransactResponse) Reset()         { *m = TransactResponse{} }
+func (m *TransactResponse) String() string { return proto.CompactTextString(m) }
+func (*TransactResponse) ProtoMessage()    {}
+func (*TransactResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{25}
+}
+
+func (m *TransactResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactResponse.Unmarshal(m, b)
+}
+func (m *TransactResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactResponse.Marshal(b, m, deterministic)
+}
+func (m *TransactResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactResponse.Merge(m, src)
+}
+func (m *TransactResponse) XXX_Size() int {
+	return xxx_messageInfo_TransactResponse.Size(m)
+}
+func (m *TransactResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactResponse proto.InternalMessageInfo
+
+func (m *TransactResponse) GetResults() []*PutResponse {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// Schema is a JSON Schema of a type, which every write of the objects of the type
+// is validated against. Writes violating it fail with INVALID_ARGUMENT.
+type Schema struct {
+	Type       string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Version    uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Definition string `protobuf:"bytes,3,opt,name=definition,proto3" json:"definition,omitempty"`
+	// signer is the hex address of the admin who set the schema.
+	Signer               string   `protobuf:"bytes,4,opt,name=signer,proto3" json:"signer,omitempty"`
+	CreatedAt            uint64   `protobuf:"varint,5,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Schema) Reset()         { *m = Schema{} }
+func (m *Schema) String() string { return proto.CompactTextString(m) }
+func (*Schema) ProtoMessage()    {}
+func (*Schema) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{26}
+}
+
+func (m *Schema) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Schema.Unmarshal(m, b)
+}
+func (m *Schema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Schema.Marshal(b, m, deterministic)
+}
+func (m *Schema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schema.Merge(m, src)
+}
+func (m *Schema) XXX_Size() int {
+	return xxx_messageInfo_Schema.Size(m)
+}
+func (m *Schema) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schema proto.InternalMessageInfo
+
+func (m *Schema) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Schema) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Schema) GetDefinition() string {
+	if m != nil {
+		return m.Definition
+	}
+	return ""
+}
+
+func (m *Schema) GetSigner() string {
+	if m != nil {
+		return m.Signer
+	}
+	return ""
+}
+
+func (m *Schema) GetCreatedAt() uint64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type SetSchemaRequest struct {
+	Type       string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Definition string `protobuf:"bytes,2,opt,name=definition,proto3" json:"definition,omitempty"`
+	// signature should be made by an admin for the next version of the schema.
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,4,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetSchemaRequest) Reset()         { *m = SetSchemaRequest{} }
+func (m *SetSchemaRequest) String() string { return proto.CompactTextString(m) }
+func (*SetSchemaRequest) ProtoMessage()    {}
+func (*SetSchemaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{27}
+}
+
+func (m *SetSchemaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetSchemaRequest.Unmarshal(m, b)
+}
+func (m *SetSchemaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetSchemaRequest.Marshal(b, m, deterministic)
+}
+func (m *SetSchemaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetSchemaRequest.Merge(m, src)
+}
+func (m *SetSchemaRequest) XXX_Size() int {
+	return xxx_messageInfo_SetSchemaRequest.Size(m)
+}
+func (m *SetSchemaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetSchemaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetSchemaRequest proto.InternalMessageInfo
+
+func (m *SetSchemaRequest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *SetSchemaRequest) GetDefinition() string {
+	if m != nil {
+		return m.Definition
+	}
+	return ""
+}
+
+func (m *SetSchemaRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SetSchemaRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+type GetSchemaRequest struct {
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// version is the version of the schema to get. The latest one is returned if it is 0.
+	Version              uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetSchemaRequest) Reset()         { *m = GetSchemaRequest{} }
+func (m *GetSchemaRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSchemaRequest) ProtoMessage()    {}
+func (*GetSchemaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{28}
+}
+
+func (m *GetSchemaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSchemaRequest.Unmarshal(m, b)
+}
+func (m *GetSchemaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSchemaRequest.Marshal(b, m, deterministic)
+}
+func (m *GetSchemaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSchemaRequest.Merge(m, src)
+}
+func (m *GetSchemaRequest) XXX_Size() int {
+	return xxx_messageInfo_GetSchemaRequest.Size(m)
+}
+func (m *GetSchemaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSchemaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSchemaRequest proto.InternalMessageInfo
+
+func (m *GetSchemaRequest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *GetSchemaRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type ListSchemasRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSchemasRequest) Reset()         { *m = ListSchemasRequest{} }
+func (m *ListSchemasRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSchemasRequest) ProtoMessage()    {}
+func (*ListSchemasRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{29}
+}
+
+func (m *ListSchemasRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSchemasRequest.Unmarshal(m, b)
+}
+func (m *ListSchemasRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSchemasRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSchemasRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSchemasRequest.Merge(m, src)
+}
+func (m *ListSchemasRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSchemasRequest.Size(m)
+}
+func (m *ListSchemasRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSchemasRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSchemasRequest proto.InternalMessageInfo
+
+type ListSchemasResponse struct {
+	Schemas              []*Schema `protobuf:"bytes,1,rep,name=schemas,proto3" json:"schemas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListSchemasResponse) Reset()         { *m = ListSchemasResponse{} }
+func (m *ListSchemasResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSchemasResponse) ProtoMessage()    {}
+func (*ListSchemasResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{30}
+}
+
+func (m *ListSchemasResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSchemasResponse.Unmarshal(m, b)
+}
+func (m *ListSchemasResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSchemasResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSchemasResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSchemasResponse.Merge(m, src)
+}
+func (m *ListSchemasResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSchemasResponse.Size(m)
+}
+func (m *ListSchemasResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSchemasResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSchemasResponse proto.InternalMessageInfo
+
+func (m *ListSchemasResponse) GetSchemas() []*Schema {
+	if m != nil {
+		return m.Schemas
+	}
+	return nil
+}
+
+// Usage is the fees charged to an owner and the prepaid quota deposited by the admins.
+// If the quota is enforced, writes which the payer cannot afford fail with RESOURCE_EXHAUSTED.
+type Usage struct {
+	// owner is the hex address of the owner.
+	Owner                string   `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Writes               uint64   `protobuf:"varint,2,opt,name=writes,proto3" json:"writes,omitempty"`
+	FeeUsed              uint64   `protobuf:"varint,3,opt,name=feeUsed,proto3" json:"feeUsed,omitempty"`
+	Deposited            uint64   `protobuf:"varint,4,opt,name=deposited,proto3" json:"deposited,omitempty"`
+	Deposits             uint64   `protobuf:"varint,5,opt,name=deposits,proto3" json:"deposits,omitempty"`
+	Balance              uint64   `protobuf:"varint,6,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Usage) Reset()         { *m = Usage{} }
+func (m *Usage) String() string { return proto.CompactTextString(m) }
+func (*Usage) ProtoMessage()    {}
+func (*Usage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{31}
+}
+
+func (m *Usage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Usage.Unmarshal(m, b)
+}
+func (m *Usage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Usage.Marshal(b, m, deterministic)
+}
+func (m *Usage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Usage.Merge(m, src)
+}
+func (m *Usage) XXX_Size() int {
+	return xxx_messageInfo_Usage.Size(m)
+}
+func (m *Usage) XXX_DiscardUnknown() {
+	xxx_messageInfo_Usage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Usage proto.InternalMessageInfo
+
+func (m *Usage) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *Usage) GetWrites() uint64 {
+	if m != nil {
+		return m.Writes
+	}
+	return 0
+}
+
+func (m *Usage) GetFeeUsed() uint64 {
+	if m != nil {
+		return m.FeeUsed
+	}
+	return 0
+}
+
+func (m *Usage) GetDeposited() uint64 {
+	if m != nil {
+		return m.Deposited
+	}
+	return 0
+}
+
+func (m *Usage) GetDeposits() uint64 {
+	if m != nil {
+		return m.Deposits
+	}
+	return 0
+}
+
+func (m *Usage) GetBalance() uint64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+// GetUsageRequest requires a read token of the owner or an admin.
+type GetUsageRequest struct {
+	Owner                string   `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetUsageRequest) Reset()         { *m = GetUsageRequest{} }
+func (m *GetUsageRequest) String() string { return proto.CompactTextString(m) }
+func (*GetUsageRequest) ProtoMessage()    {}
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{32}
+}
+
+func (m *GetUsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUsageRequest.Unmarshal(m, b)
+}
+func (m *GetUsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUsageRequest.Marshal(b, m, deterministic)
+}
+func (m *GetUsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUsageRequest.Merge(m, src)
+}
+func (m *GetUsageRequest) XXX_Size() int {
+	return xxx_messageInfo_GetUsageRequest.Size(m)
+}
+func (m *GetUsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUsageRequest proto.InternalMessageInfo
+
+func (m *GetUsageRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+type DepositRequest struct {
+	Owner  string `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Amount uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// signature should be made by an admin for the next deposit of the owner.
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DepositRequest) Reset()         { *m = DepositRequest{} }
+func (m *DepositRequest) String() string { return proto.CompactTextString(m) }
+func (*DepositRequest) ProtoMessage()    {}
+func (*DepositRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ecf0878b123623e2, []int{33}
+}
+
+func (m *DepositRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DepositRequest.Unmarshal(m, b)
+}
+func (m *DepositRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DepositRequest.Marshal(b, m, deterministic)
+}
+func (m *DepositRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DepositRequest.Merge(m, src)
+}
+func (m *DepositRequest) XXX_Size() int {
+	return xxx_messageInfo_DepositRequest.Size(m)
+}
+func (m *DepositRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DepositRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DepositRequest proto.InternalMessageInfo
+
+func (m *DepositRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *DepositRequest) GetAmount() uint64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *DepositRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*GetRequest)(nil), "GetRequest")
+	proto.RegisterType((*GetResponse)(nil), "GetResponse")
+	proto.RegisterType((*QueryRequest)(nil), "QueryRequest")
+	proto.RegisterType((*QueryResponse)(nil), "QueryResponse")
+	proto.RegisterType((*PutRequest)(nil), "PutRequest")
+	proto.RegisterType((*PutResponse)(nil), "PutResponse")
+	proto.RegisterType((*PatchRequest)(nil), "PatchRequest")
+	proto.RegisterType((*DeleteRequest)(nil), "DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "DeleteResponse")
+	proto.RegisterType((*TransferRequest)(nil), "TransferRequest")
+	proto.RegisterType((*SetACLRequest)(nil), "SetACLRequest")
+	proto.RegisterType((*SetVisibilityRequest)(nil), "SetVisibilityRequest")
+	proto.RegisterType((*HistoryRequest)(nil), "HistoryRequest")
+	proto.RegisterType((*Revision)(nil), "Revision")
+	proto.RegisterType((*HistoryResponse)(nil), "HistoryResponse")
+	proto.RegisterType((*ObjectKey)(nil), "ObjectKey")
+	proto.RegisterType((*ItemError)(nil), "ItemError")
+	proto.RegisterType((*BatchGetRequest)(nil), "BatchGetRequest")
+	proto.RegisterType((*BatchGetResult)(nil), "BatchGetResult")
+	proto.RegisterType((*BatchGetResponse)(nil), "BatchGetResponse")
+	proto.RegisterType((*BatchPutRequest)(nil), "BatchPutRequest")
+	proto.RegisterType((*BatchPutResult)(nil), "BatchPutResult")
+	proto.RegisterType((*BatchPutResponse)(nil), "BatchPutResponse")
+	proto.RegisterType((*TxOp)(nil), "TxOp")
+	proto.RegisterType((*TransactRequest)(nil), "TransactRequest")
+	proto.RegisterType((*TransactResponse)(nil), "TransactResponse")
+	proto.RegisterType((*Schema)(nil), "Schema")
+	proto.RegisterType((*SetSchemaRequest)(nil), "SetSchemaRequest")
+	proto.RegisterType((*GetSchemaRequest)(nil), "GetSchemaRequest")
+	proto.RegisterType((*ListSchemasRequest)(nil), "ListSchemasRequest")
+	proto.RegisterType((*ListSchemasResponse)(nil), "ListSchemasResponse")
+	proto.RegisterType((*Usage)(nil), "Usage")
+	proto.RegisterType((*GetUsageRequest)(nil), "GetUsageRequest")
+	proto.RegisterType((*DepositRequest)(nil), "DepositRequest")
+}
+
+func init() { proto.RegisterFile("proto/api.proto", fileDescriptor_ecf0878b123623e2) }
+
+var fileDescriptor_ecf0878b123623e2 = []byte{
+	// 1479 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x5b, 0x6f, 0x1b, 0xc5,
+	0x17, 0xf7, 0xfa, 0xbe, 0xc7, 0x89, 0xed, 0x4c, 0xf3, 0xef, 0xdf, 0xb2, 0xa2, 0xca, 0x1d, 0x55,
+	0xad, 0x8b, 0xd0, 0x54, 0x94, 0x02, 0x55, 0x25, 0x24, 0xd2, 0x82, 0x42, 0x45, 0xa5, 0xba, 0x9b,
+	0xb6, 0x50, 0xe0, 0x65, 0xe3, 0x9d, 0xb4, 0x4b, 0x6d, 0xef, 0x76, 0x66, 0xb6, 0x4d, 0xde, 0x79,
+	0x44, 0x7c, 0x01, 0x78, 0x83, 0x47, 0x04, 0x1f, 0x02, 0x9e, 0xf9, 0x22, 0x7c, 0x09, 0x34, 0xb7,
+	0xbd, 0xd9, 0x71, 0x6b, 0xd4, 0xa7, 0xec, 0xb9, 0xcc, 0x99, 0x73, 0x9b, 0x73, 0x7e, 0x0e, 0xf4,
+	0x62, 0x16, 0x89, 0xe8, 0x9a, 0x1f, 0x87, 0x44, 0x7d, 0xe1, 0xef, 0x1d, 0x80, 0x03, 0x2a, 0x3c,
+	0xfa, 0x22, 0xa1, 0x5c, 0x20, 0x04, 0x75, 0x71, 0x1a, 0xd3, 0x81, 0x33, 0x72, 0xc6, 0xae, 0xa7,
+	0xbe, 0x51, 0x17, 0xaa, 0x61, 0x30, 0xa8, 0x2a, 0x4e, 0x35, 0x0c, 0xd0, 0x65, 0xe8, 0x86, 0x8b,
+	0xe9, 0x2c, 0x09, 0xe8, 0xa7, 0x74, 0x46, 0x05, 0x0d, 0x06, 0xb5, 0x91, 0x33, 0x6e, 0x7b, 0x25,
+	0x2e, 0x1a, 0x42, 0x9b, 0xd1, 0x97, 0x21, 0x0f, 0xa3, 0xc5, 0xa0, 0x3e, 0x72, 0xc6, 0x75, 0x2f,
+	0xa5, 0xa5, 0x4d, 0x5f, 0x0c, 0x1a, 0x8a, 0x5b, 0xf5, 0x05, 0xfe, 0xa9, 0x0a, 0x1d, 0xe5, 0x06,
+	0x8f, 0xa3, 0x05, 0xa7, 0xd2, 0x8f, 0xc0, 0x17, 0xbe, 0xf5, 0x43, 0x7e, 0xa3, 0x5d, 0x68, 0x44,
+	0xaf, 0x16, 0x94, 0x19, 0x57, 0x34, 0x81, 0xf6, 0xc0, 0x9d, 0x32, 0xea, 0x0b, 0x1a, 0xec, 0x0b,
+	0xe5, 0x48, 0xdd, 0xcb, 0x18, 0xe8, 0x12, 0x6c, 0xcf, 0x7c, 0x2e, 0x1e, 0xc5, 0x81, 0xd1, 0xd0,
+	0x8e, 0x14, 0x99, 0x68, 0x00, 0xad, 0x97, 0x94, 0x29, 0x47, 0xb5, 0x4b, 0x96, 0x94, 0x92, 0xc0,
+	0x04, 0xd9, 0x54, 0x41, 0x5a, 0x52, 0x4a, 0x5e, 0xb1, 0x50, 0x50, 0xc6, 0x07, 0xad, 0x51, 0x6d,
+	0xec, 0x7a, 0x96, 0x94, 0x12, 0x46, 0xfd, 0x40, 0x4a, 0xda, 0x5a, 0x62, 0x48, 0x29, 0x89, 0x59,
+	0xf8, 0xd2, 0x17, 0x74, 0xe0, 0x6a, 0x6b, 0x86, 0x94, 0x51, 0xd0, 0x93, 0x38, 0x64, 0x94, 0xef,
+	0x8b, 0x01, 0xe8, 0x28, 0x52, 0x06, 0xfe, 0xd5, 0x81, 0xad, 0x07, 0x09, 0x65, 0xa7, 0xeb, 0xca,
+	0xb4, 0x0b, 0x8d, 0x17, 0x52, 0xc7, 0xa6, 0x47, 0x11, 0x52, 0x93, 0x3f, 0x0f, 0x63, 0x93, 0x19,
+	0xf5, 0x2d, 0x35, 0x67, 0xe1, 0x3c, 0xb4, 0xc9, 0xd0, 0x84, 0xd2, 0x8c, 0x98, 0x2e, 0x8a, 0xeb,
+	0xa9, 0x6f, 0x95, 0x72, 0x16, 0x50, 0x36, 0x68, 0x9a, 0x94, 0x4b, 0x02, 0x9d, 0x87, 0xe6, 0x34,
+	0x61, 0x3c, 0x62, 0x83, 0x96, 0x62, 0x1b, 0x0a, 0x7f, 0x09, 0xdb, 0xc6, 0x4b, 0x53, 0xc5, 0xcb,
+	0x32, 0x13, 0x3c, 0x99, 0x09, 0x3e, 0x70, 0x46, 0xb5, 0x71, 0xe7, 0xfa, 0x16, 0xc9, 0x15, 0xd9,
+	0xb3, 0x42, 0x74, 0x01, 0x60, 0x41, 0x4f, 0xc4, 0x1d, 0x6d, 0x54, 0xfb, 0x9f, 0xe3, 0xe0, 0xbf,
+	0x1c, 0x80, 0x49, 0xb2, 0x51, 0x93, 0xda, 0x06, 0xaa, 0xe5, 0x1a, 0x68, 0x0f, 0x5c, 0x1e, 0x3e,
+	0x5d, 0xf8, 0x22, 0x61, 0x54, 0xc5, 0xbe, 0xe5, 0x65, 0x0c, 0x34, 0x86, 0x1e, 0x3d, 0x89, 0xe9,
+	0x54, 0xd0, 0xe0, 0x71, 0xa1, 0x19, 0xca, 0xec, 0x7c, 0x19, 0x9b, 0x6b, 0xca, 0xd8, 0x2a, 0x97,
+	0xf1, 0x1b, 0xe8, 0x4c, 0x92, 0x34, 0x7c, 0x69, 0xc6, 0x34, 0xaa, 0x8a, 0xa4, 0xed, 0x59, 0x52,
+	0x4a, 0x8e, 0x29, 0x7d, 0xc4, 0xa9, 0x8e, 0xa8, 0xee, 0x59, 0x32, 0xdf, 0xa9, 0xb5, 0x42, 0xa7,
+	0xe2, 0xdf, 0x1d, 0xd8, 0x9a, 0xf8, 0x62, 0xfa, 0x6c, 0x93, 0x2c, 0xed, 0x81, 0x1b, 0xcb, 0x33,
+	0x0f, 0xa5, 0xa2, 0x4e, 0x55, 0xc6, 0x90, 0xd5, 0x57, 0x84, 0xca, 0x95, 0xeb, 0x69, 0xa2, 0x98,
+	0xc5, 0xc6, 0x1b, 0x64, 0xb1, 0xb9, 0x32, 0x8b, 0xf8, 0x01, 0x6c, 0xeb, 0x49, 0xb1, 0xa1, 0xc3,
+	0xd9, 0xe5, 0xb5, 0xd2, 0xe5, 0xb8, 0x0f, 0x5d, 0x6b, 0x52, 0xe7, 0x18, 0xff, 0xe3, 0x40, 0xef,
+	0x21, 0xf3, 0x17, 0xfc, 0x98, 0xb2, 0x4d, 0xee, 0x19, 0x42, 0x7b, 0x41, 0x5f, 0xdd, 0x57, 0xe3,
+	0x46, 0xe7, 0x25, 0xa5, 0xd1, 0x08, 0x3a, 0xf6, 0xfb, 0x0b, 0x7a, 0x6a, 0x1a, 0x29, 0xcf, 0x7a,
+	0x4d, 0x8a, 0x08, 0x20, 0x46, 0xa7, 0x61, 0x1c, 0xd2, 0x85, 0x38, 0x4c, 0xd5, 0x9a, 0x4a, 0x6d,
+	0x85, 0x64, 0x55, 0x4a, 0x5b, 0xab, 0x53, 0xfa, 0x87, 0x03, 0xdb, 0x87, 0x54, 0xec, 0xdf, 0xb9,
+	0xb7, 0x49, 0xac, 0xb9, 0x49, 0x56, 0x3b, 0x73, 0x92, 0xd5, 0x8b, 0x93, 0xec, 0x6d, 0x35, 0xc1,
+	0xcf, 0x0e, 0xec, 0x1e, 0x52, 0xf1, 0x38, 0xe4, 0xe1, 0x51, 0x38, 0x0b, 0xc5, 0xe9, 0x86, 0x8e,
+	0xdb, 0x77, 0x58, 0x5b, 0x7a, 0x87, 0x6f, 0xe3, 0xa5, 0xe3, 0x1b, 0xd0, 0xfd, 0x3c, 0xe4, 0x22,
+	0x62, 0x9b, 0xf8, 0x85, 0x7f, 0xab, 0x42, 0xdb, 0xb3, 0x9b, 0x2e, 0xf7, 0x62, 0x9d, 0xe2, 0x6e,
+	0xb1, 0x23, 0xaa, 0xba, 0x6a, 0xc7, 0xd5, 0xf2, 0x3b, 0xee, 0x3c, 0x34, 0xa5, 0xf7, 0x94, 0x99,
+	0x97, 0x68, 0xa8, 0xd7, 0x54, 0x61, 0x0f, 0x5c, 0x11, 0xce, 0x29, 0x17, 0xfe, 0x3c, 0x36, 0xf9,
+	0xcf, 0x18, 0xf9, 0xcd, 0xd6, 0x2a, 0x6e, 0xb6, 0x11, 0x74, 0x84, 0x79, 0x32, 0x8c, 0x06, 0x83,
+	0xb6, 0x92, 0xe6, 0x59, 0x67, 0x74, 0xb0, 0x7b, 0x66, 0x07, 0xaf, 0xdf, 0x6e, 0xb7, 0xa0, 0x97,
+	0x26, 0xd9, 0x8c, 0xc6, 0x2b, 0xe0, 0x5a, 0xa8, 0x60, 0x57, 0x87, 0x4b, 0x6c, 0x4a, 0xbd, 0x4c,
+	0x86, 0xaf, 0x81, 0x7b, 0xff, 0xe8, 0x3b, 0x3a, 0x15, 0xf2, 0xd9, 0xbd, 0x49, 0x6d, 0x9e, 0x80,
+	0x7b, 0x57, 0xd0, 0xf9, 0x67, 0x8c, 0x45, 0x4c, 0x1e, 0x98, 0x46, 0x81, 0x3e, 0xb0, 0xed, 0xa9,
+	0x6f, 0x99, 0x97, 0x39, 0xe5, 0xdc, 0x7f, 0x4a, 0xcd, 0x29, 0x4b, 0xca, 0x2d, 0xc5, 0xa8, 0x60,
+	0xa7, 0xfb, 0xc7, 0xc2, 0x14, 0xa8, 0xee, 0xe5, 0x38, 0xf8, 0x09, 0xf4, 0x6e, 0xcb, 0x09, 0x99,
+	0x83, 0x53, 0x17, 0xa0, 0xfe, 0x9c, 0x9e, 0xda, 0x10, 0x80, 0xa4, 0xbe, 0x7a, 0x8a, 0xbf, 0x02,
+	0x4a, 0x55, 0x57, 0x41, 0x29, 0xfc, 0x15, 0x74, 0x33, 0xd3, 0x72, 0x67, 0xa2, 0x4b, 0xd0, 0x8c,
+	0x94, 0x31, 0xe5, 0x7c, 0x79, 0xb3, 0x1a, 0x19, 0x1a, 0x41, 0x83, 0xca, 0x48, 0x95, 0x59, 0xe9,
+	0x40, 0x1a, 0xbb, 0xa7, 0x05, 0xf8, 0x63, 0xe8, 0xe7, 0x2c, 0xeb, 0xec, 0x5f, 0x2d, 0xaf, 0xed,
+	0x1e, 0x29, 0xde, 0x9e, 0x6e, 0x6e, 0x7c, 0xc3, 0xc4, 0x9c, 0xdb, 0xce, 0x17, 0xa1, 0x11, 0x0a,
+	0x3a, 0xb7, 0x67, 0x3b, 0x24, 0x93, 0x79, 0x5a, 0x92, 0x86, 0xa3, 0x24, 0x36, 0x1c, 0x6d, 0x32,
+	0x0d, 0x27, 0xb7, 0x29, 0x3d, 0x23, 0xdb, 0x20, 0x9c, 0x49, 0xf2, 0xfa, 0x70, 0x26, 0xc9, 0x52,
+	0x38, 0x7f, 0x3b, 0x50, 0x7f, 0x78, 0x72, 0x3f, 0x96, 0x6d, 0x13, 0xc5, 0xa6, 0x91, 0xaa, 0x51,
+	0x9c, 0xb6, 0x56, 0x75, 0xa9, 0xb5, 0x6a, 0x4b, 0x90, 0xa3, 0x7e, 0x16, 0xe4, 0xf8, 0xef, 0x73,
+	0x32, 0x3f, 0xea, 0x5a, 0x6b, 0x20, 0x47, 0xbb, 0xfc, 0xb6, 0xde, 0x31, 0xeb, 0xcf, 0x9f, 0xa6,
+	0xf5, 0xf9, 0x3f, 0xd4, 0xa2, 0xd8, 0xa6, 0xa2, 0x41, 0x64, 0xb8, 0x9e, 0xe4, 0xe0, 0x5b, 0xd0,
+	0xcf, 0x74, 0xcf, 0x46, 0x70, 0xf9, 0xc2, 0xa4, 0x89, 0xfb, 0xc1, 0x81, 0xe6, 0xe1, 0xf4, 0x19,
+	0x9d, 0xfb, 0x2b, 0x5f, 0x61, 0x6e, 0x08, 0x56, 0x8b, 0x43, 0xf0, 0x02, 0x40, 0x40, 0x8f, 0xc3,
+	0x45, 0x28, 0x2c, 0xa6, 0x71, 0xbd, 0x1c, 0x67, 0xdd, 0xe8, 0xcb, 0x60, 0x7f, 0xa3, 0x04, 0xfb,
+	0xf1, 0x8f, 0x0e, 0xf4, 0x0f, 0xa9, 0xd0, 0x1e, 0xad, 0x1b, 0xdd, 0xc5, 0xeb, 0xab, 0x4b, 0xd7,
+	0xaf, 0xc5, 0x1b, 0xab, 0xea, 0x57, 0x5f, 0xbd, 0x48, 0x3e, 0x81, 0xfe, 0xc1, 0x9b, 0xf8, 0x73,
+	0x66, 0xa2, 0xf0, 0x2e, 0xa0, 0x7b, 0x21, 0x37, 0x26, 0xb8, 0xb1, 0x81, 0x6f, 0xc2, 0xb9, 0x02,
+	0xd7, 0x94, 0xed, 0x22, 0xb4, 0xb8, 0x66, 0x99, 0xb2, 0xb5, 0x88, 0xb9, 0xdb, 0xf2, 0xf1, 0x2f,
+	0x0e, 0x34, 0x1e, 0xa9, 0xb9, 0x96, 0xee, 0x1c, 0xa7, 0xb4, 0x73, 0x14, 0x0c, 0xe0, 0xc6, 0x11,
+	0x43, 0xe5, 0xb1, 0x69, 0xad, 0x88, 0x4d, 0xf7, 0xc0, 0x0d, 0x68, 0x1c, 0xf1, 0x50, 0xce, 0x31,
+	0x9d, 0x87, 0x8c, 0x21, 0x11, 0x95, 0x21, 0xb8, 0xa9, 0x57, 0x4a, 0x4b, 0x9b, 0x47, 0xfe, 0xcc,
+	0x5f, 0x4c, 0xa9, 0xe9, 0x7f, 0x4b, 0xe2, 0x2b, 0xd0, 0x3b, 0xa0, 0x42, 0xf9, 0x69, 0xd3, 0xb6,
+	0xd2, 0x5d, 0xfc, 0xad, 0x84, 0x7e, 0xca, 0xdc, 0x5a, 0x3d, 0x19, 0x96, 0x3f, 0x8f, 0x92, 0x85,
+	0xb0, 0x61, 0x69, 0x6a, 0x7d, 0xa1, 0xaf, 0xff, 0xd9, 0x84, 0xda, 0xfe, 0xe4, 0x2e, 0x1a, 0x83,
+	0x7b, 0x40, 0x85, 0x9e, 0xe2, 0xa8, 0x43, 0xb2, 0x49, 0x3f, 0x2c, 0xcc, 0x5f, 0x5c, 0x41, 0x04,
+	0x3a, 0xea, 0xb7, 0x90, 0xd1, 0xdd, 0x26, 0xf9, 0xdf, 0x6f, 0xc3, 0x2e, 0x29, 0xfc, 0x50, 0xc2,
+	0x15, 0x69, 0x79, 0x92, 0x64, 0x96, 0xb3, 0x99, 0x39, 0x2c, 0xbc, 0x38, 0x5c, 0x41, 0xef, 0x42,
+	0x47, 0xe1, 0xfc, 0xd4, 0x72, 0x1e, 0xf5, 0x2f, 0x69, 0xbf, 0x07, 0x5b, 0x7a, 0x89, 0x18, 0xf5,
+	0x2e, 0x29, 0x80, 0xee, 0x61, 0x8f, 0x94, 0x10, 0x73, 0x05, 0x7d, 0x00, 0x3b, 0x16, 0x32, 0x2b,
+	0x44, 0xcb, 0x9f, 0x85, 0x31, 0xea, 0x93, 0x12, 0x8c, 0x5e, 0xba, 0x69, 0x0c, 0x4d, 0x8d, 0x3d,
+	0x51, 0x97, 0x14, 0x40, 0xe8, 0x92, 0xe6, 0x87, 0xb0, 0x5d, 0xc0, 0x7c, 0xe8, 0x7f, 0x64, 0x15,
+	0x06, 0x5c, 0x3a, 0xf7, 0x91, 0x7a, 0x44, 0x3a, 0x10, 0x83, 0x18, 0x50, 0x8f, 0x14, 0x01, 0xda,
+	0xb0, 0x4f, 0x4a, 0x60, 0x02, 0x57, 0xd0, 0xcd, 0x6c, 0x33, 0xeb, 0xd3, 0x1c, 0xf5, 0x49, 0x69,
+	0x57, 0x0f, 0x77, 0x48, 0x79, 0x11, 0xe6, 0x4e, 0x4e, 0x92, 0xf2, 0xc9, 0x49, 0x52, 0x3e, 0x59,
+	0x4e, 0x7c, 0xdb, 0x4e, 0x53, 0x9b, 0xbc, 0x6c, 0x08, 0x0f, 0x77, 0x48, 0x79, 0xd4, 0xe2, 0x0a,
+	0xba, 0x0a, 0x6e, 0x3a, 0xb4, 0xd0, 0x0e, 0x29, 0x0f, 0xb0, 0xa1, 0x7d, 0xc4, 0x5a, 0xf5, 0x20,
+	0xa7, 0x7a, 0xb0, 0x46, 0xf5, 0x16, 0x74, 0x72, 0x23, 0x02, 0x9d, 0x23, 0xcb, 0x63, 0x64, 0xb8,
+	0x4b, 0x56, 0x4c, 0x11, 0x5c, 0x41, 0x97, 0xa1, 0x6d, 0x9f, 0x1f, 0xea, 0x93, 0xd2, 0x4b, 0x1c,
+	0x36, 0x89, 0x22, 0x71, 0x05, 0x5d, 0x82, 0x96, 0x79, 0x7d, 0xa8, 0x47, 0xcc, 0xd7, 0x92, 0xd6,
+	0xed, 0xd6, 0xd7, 0x0d, 0xf5, 0x4f, 0xa7, 0xa3, 0xa6, 0xfa, 0xf3, 0xfe, 0xbf, 0x03, 0x00, 0x16,
+	0x02, 0xac, 0xb7, 0x8e, 0x12, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// APIClient is the client API for API service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type APIClient interface {
+	GetObject(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	QueryObject(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	PutObject(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	PatchObject(ctx context.Context, in *PatchRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	DeleteObject(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	TransferOwnership(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	SetACL(ctx context.Context, in *SetACLRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	SetVisibility(ctx context.Context, in *SetVisibilityRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	GetObjectHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+	BatchGetObjects(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error)
+	BatchPutObjects(ctx context.Context, in *BatchPutRequest, opts ...grpc.CallOption) (*BatchPutResponse, error)
+	// Transact commits the operations all-or-nothing. If any of them fails,
+	// nothing is written and the error message has the index of the failed operation.
+	Transact(ctx context.Context, in *TransactRequest, opts ...grpc.CallOption) (*TransactResponse, error)
+	SetSchema(ctx context.Context, in *SetSchemaRequest, opts ...grpc.CallOption) (*Schema, error)
+	GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*Schema, error)
+	ListSchemas(ctx context.Context, in *ListSchemasRequest, opts ...grpc.CallOption) (*ListSchemasResponse, error)
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*Usage, error)
+	Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*Usage, error)
+}
+
+type aPIClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAPIClient(cc *grpc.ClientConn) APIClient {
+	return &aPIClient{cc}
+}
+
+func (c *aPIClient) GetObject(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, "/API/GetObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) QueryObject(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, "/API/QueryObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) PutObject(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	out := new(PutResponse)
+	err := c.cc.Invoke(ctx, "/API/PutObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) PatchObject(ctx context.Context, in *PatchRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	out := new(PutResponse)
+	err := c.cc.Invoke(ctx, "/API/PatchObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) DeleteObject(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/API/DeleteObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) TransferOwnership(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	out := new(PutResponse)
+	err := c.cc.Invoke(ctx, "/API/TransferOwnership", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SetACL(ctx context.Context, in *SetACLRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	out := new(PutResponse)
+	err := c.cc.Invoke(ctx, "/API/SetACL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SetVisibility(ctx context.Context, in *SetVisibilityRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	out := new(PutResponse)
+	err := c.cc.Invoke(ctx, "/API/SetVisibility", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetObjectHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, "/API/GetObjectHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) BatchGetObjects(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error) {
+	out := new(BatchGetResponse)
+	err := c.cc.Invoke(ctx, "/API/BatchGetObjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) BatchPutObjects(ctx context.Context, in *BatchPutRequest, opts ...grpc.CallOption) (*BatchPutResponse, error) {
+	out := new(BatchPutResponse)
+	err := c.cc.Invoke(ctx, "/API/BatchPutObjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Transact(ctx context.Context, in *TransactRequest, opts ...grpc.CallOption) (*TransactResponse, error) {
+	out := new(TransactResponse)
+	err := c.cc.Invoke(ctx, "/API/Transact", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SetSchema(ctx context.Context, in *SetSchemaRequest, opts ...grpc.CallOption) (*Schema, error) {
+	out := new(Schema)
+	err := c.cc.Invoke(ctx, "/API/SetSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*Schema, error) {
+	out := new(Schema)
+	err := c.cc.Invoke(ctx, "/API/GetSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListSchemas(ctx context.Context, in *ListSchemasRequest, opts ...grpc.CallOption) (*ListSchemasResponse, error) {
+	out := new(ListSchemasResponse)
+	err := c.cc.Invoke(ctx, "/API/ListSchemas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*Usage, error) {
+	out := new(Usage)
+	err := c.cc.Invoke(ctx, "/API/GetUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*Usage, error) {
+	out := new(Usage)
+	err := c.cc.Invoke(ctx, "/API/Deposit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// APIServer is the server API for API service.
+type APIServer interface {
+	GetObject(context.Context, *GetRequest) (*GetResponse, error)
+	QueryObject(context.Context, *QueryRequest) (*QueryResponse, error)
+	PutObject(context.Context, *PutRequest) (*PutResponse, error)
+	PatchObject(context.Context, *PatchRequest) (*PutResponse, error)
+	DeleteObject(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	TransferOwnership(context.Context, *TransferRequest) (*PutResponse, error)
+	SetACL(context.Context, *SetACLRequest) (*PutResponse, error)
+	SetVisibility(context.Context, *SetVisibilityRequest) (*PutResponse, error)
+	GetObjectHistory(context.Context, *HistoryRequest) (*HistoryResponse, error)
+	BatchGetObjects(context.Context, *BatchGetRequest) (*BatchGetResponse, error)
+	BatchPutObjects(context.Context, *BatchPutRequest) (*BatchPutResponse, error)
+	// Transact commits the operations all-or-nothing. If any of them fails,
+	// nothing is written and the error message has the index of the failed operation.
+	Transact(context.Context, *TransactRequest) (*TransactResponse, error)
+	SetSchema(context.Context, *SetSchemaRequest) (*Schema, error)
+	GetSchema(context.Context, *GetSchemaRequest) (*Schema, error)
+	ListSchemas(context.Context, *ListSchemasRequest) (*ListSchemasResponse, error)
+	GetUsage(context.Context, *GetUsageRequest) (*Usage, error)
+	Deposit(context.Context, *DepositRequest) (*Usage, error)
+}
+
+func RegisterAPIServer(s *grpc.Server, srv APIServer) {
+	s.RegisterService(&_API_serviceDesc, srv)
+}
+
+func _API_GetObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/GetObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetObject(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_QueryObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).QueryObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/QueryObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).QueryObject(ctx, req.(*QueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_PutObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).PutObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/PutObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).PutObject(ctx, req.(*PutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_PatchObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).PatchObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/PatchObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).PatchObject(ctx, req.(*PatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_DeleteObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).DeleteObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/DeleteObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).DeleteObject(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_TransferOwnership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).TransferOwnership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/TransferOwnership",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).TransferOwnership(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_SetACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetACLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).SetACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/SetACL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).SetACL(ctx, req.(*SetACLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_SetVisibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVisibilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).SetVisibility(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/SetVisibility",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).SetVisibility(ctx, req.(*SetVisibilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_GetObjectHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetObjectHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/GetObjectHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetObjectHistory(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_BatchGetObjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).BatchGetObjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/BatchGetObjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).BatchGetObjects(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_BatchPutObjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchPutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).BatchPutObjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/BatchPutObjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).BatchPutObjects(ctx, req.(*BatchPutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_Transact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).Transact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/Transact",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Transact(ctx, req.(*TransactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_SetSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).SetSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/SetSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).SetSchema(ctx, req.(*SetSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_GetSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/GetSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetSchema(ctx, req.(*GetSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_ListSchemas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchemasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ListSchemas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/ListSchemas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ListSchemas(ctx, req.(*ListSchemasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/GetUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_Deposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).Deposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/API/Deposit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Deposit(ctx, req.(*DepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _API_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "API",
+	HandlerType: (*APIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetObject",
+			Handler:    _API_GetObject_Handler,
+		},
+		{
+			MethodName: "QueryObject",
+			Handler:    _API_QueryObject_Handler,
+		},
+		{
+			MethodName: "PutObject",
+			Handler:    _API_PutObject_Handler,
+		},
+		{
+			MethodName: "PatchObject",
+			Handler:    _API_PatchObject_Handler,
+		},
+		{
+			MethodName: "DeleteObject",
+			Handler:    _API_DeleteObject_Handler,
+		},
+		{
+			MethodName: "TransferOwnership",
+			Handler:    _API_TransferOwnership_Handler,
+		},
+		{
+			MethodName: "SetACL",
+			Handler:    _API_SetACL_Handler,
+		},
+		{
+			MethodName: "SetVisibility",
+			Handler:    _API_SetVisibility_Handler,
+		},
+		{
+			MethodName: "GetObjectHistory",
+			Handler:    _API_GetObjectHistory_Handler,
+		},
+		{
+			MethodName: "BatchGetObjects",
+			Handler:    _API_BatchGetObjects_Handler,
+		},
+		{
+			MethodName: "BatchPutObjects",
+			Handler:    _API_BatchPutObjects_Handler,
+		},
+		{
+			MethodName: "Transact",
+			Handler:    _API_Transact_Handler,
+		},
+		{
+			MethodName: "SetSchema",
+			Handler:    _API_SetSchema_Handler,
+		},
+		{
+			MethodName: "GetSchema",
+			Handler:    _API_GetSchema_Handler,
+		},
+		{
+			MethodName: "ListSchemas",
+			Handler:    _API_ListSchemas_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _API_GetUsage_Handler,
+		},
+		{
+			MethodName: "Deposit",
+			Handler:    _API_Deposit_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
-	Metadata: "rpcserver/api.proto",
+	Metadata: "proto/api.proto",
 }
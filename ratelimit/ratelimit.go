@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter limits the rate of requests with a token bucket for each key, such as
+// an address or an IP. Each bucket holds up to burst tokens and refills with rate tokens
+// per second, and every request takes a token from the bucket of the key.
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	lock      sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// New creates a limiter allowing rate requests per second for each key with given burst.
+// It returns nil if the rate is not positive, which allows every request.
+func New(rate float64, burst int) *Limiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:      rate,
+		burst:     float64(burst),
+		buckets:   make(map[string]*bucket),
+		lastPrune: time.Now(),
+	}
+}
+
+// Take takes a token from the bucket of the key. It returns 0 if the request is allowed,
+// or how long the caller should wait until a token is available otherwise.
+func (l *Limiter) Take(key string) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updatedAt: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.updatedAt).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.updatedAt = now
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
+
+// prune removes the buckets which have been refilled, since they are the same with
+// new ones. It runs at most once in the time of refilling a bucket from empty.
+func (l *Limiter) prune(now time.Time) {
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.lastPrune) < refill {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.updatedAt) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPrune = now
+}
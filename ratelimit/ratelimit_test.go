@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// elapse makes the buckets and the last pruning of the limiter older by given duration.
+func elapse(l *Limiter, d time.Duration) {
+	for _, b := range l.buckets {
+		b.updatedAt = b.updatedAt.Add(-d)
+	}
+	l.lastPrune = l.lastPrune.Add(-d)
+}
+
+func TestLimiter_Burst(t *testing.T) {
+	l := New(1, 3)
+	for i := 0; i < 3; i++ {
+		require.Zero(t, l.Take("a"))
+	}
+	retryAfter := l.Take("a")
+	require.True(t, retryAfter > 0 && retryAfter <= time.Second, retryAfter)
+
+	// each key has its own bucket
+	require.Zero(t, l.Take("b"))
+}
+
+func TestLimiter_Refill(t *testing.T) {
+	l := New(2, 2)
+	require.Zero(t, l.Take("a"))
+	require.Zero(t, l.Take("a"))
+	require.NotZero(t, l.Take("a"))
+
+	// 2 tokens are refilled in a second
+	elapse(l, time.Second)
+	require.Zero(t, l.Take("a"))
+	require.Zero(t, l.Take("a"))
+	require.NotZero(t, l.Take("a"))
+
+	// but no more than the burst
+	elapse(l, time.Minute)
+	require.Zero(t, l.Take("a"))
+	require.Zero(t, l.Take("a"))
+	require.NotZero(t, l.Take("a"))
+}
+
+func TestLimiter_Prune(t *testing.T) {
+	l := New(1, 2)
+	require.Zero(t, l.Take("a"))
+	require.Zero(t, l.Take("b"))
+
+	// buckets are not pruned until a bucket can be refilled from empty
+	elapse(l, time.Second)
+	require.Zero(t, l.Take("b"))
+	require.Len(t, l.buckets, 2)
+
+	// then the refilled buckets are removed
+	elapse(l, time.Second)
+	require.Zero(t, l.Take("c"))
+	require.Len(t, l.buckets, 2)
+	require.Contains(t, l.buckets, "b")
+	require.NotContains(t, l.buckets, "a")
+}
+
+func TestLimiter_Nil(t *testing.T) {
+	l := New(0, 10)
+	require.Nil(t, l)
+
+	// nil limiters allow every request
+	for i := 0; i < 100; i++ {
+		require.Zero(t, l.Take("a"))
+	}
+}
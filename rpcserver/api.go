@@ -2,54 +2,144 @@ package rpcserver
 
 import (
 	"context"
+	"fmt"
+	"github.com/airbloc/airframe/auth"
 	"github.com/airbloc/airframe/database"
 	pb "github.com/airbloc/airframe/proto"
 	"github.com/json-iterator/go"
+	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/crypto"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 var (
 	json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+	// errNotSupported is returned by the RPCs of optional capabilities which the backend does not implement.
+	errNotSupported = status.Error(codes.Unimplemented, database.ErrNotSupported.Error())
 )
 
 type API struct {
-	db database.Database
+	db     database.Database
+	config *database.Config
 }
 
-func RegisterV1API(srv *grpc.Server, db database.Database) {
-	api := API{db: db}
+// RegisterV1API registers the API of the backend. Read tokens are verified
+// with the signing domain of the backend, and the admins can read the usage of every owner.
+func RegisterV1API(srv *grpc.Server, db database.Database, config *database.Config) {
+	api := API{db: db, config: config}
 	pb.RegisterAPIServer(srv, &api)
 }
 
 func (api *API) GetObject(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
-	obj, err := api.db.Get(ctx, req.GetType(), req.GetId())
+	reader, err := readerOf(ctx, api.config.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj *database.Object
+	switch {
+	case req.GetRevision() > 0:
+		obj, err = database.GetRevision(ctx, api.db, req.GetType(), req.GetId(), req.GetRevision())
+	case req.GetAt() > 0:
+		obj, err = database.GetAt(ctx, api.db, req.GetType(), req.GetId(), time.Unix(0, int64(req.GetAt())))
+	default:
+		obj, err = api.db.Get(ctx, req.GetType(), req.GetId())
+		if err == database.ErrNotExists && req.GetIncludeDeleted() {
+			return api.getDeleted(ctx, req.GetType(), req.GetId())
+		}
+	}
 	if err != nil {
 		if err == database.ErrNotExists {
 			return nil, status.Error(codes.NotFound, "resource not found")
 		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	if !database.CanRead(obj, reader) {
+		return nil, errReadDenied(reader)
+	}
+	if req.GetRevision() > 0 || req.GetAt() > 0 {
+		// past states are also protected by the current visibility of the object
+		if err := api.checkCurrentRead(ctx, req.GetType(), req.GetId(), reader); err != nil {
+			return nil, err
+		}
+	}
 	return objToGetResponse(obj), nil
 }
 
+// checkCurrentRead returns an error if the reader cannot read the current object.
+// Deleted objects are readable, since their data have been erased.
+func (api *API) checkCurrentRead(ctx context.Context, typ, id string, reader *common.Address) error {
+	current, err := api.db.Get(ctx, typ, id)
+	if err == database.ErrNotExists {
+		return nil
+	} else if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if !database.CanRead(current, reader) {
+		return errReadDenied(reader)
+	}
+	return nil
+}
+
+// getDeleted returns the version of the deleted object, which is needed for re-creating it.
+func (api *API) getDeleted(ctx context.Context, typ, id string) (*pb.GetResponse, error) {
+	version, err := api.db.Version(ctx, typ, id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if version == 0 {
+		return nil, status.Error(codes.NotFound, "resource not found")
+	}
+	return &pb.GetResponse{Version: version, Deleted: true}, nil
+}
+
 func (api *API) QueryObject(ctx context.Context, req *pb.QueryRequest) (*pb.QueryResponse, error) {
+	reader, err := readerOf(ctx, api.config.Domain)
+	if err != nil {
+		return nil, err
+	}
 	q := req.GetQuery()
 	if q == "" {
 		q = "{}"
 	}
-	query, err := database.QueryFromJson(q)
+	query, err := parseQuery(ctx, q)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid query: %s", err.Error())
+	}
+	sort, err := database.SortFromString(req.GetSort(), req.GetOrder())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sort: %s", err.Error())
+	}
+	cursor, err := database.CursorFromString(req.GetCursor(), sort)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid query")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	objects, err := api.db.Query(ctx, req.GetType(), query, int(req.GetSkip()), int(req.GetLimit()))
+	result, err := api.db.Query(ctx, req.GetType(), query, database.QueryOptions{
+		Sort:   sort,
+		Cursor: cursor,
+		Skip:   int(req.GetSkip()),
+		Limit:  int(req.GetLimit()),
+		Reader: reader,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	objects := result.Objects
 	results := make([]*pb.GetResponse, len(objects))
 	for i := 0; i < len(objects); i++ {
 		results[i] = objToGetResponse(objects[i])
 	}
-	return &pb.QueryResponse{Results: results}, nil
+	res := &pb.QueryResponse{Results: results}
+	if result.NextCursor != nil {
+		res.NextCursor = result.NextCursor.String()
+	}
+	return res, nil
 }
 
 func (api *API) PutObject(ctx context.Context, req *pb.PutRequest) (*pb.PutResponse, error) {
@@ -58,33 +148,681 @@ func (api *API) PutObject(ctx context.Context, req *pb.PutRequest) (*pb.PutRespo
 	}
 
 	var data database.Payload
-	if err := json.UnmarshalFromString(req.GetData(), &data); err != nil {
+	if err := unmarshalData(ctx, req.GetData(), &data); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid data: '%s'", req.GetData())
 	}
 
-	result, err := api.db.Put(ctx, req.GetType(), req.GetId(), data, req.Signature)
+	result, err := api.db.Put(ctx, req.GetType(), req.GetId(), data, req.Signature, database.PutOptions{
+		ExpectedVersion: req.GetExpectedVersion(),
+		Private:         req.GetPrivate(),
+		ExpiresAt:       expiryOf(req.GetExpiresAt()),
+	})
 	if err != nil {
-		if err == database.ErrNotAuthorized {
+		if st := rateLimitStatus(ctx, err); st != nil {
+			return nil, st
+		}
+		if isSchemaError(err) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		switch errors.Cause(err) {
+		case database.ErrInvalidID, database.ErrInvalidType, database.ErrInvalidExpiry, database.ErrReservedKey, auth.ErrInvalidSignature:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case database.ErrNotAuthorized:
 			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case database.ErrConflict:
+			return nil, status.Error(codes.Aborted, err.Error())
+		case database.ErrQuotaExceeded:
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
 		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	return &pb.PutResponse{
 		Created: result.Created,
 		FeeUsed: result.FeeUsed,
+		Version: result.Version,
 	}, nil
 }
 
-func objToGetResponse(obj *database.Object) *pb.GetResponse {
-	pub, _ := crypto.DecompressPubkey(obj.Owner[:])
-	ownerAddr := crypto.PubkeyToAddress(*pub)
+func (api *API) PatchObject(ctx context.Context, req *pb.PatchRequest) (*pb.PutResponse, error) {
+	if len(req.Signature) != 65 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signature length: %d", len(req.Signature))
+	}
+	patch := &database.Patch{
+		Type:     database.PatchType(req.GetPatchType()),
+		Document: []byte(req.GetPatch()),
+	}
+	result, err := api.db.Patch(ctx, req.GetType(), req.GetId(), patch, req.Signature, database.PutOptions{
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if err != nil {
+		if st := rateLimitStatus(ctx, err); st != nil {
+			return nil, st
+		}
+		if isSchemaError(err) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		switch errors.Cause(err) {
+		case database.ErrNotExists:
+			return nil, status.Error(codes.NotFound, "resource not found")
+		case auth.ErrInvalidSignature:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case database.ErrNotAuthorized:
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case database.ErrConflict:
+			return nil, status.Error(codes.Aborted, err.Error())
+		case database.ErrQuotaExceeded:
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case database.ErrInvalidPatch, database.ErrReservedKey:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.PutResponse{
+		Created: result.Created,
+		FeeUsed: result.FeeUsed,
+		Version: result.Version,
+	}, nil
+}
+
+func (api *API) DeleteObject(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	if len(req.Signature) != 65 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signature length: %d", len(req.Signature))
+	}
+	if err := api.db.Delete(ctx, req.GetType(), req.GetId(), req.Signature); err != nil {
+		if st := rateLimitStatus(ctx, err); st != nil {
+			return nil, st
+		}
+		switch errors.Cause(err) {
+		case database.ErrNotExists:
+			return nil, status.Error(codes.NotFound, "resource not found")
+		case auth.ErrInvalidSignature:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case database.ErrNotAuthorized:
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case database.ErrQuotaExceeded:
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.DeleteResponse{}, nil
+}
+
+func (api *API) TransferOwnership(ctx context.Context, req *pb.TransferRequest) (*pb.PutResponse, error) {
+	if len(req.Signature) != 65 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signature length: %d", len(req.Signature))
+	}
+	if len(req.RecipientSignature) != 0 && len(req.RecipientSignature) != 65 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid recipient signature length: %d", len(req.RecipientSignature))
+	}
+	if !common.IsHexAddress(req.GetNewOwner()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid new owner: '%s'", req.GetNewOwner())
+	}
+	transfer := &database.Transfer{
+		To:                 common.HexToAddress(req.GetNewOwner()),
+		RecipientSignature: req.RecipientSignature,
+	}
+	if len(req.NewOwnerKey) > 0 {
+		if len(req.NewOwnerKey) != len(transfer.NewOwner) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid new owner key length: %d", len(req.NewOwnerKey))
+		}
+		copy(transfer.NewOwner[:], req.NewOwnerKey)
+	}
+
+	result, err := api.db.TransferOwnership(ctx, req.GetType(), req.GetId(), transfer, req.Signature, database.PutOptions{
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if err != nil {
+		if st := rateLimitStatus(ctx, err); st != nil {
+			return nil, st
+		}
+		switch errors.Cause(err) {
+		case database.ErrNotExists:
+			return nil, status.Error(codes.NotFound, "resource not found")
+		case auth.ErrInvalidSignature:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case database.ErrNotAuthorized:
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case database.ErrConflict:
+			return nil, status.Error(codes.Aborted, err.Error())
+		case database.ErrQuotaExceeded:
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case database.ErrInvalidRecipient:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.PutResponse{
+		Created: result.Created,
+		FeeUsed: result.FeeUsed,
+		Version: result.Version,
+	}, nil
+}
+
+func (api *API) SetACL(ctx context.Context, req *pb.SetACLRequest) (*pb.PutResponse, error) {
+	if len(req.Signature) != 65 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signature length: %d", len(req.Signature))
+	}
+	acl := new(database.ACL)
+	for _, writer := range req.GetWriters() {
+		if !common.IsHexAddress(writer) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid writer: '%s'", writer)
+		}
+		acl.Writers = append(acl.Writers, common.HexToAddress(writer))
+	}
+	for _, reader := range req.GetReaders() {
+		if !common.IsHexAddress(reader) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid reader: '%s'", reader)
+		}
+		acl.Readers = append(acl.Readers, common.HexToAddress(reader))
+	}
+
+	result, err := api.db.SetACL(ctx, req.GetType(), req.GetId(), acl, req.Signature, database.PutOptions{
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if err != nil {
+		if st := rateLimitStatus(ctx, err); st != nil {
+			return nil, st
+		}
+		switch errors.Cause(err) {
+		case database.ErrNotExists:
+			return nil, status.Error(codes.NotFound, "resource not found")
+		case auth.ErrInvalidSignature:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case database.ErrNotAuthorized:
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case database.ErrConflict:
+			return nil, status.Error(codes.Aborted, err.Error())
+		case database.ErrQuotaExceeded:
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case database.ErrACLTooLarge:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.PutResponse{
+		Created: result.Created,
+		FeeUsed: result.FeeUsed,
+		Version: result.Version,
+	}, nil
+}
+
+func (api *API) SetVisibility(ctx context.Context, req *pb.SetVisibilityRequest) (*pb.PutResponse, error) {
+	if len(req.Signature) != 65 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signature length: %d", len(req.Signature))
+	}
+	result, err := api.db.SetVisibility(ctx, req.GetType(), req.GetId(), req.GetPrivate(), req.Signature, database.PutOptions{
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if err != nil {
+		if st := rateLimitStatus(ctx, err); st != nil {
+			return nil, st
+		}
+		switch errors.Cause(err) {
+		case database.ErrNotExists:
+			return nil, status.Error(codes.NotFound, "resource not found")
+		case auth.ErrInvalidSignature:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case database.ErrNotAuthorized:
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case database.ErrConflict:
+			return nil, status.Error(codes.Aborted, err.Error())
+		case database.ErrQuotaExceeded:
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.PutResponse{
+		Created: result.Created,
+		FeeUsed: result.FeeUsed,
+		Version: result.Version,
+	}, nil
+}
+
+func (api *API) GetObjectHistory(ctx context.Context, req *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	reader, err := readerOf(ctx, api.config.Domain)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.checkCurrentRead(ctx, req.GetType(), req.GetId(), reader); err != nil {
+		return nil, err
+	}
+	revisions, err := api.db.History(ctx, req.GetType(), req.GetId())
+	if err != nil {
+		if err == database.ErrNotExists {
+			return nil, status.Error(codes.NotFound, "resource not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &pb.HistoryResponse{Revisions: make([]*pb.Revision, len(revisions))}
+	for i, rev := range revisions {
+		data, _ := json.MarshalToString(rev.Data)
+		res.Revisions[i] = &pb.Revision{
+			Version:   rev.Version,
+			Data:      data,
+			Owner:     pubkeyToAddress(rev.Owner),
+			Signer:    pubkeyToAddress(rev.Signer),
+			Signature: rev.Signature,
+			Timestamp: uint64(rev.LastUpdatedAt.UnixNano()),
+			Deleted:   rev.Deleted,
+			ExpiresAt: unixNanoOf(rev.ExpiresAt),
+		}
+		if rev.Transfer != nil {
+			res.Revisions[i].Transferred = true
+			res.Revisions[i].RecipientSignature = rev.Transfer.RecipientSignature
+		}
+	}
+	return res, nil
+}
+
+func (api *API) BatchGetObjects(ctx context.Context, req *pb.BatchGetRequest) (*pb.BatchGetResponse, error) {
+	batcher, ok := api.db.(database.Batcher)
+	if !ok {
+		return nil, errNotSupported
+	}
+	reader, err := readerOf(ctx, api.config.Domain)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]database.ObjectKey, len(req.GetKeys()))
+	for i, key := range req.GetKeys() {
+		keys[i] = database.ObjectKey{Type: key.GetType(), ID: key.GetId()}
+	}
+	results, err := batcher.BatchGet(ctx, keys, req.GetIncludeDeleted())
+	if err != nil {
+		if errors.Cause(err) == database.ErrNotSupported {
+			return nil, errNotSupported
+		}
+		return nil, batchErrorToStatus(err)
+	}
+	res := &pb.BatchGetResponse{Results: make([]*pb.BatchGetResult, len(results))}
+	for i, result := range results {
+		if result.Err != nil {
+			res.Results[i] = &pb.BatchGetResult{Error: itemErrorOf(result.Err)}
+			continue
+		}
+		if !database.CanRead(result.Object, reader) {
+			st, _ := status.FromError(errReadDenied(reader))
+			res.Results[i] = &pb.BatchGetResult{Error: &pb.ItemError{Code: uint32(st.Code()), Message: st.Message()}}
+			continue
+		}
+		res.Results[i] = &pb.BatchGetResult{Object: objToGetResponse(result.Object)}
+	}
+	return res, nil
+}
+
+func (api *API) BatchPutObjects(ctx context.Context, req *pb.BatchPutRequest) (*pb.BatchPutResponse, error) {
+	batcher, ok := api.db.(database.Batcher)
+	if !ok {
+		return nil, errNotSupported
+	}
+	res := &pb.BatchPutResponse{Results: make([]*pb.BatchPutResult, len(req.GetItems()))}
+
+	// malformed items fail without being sent to the database
+	var items []database.PutItem
+	var indices []int
+	for i, item := range req.GetItems() {
+		if len(item.Signature) != 65 {
+			res.Results[i] = &pb.BatchPutResult{Error: &pb.ItemError{
+				Code:    uint32(codes.InvalidArgument),
+				Message: fmt.Sprintf("invalid signature length: %d", len(item.Signature)),
+			}}
+			continue
+		}
+		var data database.Payload
+		if err := unmarshalData(ctx, item.GetData(), &data); err != nil {
+			res.Results[i] = &pb.BatchPutResult{Error: &pb.ItemError{
+				Code:    uint32(codes.InvalidArgument),
+				Message: fmt.Sprintf("invalid data: '%s'", item.GetData()),
+			}}
+			continue
+		}
+		items = append(items, database.PutItem{
+			ObjectKey: database.ObjectKey{Type: item.GetType(), ID: item.GetId()},
+			Data:      data,
+			Signature: item.Signature,
+			Options: database.PutOptions{
+				ExpectedVersion: item.GetExpectedVersion(),
+				Private:         item.GetPrivate(),
+				ExpiresAt:       expiryOf(item.GetExpiresAt()),
+			},
+		})
+		indices = append(indices, i)
+	}
+	if len(items) == 0 {
+		return res, nil
+	}
+
+	results, err := batcher.BatchPut(ctx, items)
+	if err != nil {
+		if errors.Cause(err) == database.ErrNotSupported {
+			return nil, errNotSupported
+		}
+		return nil, batchErrorToStatus(err)
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			res.Results[indices[i]] = &pb.BatchPutResult{Error: itemErrorOf(result.Err)}
+			continue
+		}
+		res.Results[indices[i]] = &pb.BatchPutResult{Result: &pb.PutResponse{
+			Created: result.Result.Created,
+			FeeUsed: result.Result.FeeUsed,
+			Version: result.Result.Version,
+		}}
+	}
+	return res, nil
+}
+
+func (api *API) Transact(ctx context.Context, req *pb.TransactRequest) (*pb.TransactResponse, error) {
+	transactor, ok := api.db.(database.Transactor)
+	if !ok {
+		return nil, errNotSupported
+	}
+	ops := make([]database.TxOp, len(req.GetOps()))
+	for i, op := range req.GetOps() {
+		ops[i] = database.TxOp{
+			ObjectKey: database.ObjectKey{Type: op.GetType(), ID: op.GetId()},
+			Op:        database.TxOpType(op.GetOp()),
+			Signature: op.GetSignature(),
+			Options: database.PutOptions{
+				ExpectedVersion: op.GetExpectedVersion(),
+				Private:         op.GetPrivate(),
+				ExpiresAt:       expiryOf(op.GetExpiresAt()),
+			},
+		}
+		if ops[i].Op == database.TxCheck {
+			continue
+		}
+		if len(op.Signature) != 65 {
+			return nil, status.Errorf(codes.InvalidArgument, "operation %d: invalid signature length: %d", i, len(op.Signature))
+		}
+		if ops[i].Op == database.TxPut {
+			if err := unmarshalData(ctx, op.GetData(), &ops[i].Data); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "operation %d: invalid data: '%s'", i, op.GetData())
+			}
+		}
+	}
+
+	results, err := transactor.Transact(ctx, ops)
+	if err != nil {
+		if errors.Cause(err) == database.ErrNotSupported {
+			return nil, errNotSupported
+		}
+		if st := rateLimitStatus(ctx, err); st != nil {
+			return nil, st
+		}
+		code := codes.Internal
+		switch errors.Cause(err) {
+		case database.ErrTxTooLarge, database.ErrDuplicatedKey, database.ErrInvalidTxOp, database.ErrInvalidID, database.ErrInvalidType, database.ErrInvalidExpiry, database.ErrReservedKey, auth.ErrInvalidSignature:
+			code = codes.InvalidArgument
+		case database.ErrNotExists:
+			code = codes.NotFound
+		case database.ErrNotAuthorized:
+			code = codes.Unauthenticated
+		case database.ErrConflict:
+			code = codes.Aborted
+		case database.ErrQuotaExceeded:
+			code = codes.ResourceExhausted
+		}
+		if isSchemaError(err) {
+			code = codes.InvalidArgument
+		}
+		return nil, status.Error(code, err.Error())
+	}
+	res := &pb.TransactResponse{Results: make([]*pb.PutResponse, len(results))}
+	for i, result := range results {
+		if result == nil {
+			res.Results[i] = &pb.PutResponse{}
+			continue
+		}
+		res.Results[i] = &pb.PutResponse{
+			Created: result.Created,
+			FeeUsed: result.FeeUsed,
+			Version: result.Version,
+		}
+	}
+	return res, nil
+}
+
+// batchErrorToStatus returns a gRPC status of the error failing the whole batch.
+func batchErrorToStatus(err error) error {
+	switch errors.Cause(err) {
+	case database.ErrBatchTooLarge, database.ErrDuplicatedKey:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
 
+// itemErrorOf returns an error of an item in a batch, with the same code as the single-object RPCs.
+func itemErrorOf(err error) *pb.ItemError {
+	code := codes.Internal
+	msg := err.Error()
+	switch errors.Cause(err) {
+	case database.ErrNotExists:
+		code, msg = codes.NotFound, "resource not found"
+	case database.ErrInvalidID, database.ErrInvalidType, database.ErrInvalidExpiry, database.ErrReservedKey, auth.ErrInvalidSignature:
+		code = codes.InvalidArgument
+	case database.ErrNotAuthorized:
+		code = codes.Unauthenticated
+	case database.ErrConflict:
+		code = codes.Aborted
+	case database.ErrQuotaExceeded:
+		code = codes.ResourceExhausted
+	}
+	if isSchemaError(err) {
+		code = codes.InvalidArgument
+	}
+	if limitErr, ok := errors.Cause(err).(*database.RateLimitError); ok {
+		return &pb.ItemError{
+			Code:       uint32(codes.ResourceExhausted),
+			Message:    msg,
+			RetryAfter: uint64(database.RetryAfterSeconds(limitErr.RetryAfter)),
+		}
+	}
+	return &pb.ItemError{Code: uint32(code), Message: msg}
+}
+
+// isSchemaError returns true if the error is caused by data violating the schema of the type.
+func isSchemaError(err error) bool {
+	_, ok := errors.Cause(err).(*database.SchemaError)
+	return ok
+}
+
+func (api *API) SetSchema(ctx context.Context, req *pb.SetSchemaRequest) (*pb.Schema, error) {
+	registry, ok := api.db.(database.SchemaRegistry)
+	if !ok {
+		return nil, errNotSupported
+	}
+	if len(req.Signature) != 65 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signature length: %d", len(req.Signature))
+	}
+	schema, err := registry.SetSchema(ctx, req.GetType(), req.GetDefinition(), req.Signature, database.PutOptions{
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if err != nil {
+		if errors.Cause(err) == database.ErrNotSupported {
+			return nil, errNotSupported
+		}
+		switch errors.Cause(err) {
+		case database.ErrInvalidSchema, auth.ErrInvalidSignature:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case database.ErrNotAuthorized:
+			return nil, status.Error(codes.Unauthenticated, "only admins can set schemas")
+		case database.ErrConflict:
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return schemaToProto(schema), nil
+}
+
+func (api *API) GetSchema(ctx context.Context, req *pb.GetSchemaRequest) (*pb.Schema, error) {
+	registry, ok := api.db.(database.SchemaRegistry)
+	if !ok {
+		return nil, errNotSupported
+	}
+	schema, err := registry.GetSchema(ctx, req.GetType(), req.GetVersion())
+	if err != nil {
+		if errors.Cause(err) == database.ErrNotSupported {
+			return nil, errNotSupported
+		}
+		if err == database.ErrNotExists {
+			return nil, status.Error(codes.NotFound, "schema not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return schemaToProto(schema), nil
+}
+
+func (api *API) ListSchemas(ctx context.Context, req *pb.ListSchemasRequest) (*pb.ListSchemasResponse, error) {
+	registry, ok := api.db.(database.SchemaRegistry)
+	if !ok {
+		return nil, errNotSupported
+	}
+	schemas, err := registry.ListSchemas(ctx)
+	if err != nil {
+		if errors.Cause(err) == database.ErrNotSupported {
+			return nil, errNotSupported
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &pb.ListSchemasResponse{Schemas: make([]*pb.Schema, len(schemas))}
+	for i, schema := range schemas {
+		res.Schemas[i] = schemaToProto(schema)
+	}
+	return res, nil
+}
+
+// GetUsage returns the usage of the owner, which can be read only by the owner and the admins.
+func (api *API) GetUsage(ctx context.Context, req *pb.GetUsageRequest) (*pb.Usage, error) {
+	meter, ok := api.db.(database.Meter)
+	if !ok {
+		return nil, errNotSupported
+	}
+	if !common.IsHexAddress(req.GetOwner()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid owner: '%s'", req.GetOwner())
+	}
+	owner := common.HexToAddress(req.GetOwner())
+	reader, err := readerOf(ctx, api.config.Domain)
+	if err != nil {
+		return nil, err
+	}
+	if reader == nil || (*reader != owner && !api.config.IsAdmin(*reader)) {
+		return nil, errReadDenied(reader)
+	}
+	usage, err := meter.Usage(ctx, owner)
+	if err != nil {
+		if errors.Cause(err) == database.ErrNotSupported {
+			return nil, errNotSupported
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return usageToProto(usage), nil
+}
+
+func (api *API) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.Usage, error) {
+	meter, ok := api.db.(database.Meter)
+	if !ok {
+		return nil, errNotSupported
+	}
+	if len(req.Signature) != 65 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid signature length: %d", len(req.Signature))
+	}
+	if !common.IsHexAddress(req.GetOwner()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid owner: '%s'", req.GetOwner())
+	}
+	usage, err := meter.Deposit(ctx, common.HexToAddress(req.GetOwner()), req.GetAmount(), req.Signature)
+	if err != nil {
+		if errors.Cause(err) == database.ErrNotSupported {
+			return nil, errNotSupported
+		}
+		switch errors.Cause(err) {
+		case auth.ErrInvalidSignature:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case database.ErrNotAuthorized:
+			return nil, status.Error(codes.Unauthenticated, "only admins can deposit")
+		case database.ErrConflict:
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return usageToProto(usage), nil
+}
+
+func usageToProto(usage *database.Usage) *pb.Usage {
+	return &pb.Usage{
+		Owner:     usage.Owner.Hex(),
+		Writes:    usage.Writes,
+		FeeUsed:   usage.FeeUsed,
+		Deposited: usage.Deposited,
+		Deposits:  usage.Deposits,
+		Balance:   usage.Balance(),
+	}
+}
+
+func schemaToProto(schema *database.Schema) *pb.Schema {
+	return &pb.Schema{
+		Type:       schema.Type,
+		Version:    schema.Version,
+		Definition: schema.Definition,
+		Signer:     schema.Signer.Hex(),
+		CreatedAt:  uint64(schema.CreatedAt.UnixNano()),
+	}
+}
+
+func objToGetResponse(obj *database.Object) *pb.GetResponse {
+	if !database.IsLive(obj) {
+		// tombstones only retain the version, and expired objects are shown as tombstones
+		return &pb.GetResponse{Version: obj.Version, Deleted: true}
+	}
 	data, _ := json.MarshalToString(obj.Data)
-	return &pb.GetResponse{
-		Data:  data,
-		Owner: ownerAddr.Hex(),
+	res := &pb.GetResponse{
+		Data:    data,
+		Owner:   pubkeyToAddress(obj.Owner),
+		Version: obj.Version,
+		Private: obj.Private,
 
+		ExpiresAt:     unixNanoOf(obj.ExpiresAt),
 		CreatedAt:     uint64(obj.CreatedAt.UnixNano()),
 		LastUpdatedAt: uint64(obj.LastUpdatedAt.UnixNano()),
 	}
+	if obj.ACL != nil {
+		res.Writers = addressesToHex(obj.ACL.Writers)
+		res.Readers = addressesToHex(obj.ACL.Readers)
+	}
+	return res
+}
+
+// expiryOf returns the expiry of given unix nanoseconds, which is zero for 0.
+func expiryOf(nanos uint64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(nanos))
+}
+
+// unixNanoOf returns given time in unix nanoseconds, or 0 for the zero time.
+func unixNanoOf(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+func addressesToHex(addrs []common.Address) []string {
+	hexes := make([]string, len(addrs))
+	for i, addr := range addrs {
+		hexes[i] = addr.Hex()
+	}
+	return hexes
+}
+
+// pubkeyToAddress returns the address of given public key in hex,
+// or an empty string for the empty key of tombstones.
+func pubkeyToAddress(key auth.PublicKey) string {
+	pub, err := crypto.DecompressPubkey(key[:])
+	if err != nil {
+		return ""
+	}
+	return crypto.PubkeyToAddress(*pub).Hex()
 }
@@ -0,0 +1,115 @@
+package rpcserver
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/database/databasetest"
+	pb "github.com/airbloc/airframe/proto"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+var sign = databasetest.Sign
+
+// newTestAPI returns the API of a new in-memory database.
+func newTestAPI(t *testing.T) *API {
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+	return &API{db: db, config: database.NewConfig()}
+}
+
+func TestAPI_PutObject_InvalidSignature(t *testing.T) {
+	api := newTestAPI(t)
+
+	// no public key can be recovered from a zero signature
+	_, err := api.PutObject(context.TODO(), &pb.PutRequest{
+		Type:      "testdata",
+		Id:        "1",
+		Data:      `{"foo": "bar"}`,
+		Signature: make([]byte, 65),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
+
+func TestAPI_PutObject_InvalidID(t *testing.T) {
+	api := newTestAPI(t)
+	priv, _ := crypto.GenerateKey()
+
+	data := database.Payload{"foo": "bar"}
+	_, err := api.PutObject(context.TODO(), &pb.PutRequest{
+		Type:      "testdata",
+		Id:        "1/1",
+		Data:      `{"foo": "bar"}`,
+		Signature: sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1/1", 1, data)),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
+
+func TestAPI_DeleteObject_InvalidSignature(t *testing.T) {
+	ctx := context.TODO()
+	api := newTestAPI(t)
+	priv, _ := crypto.GenerateKey()
+	data := database.Payload{"foo": "bar"}
+	_, err := api.db.Put(ctx, "testdata", "1", data, sign(t, priv, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, data)), database.PutOptions{})
+	require.NoError(t, err)
+
+	_, err = api.DeleteObject(ctx, &pb.DeleteRequest{Type: "testdata", Id: "1", Signature: make([]byte, 65)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	// signatures of others are recovered, but they are not authorized
+	other, _ := crypto.GenerateKey()
+	_, err = api.DeleteObject(ctx, &pb.DeleteRequest{
+		Type:      "testdata",
+		Id:        "1",
+		Signature: sign(t, other, auth.GetDeleteHash(auth.DefaultDomain, "testdata", "1", 2)),
+	})
+	require.Equal(t, codes.Unauthenticated, status.Code(err), err)
+}
+
+func TestAPI_NotSupported(t *testing.T) {
+	imdb, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+
+	// the backend only implements database.Database
+	api := &API{db: struct{ database.Database }{imdb}, config: database.NewConfig()}
+	_, err = api.BatchGetObjects(context.TODO(), &pb.BatchGetRequest{
+		Keys: []*pb.ObjectKey{{Type: "testdata", Id: "1"}},
+	})
+	require.Equal(t, codes.Unimplemented, status.Code(err), err)
+}
+
+func TestAPI_GetObject_Private(t *testing.T) {
+	api := newTestAPI(t)
+	owner, _ := crypto.GenerateKey()
+	ctx := context.TODO()
+
+	data := database.Payload{"foo": "bar"}
+	_, err := api.db.Put(ctx, "testdata", "1", data, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "1", 1, data)), database.PutOptions{Private: true})
+	require.NoError(t, err)
+	public := database.Payload{"foo": "baz"}
+	_, err = api.db.Put(ctx, "testdata", "2", public, sign(t, owner, auth.GetObjectHash(auth.DefaultDomain, "testdata", "2", 1, public)), database.PutOptions{})
+	require.NoError(t, err)
+
+	// anonymous readers can neither get nor find the private object
+	_, err = api.GetObject(ctx, &pb.GetRequest{Type: "testdata", Id: "1"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err), err)
+	_, err = api.GetObjectHistory(ctx, &pb.HistoryRequest{Type: "testdata", Id: "1"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err), err)
+	res, err := api.QueryObject(ctx, &pb.QueryRequest{Type: "testdata"})
+	require.NoError(t, err)
+	require.Len(t, res.GetResults(), 1)
+	require.JSONEq(t, `{"foo": "baz"}`, res.GetResults()[0].GetData())
+
+	// the owner can read it with a read token
+	token, err := auth.NewReadToken(auth.DefaultDomain, owner, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	authCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+	_, err = api.GetObject(authCtx, &pb.GetRequest{Type: "testdata", Id: "1"})
+	require.NoError(t, err)
+}
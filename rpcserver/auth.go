@@ -0,0 +1,35 @@
+package rpcserver
+
+import (
+	"context"
+	"github.com/airbloc/airframe/auth"
+	"github.com/klaytn/klaytn/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+// readerOf returns the address of the reader from the read token for the domain in the metadata,
+// or nil if no token is given.
+func readerOf(ctx context.Context, domain string) (*common.Address, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("authorization")) == 0 {
+		return nil, nil
+	}
+	token := strings.TrimPrefix(md.Get("authorization")[0], "Bearer ")
+	reader, err := auth.VerifyReadToken(domain, token, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return &reader, nil
+}
+
+// errReadDenied returns an error for reading a private object which the reader cannot read.
+func errReadDenied(reader *common.Address) error {
+	if reader == nil {
+		return status.Error(codes.Unauthenticated, "a read token is required to read the private object")
+	}
+	return status.Error(codes.PermissionDenied, "you're not authorized to read the object")
+}
@@ -0,0 +1,65 @@
+package rpcserver
+
+import (
+	"context"
+	"github.com/airbloc/airframe/database"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"time"
+)
+
+const (
+	// healthCheckInterval is the interval of pinging the database to update the serving status.
+	healthCheckInterval = 10 * time.Second
+
+	// healthCheckTimeout is the timeout of pinging the database.
+	healthCheckTimeout = 5 * time.Second
+)
+
+// apiServiceName is the name of the API service in the health service.
+const apiServiceName = "API"
+
+// RegisterHealthService registers the standard grpc.health.v1 service. The serving status of
+// the server ("") and the API service is NOT_SERVING until the database is pinged by WatchHealth.
+func RegisterHealthService(srv *grpc.Server) *health.Server {
+	hs := health.NewServer()
+	for _, service := range []string{"", apiServiceName} {
+		hs.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	healthpb.RegisterHealthServer(srv, hs)
+	return hs
+}
+
+// WatchHealth pings the database every interval and updates the serving status,
+// until the context is canceled.
+func WatchHealth(ctx context.Context, hs *health.Server, db database.Database, interval time.Duration) {
+	pinger, ok := db.(database.Pinger)
+	if !ok {
+		// the backend is always ready
+		for _, service := range []string{"", apiServiceName} {
+			hs.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+		}
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		servingStatus := healthpb.HealthCheckResponse_SERVING
+		pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		if err := pinger.Ping(pingCtx); err != nil {
+			log.Error("failed to ping database", err)
+			servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		cancel()
+		for _, service := range []string{"", apiServiceName} {
+			hs.SetServingStatus(service, servingStatus)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package rpcserver
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+var grpcRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "airframe",
+	Subsystem: "grpc",
+	Name:      "request_duration_seconds",
+	Help:      "Latencies of gRPC calls by method and status code.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "code"})
+
+func init() {
+	prometheus.MustRegister(grpcRequestDuration)
+}
+
+// UnaryMetricsInterceptor observes the latency of every call by method and status code.
+func UnaryMetricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		grpcRequestDuration.
+			WithLabelValues(info.FullMethod, status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+		return res, err
+	}
+}
@@ -0,0 +1,99 @@
+package rpcserver
+
+import (
+	"context"
+	"github.com/airbloc/airframe/database"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// readMethods are the RPCs which only read objects.
+var readMethods = map[string]bool{
+	"/API/GetObject":        true,
+	"/API/QueryObject":      true,
+	"/API/GetObjectHistory": true,
+	"/API/BatchGetObjects":  true,
+	"/API/GetSchema":        true,
+	"/API/ListSchemas":      true,
+	"/API/GetUsage":         true,
+}
+
+// UnaryRateLimitInterceptor limits the rate of reads by the API key in "x-api-key" metadata if it is
+// one of the API keys, or the IP of the client otherwise. Writes are limited by the IP of the client with
+// the write limiter before their signatures are recovered, and then by the signer in the database since
+// the signer is recovered with the current version of the object. See database.WithWriteLimiter.
+// Nil limiters allow every request, and RPCs of other services such as the health service are not limited.
+func UnaryRateLimitInterceptor(readLimiter, writeLimiter database.RateLimiter, apiKeys map[string]bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, "/API/") {
+			return handler(ctx, req)
+		}
+		limiter, key := writeLimiter, peerIPOf(ctx)
+		if readMethods[info.FullMethod] {
+			limiter, key = readLimiter, clientKeyOf(ctx, apiKeys)
+		}
+		if limiter == nil {
+			return handler(ctx, req)
+		}
+		if retryAfter := limiter.Take(key); retryAfter > 0 {
+			return nil, rateLimitedStatus(ctx, retryAfter, "too many requests")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// clientKeyOf returns the API key in the metadata if it is one of the API keys, or the IP of the client.
+func clientKeyOf(ctx context.Context, apiKeys map[string]bool) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-api-key")) > 0 && apiKeys[md.Get("x-api-key")[0]] {
+		return md.Get("x-api-key")[0]
+	}
+	return peerIPOf(ctx)
+}
+
+// peerIPOf returns the IP of the client.
+func peerIPOf(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// rateLimitStatus returns a RESOURCE_EXHAUSTED status with a retry hint if the signer
+// of the write has exceeded the rate limit, or nil otherwise.
+func rateLimitStatus(ctx context.Context, err error) error {
+	limitErr, ok := errors.Cause(err).(*database.RateLimitError)
+	if !ok {
+		return nil
+	}
+	return rateLimitedStatus(ctx, limitErr.RetryAfter, err.Error())
+}
+
+// rateLimitedStatus returns a RESOURCE_EXHAUSTED status with a RetryInfo detail,
+// and sets "retry-after" header in seconds for clients which cannot read the detail.
+func rateLimitedStatus(ctx context.Context, retryAfter time.Duration, msg string) error {
+	seconds := database.RetryAfterSeconds(retryAfter)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.FormatInt(seconds, 10)))
+
+	st := status.New(codes.ResourceExhausted, msg)
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(time.Duration(seconds) * time.Second),
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -1,10 +1,12 @@
 package rpcserver
 
 import (
+	"context"
 	"fmt"
 	"github.com/airbloc/airframe/database"
 	"github.com/airbloc/logger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
 	"net"
 )
 
@@ -15,14 +17,43 @@ var (
 type Server struct {
 	srv  *grpc.Server
 	port string
+
+	health      *health.Server
+	stopWatcher context.CancelFunc
+}
+
+// Options are the options of the server.
+type Options struct {
+	// Config is the configuration of the backend. Read tokens are verified with its signing domain,
+	// and its admins can read the usage of every owner.
+	Config *database.Config
+
+	// ReadLimiter limits the rate of reads by the API key or the IP of the client,
+	// and WriteLimiter limits the rate of writes by the IP. Nil limiters allow every request.
+	ReadLimiter  database.RateLimiter
+	WriteLimiter database.RateLimiter
+
+	// APIKeys are the API keys which reads are limited by. Reads with other keys are limited by the IP.
+	APIKeys map[string]bool
 }
 
-func New(backend database.Database, port int, debug bool) *Server {
-	srv := grpc.NewServer()
-	RegisterV1API(srv, backend)
+// New creates a gRPC server of the backend with given options.
+func New(backend database.Database, port int, debug bool, options Options) *Server {
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		UnaryTracingInterceptor(),
+		UnaryMetricsInterceptor(),
+		UnaryRateLimitInterceptor(options.ReadLimiter, options.WriteLimiter, options.APIKeys),
+	))
+	RegisterV1API(srv, backend, options.Config)
+	hs := RegisterHealthService(srv)
+
+	ctx, stopWatcher := context.WithCancel(context.Background())
+	go WatchHealth(ctx, hs, backend, healthCheckInterval)
 	return &Server{
-		srv:  srv,
-		port: fmt.Sprintf(":%d", port),
+		srv:         srv,
+		port:        fmt.Sprintf(":%d", port),
+		health:      hs,
+		stopWatcher: stopWatcher,
 	}
 }
 
@@ -34,6 +65,9 @@ func (s *Server) Start() error {
 	return s.srv.Serve(lis)
 }
 
+// Stop makes the health service NOT_SERVING, and stops the server gracefully.
 func (s *Server) Stop() {
+	s.stopWatcher()
+	s.health.Shutdown()
 	s.srv.GracefulStop()
 }
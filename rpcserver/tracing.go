@@ -0,0 +1,55 @@
+package rpcserver
+
+import (
+	"context"
+	"github.com/airbloc/airframe/database"
+	"github.com/airbloc/airframe/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var tracer = tracing.Tracer("rpcserver")
+
+// UnaryTracingInterceptor starts a span of every call, continuing the trace of the caller in the metadata.
+func UnaryTracingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, tracing.MetadataCarrier(md))
+		ctx, span := tracer.Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.method", info.FullMethod),
+			))
+		defer span.End()
+
+		res, err := handler(ctx, req)
+		st := status.Convert(err)
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(st.Code())))
+		if err != nil {
+			span.SetStatus(otelcodes.Error, st.Message())
+		}
+		return res, err
+	}
+}
+
+// unmarshalData parses the JSON data of an object in a span.
+func unmarshalData(ctx context.Context, raw string, data *database.Payload) error {
+	_, span := tracer.Start(ctx, "rpcserver.unmarshalData", trace.WithAttributes(attribute.Int("data.size", len(raw))))
+	err := json.UnmarshalFromString(raw, data)
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// parseQuery parses the JSON query in a span.
+func parseQuery(ctx context.Context, raw string) (*database.Query, error) {
+	_, span := tracer.Start(ctx, "rpcserver.parseQuery", trace.WithAttributes(attribute.Int("query.size", len(raw))))
+	query, err := database.QueryFromJson(raw)
+	tracing.EndSpan(span, err)
+	return query, err
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// ServiceName is the name of the service reported with the spans.
+const ServiceName = "airframe"
+
+// Options configures the exporter of the spans.
+type Options struct {
+	// Exporter is one of "none", "stdout" and "otlp". Spans are not recorded if it is "none" or empty.
+	Exporter string
+
+	// Endpoint is the URL of the OTLP/HTTP collector such as "http://localhost:4318".
+	// OTEL_EXPORTER_OTLP_ENDPOINT or the default of the exporter is used if it is empty.
+	Endpoint string
+
+	// SampleRatio is the ratio of the traces sampled at the root, between 0 and 1.
+	// Traces continued from the callers follow the sampling decision of the callers.
+	SampleRatio float64
+}
+
+// Setup installs the tracer provider exporting to given exporter, and W3C trace context
+// propagation. The returned function flushes the remaining spans and stops the exporter.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch opts.Exporter {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "otlp":
+		var clientOpts []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, clientOpts...)
+	default:
+		return nil, errors.Errorf("unknown trace exporter %s", opts.Exporter)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s exporter", opts.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of given package. It can be called before Setup,
+// since the global tracer is replaced with the one of the provider on Setup.
+func Tracer(pkg string) trace.Tracer {
+	return otel.Tracer("github.com/airbloc/airframe/" + pkg)
+}
+
+// EndSpan records the error if any, and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// MetadataCarrier propagates trace context through gRPC metadata.
+type MetadataCarrier metadata.MD
+
+func (c MetadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}